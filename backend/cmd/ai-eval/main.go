@@ -0,0 +1,138 @@
+package main
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"weel-backend/config"
+	"weel-backend/internal/aieval"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/logging"
+	"weel-backend/internal/prompt"
+	"weel-backend/internal/service"
+)
+const usage = `Usage: ai-eval [flags] -run NAME=SOURCE [-run NAME=SOURCE ...]
+
+Runs every case in the dataset through each source and prints a comparison.
+
+Sources:
+  replay:FILE       replay responses recorded in a fixture file (no network)
+  openai            call OpenAI with the built-in prompt (needs OPEN_AI_SECRET)
+  openai:TEMPLATE   call OpenAI with a prompt template file (same format as internal/prompt/templates)
+
+Flags:
+`
+type runFlags []string
+func (r *runFlags) String() string {
+	return strings.Join(*r, ",")
+}
+func (r *runFlags) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+type staticPrompt struct {
+	template *domain.PromptTemplate
+}
+func (p staticPrompt) Active(ctx context.Context, name string) (*domain.PromptTemplate, error) {
+	return p.template, nil
+}
+func main() {
+	var runs runFlags
+	datasetPath := flag.String("dataset", "internal/aieval/testdata/dataset.json", "Labelled dataset of order summaries")
+	recordDir := flag.String("record", "", "Directory to save live OpenAI responses to as NAME.json fixtures")
+	format := flag.String("format", "text", "Report format: text or json")
+	failUnder := flag.Float64("fail-under", 0, "Exit with status 1 when any run's pass rate is below this fraction")
+	flag.Var(&runs, "run", "Run to evaluate as NAME=SOURCE (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if len(runs) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dataset, err := aieval.LoadDataset(*datasetPath)
+	if err != nil {
+		log.Fatal("Failed to load dataset:", err)
+	}
+	evaluator, err := aieval.NewEvaluator(dataset)
+	if err != nil {
+		log.Fatal("Failed to create evaluator:", err)
+	}
+	var cfg *config.Config
+	ctx := context.Background()
+	results := make([]*aieval.RunResult, 0, len(runs))
+	for _, spec := range runs {
+		name, source, ok := strings.Cut(spec, "=")
+		if !ok || name == "" {
+			log.Fatalf("Invalid run %q, expected NAME=SOURCE", spec)
+		}
+		kind, arg, _ := strings.Cut(source, ":")
+		var ai service.AIService
+		var recorder *aieval.Recorder
+		switch kind {
+		case "replay":
+			fixture, err := aieval.LoadFixture(arg)
+			if err != nil {
+				log.Fatal("Failed to load fixture:", err)
+			}
+			ai = aieval.NewReplayer(fixture)
+		case "openai":
+			if cfg == nil {
+				if cfg, err = config.Load(); err != nil {
+					log.Fatal("Failed to load config:", err)
+				}
+				logging.Setup(cfg.Log)
+				if cfg.OpenAI.Secret == "" {
+					log.Fatal("OPEN_AI_SECRET is required for openai runs")
+				}
+			}
+			var opts []service.AIServiceOption
+			if arg != "" {
+				data, err := os.ReadFile(arg)
+				if err != nil {
+					log.Fatal("Failed to read prompt template:", err)
+				}
+				template, err := prompt.Parse(prompt.SuggestProducts, 0, data)
+				if err != nil {
+					log.Fatal("Failed to parse prompt template:", err)
+				}
+				opts = append(opts, service.WithPromptSource(staticPrompt{template: template}))
+			}
+			ai = service.NewAIService(cfg, opts...)
+			if *recordDir != "" {
+				recorder = aieval.NewRecorder(name, ai)
+				ai = recorder
+			}
+		default:
+			log.Fatalf("Unknown source %q in run %q", kind, spec)
+		}
+		results = append(results, evaluator.Run(ctx, name, ai))
+		if recorder != nil {
+			path := filepath.Join(*recordDir, name+".json")
+			if err := recorder.Fixture().Save(path); err != nil {
+				log.Fatal("Failed to save fixture:", err)
+			}
+			log.Printf("✅ Recorded %d response(s) to %s", len(dataset.Cases), path)
+		}
+	}
+	switch *format {
+	case "json":
+		err = aieval.WriteJSON(os.Stdout, results)
+	default:
+		err = aieval.WriteText(os.Stdout, dataset, results)
+	}
+	if err != nil {
+		log.Fatal("Failed to write report:", err)
+	}
+	for _, result := range results {
+		if result.Summary.PassRate < *failUnder {
+			log.Printf("❌ %s pass rate %.1f%% is below %.1f%%", result.Name, result.Summary.PassRate*100, *failUnder*100)
+			os.Exit(1)
+		}
+	}
+}
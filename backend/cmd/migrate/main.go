@@ -1,20 +1,116 @@
 package main
 import (
-	"log"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
 	"weel-backend/config"
 	"weel-backend/internal/database"
+	"weel-backend/internal/logging"
+	"weel-backend/internal/migrator"
+	"weel-backend/migrations"
 )
+const usage = `Usage: migrate [flags] <command> [args]
+
+Commands:
+  up            apply all pending migrations
+  down N        roll back the last N applied migrations
+  status        list migrations and whether they have been applied
+  create NAME   write empty up/down files for a new migration
+
+Flags:
+`
 func main() {
+	dir := flag.String("dir", "migrations", "Directory that create writes new migration files to")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		fatal("failed to load config", err)
+	}
+	logging.Setup(cfg.Log)
+	command := args[0]
+	if command == "create" {
+		if len(args) != 2 {
+			slog.Error("create requires a migration name")
+			os.Exit(2)
+		}
+		up, down, err := migrator.Create(*dir, args[1])
+		if err != nil {
+			fatal("failed to create migration", err)
+		}
+		slog.Info("created migration", "up", up, "down", down)
+		return
 	}
 	if err := database.Connect(cfg); err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("failed to connect to database", err)
 	}
 	defer database.Close()
-	if err := database.AutoMigrate(); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		fatal("failed to open database", err)
 	}
-	log.Println("✅ Migrations completed successfully")
+	m, err := migrator.New(sqlDB, migrations.FS, migrator.WithLogger(func(format string, args ...interface{}) {
+		slog.Info(fmt.Sprintf(format, args...), "component", "migrator")
+	}))
+	if err != nil {
+		fatal("failed to load migrations", err)
+	}
+	ctx := context.Background()
+	switch command {
+	case "up":
+		applied, err := m.Up(ctx)
+		if err != nil {
+			fatal("failed to run migrations", err)
+		}
+		slog.Info("migrations applied", "applied", applied)
+	case "down":
+		if len(args) != 2 {
+			slog.Error("down requires the number of migrations to roll back")
+			os.Exit(2)
+		}
+		steps, err := strconv.Atoi(args[1])
+		if err != nil {
+			slog.Error("down requires a numeric step count", "error", err)
+			os.Exit(2)
+		}
+		reverted, err := m.Down(ctx, steps)
+		if err != nil {
+			fatal("failed to roll back migrations", err)
+		}
+		slog.Info("migrations rolled back", "reverted", reverted)
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			fatal("failed to read migration status", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, s.State(), appliedAt)
+		}
+		w.Flush()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -1,9 +1,11 @@
 package main
 import (
 	"flag"
-	"log"
+	"log/slog"
+	"os"
 	"weel-backend/config"
 	"weel-backend/internal/database"
+	"weel-backend/internal/logging"
 	"weel-backend/internal/seed"
 )
 func main() {
@@ -11,29 +13,36 @@ func main() {
 	flag.Parse()
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		fatal("failed to load config", err)
 	}
+	logging.Setup(cfg.Log)
 	if err := database.Connect(cfg); err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("failed to connect to database", err)
 	}
 	defer database.Close()
-	if err := database.AutoMigrate(); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	if err := database.Migrate(); err != nil {
+		fatal("failed to run migrations", err)
 	}
 	if *reset {
-		log.Println("Resetting seed data...")
+		slog.Info("resetting seed data")
 		if err := seed.Reset(database.DB); err != nil {
-			log.Fatal("Failed to reset seed data:", err)
+			fatal("failed to reset seed data", err)
 		}
 	}
 	seeders := []seed.Seeder{
 		seed.NewFeatureFlagSeeder(),
+		seed.NewDeliveryZoneSeeder(),
+		seed.NewStoreSeeder(),
 		seed.NewUserSeeder(),
 		seed.NewOrderSeeder(),
 	}
-	log.Println("Starting database seeding...")
+	slog.Info("starting database seeding", "seeders", len(seeders))
 	if err := seed.Run(database.DB, seeders...); err != nil {
-		log.Fatal("Failed to seed database:", err)
+		fatal("failed to seed database", err)
 	}
-	log.Println("✅ Seed data created successfully!")
+	slog.Info("seed data created")
+}
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -1,32 +1,114 @@
 package main
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 	"weel-backend/config"
 	"weel-backend/internal/app"
 	"weel-backend/internal/database"
+	"weel-backend/internal/logging"
+	"weel-backend/internal/metrics"
+	"weel-backend/internal/tracing"
 )
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		fatal("failed to load config", err)
+	}
+	logging.Setup(cfg.Log)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("failed to set up tracing", err)
 	}
 	if err := database.Connect(cfg); err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("failed to connect to database", err)
 	}
-	defer database.Close()
-	if err := database.AutoMigrate(); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	if err := database.Migrate(); err != nil {
+		database.Close()
+		fatal("failed to run migrations", err)
 	}
 	application, err := app.NewApp(cfg)
 	if err != nil {
-		log.Fatal("Failed to initialize application:", err)
+		database.Close()
+		fatal("failed to initialize application", err)
 	}
-	defer application.Close()
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("Server starting on %s", addr)
-	if err := http.ListenAndServe(addr, application.GetRouter().Router.GetEngine()); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           application.GetRouter().Router.GetEngine(),
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	var metricsSrv *http.Server
+	if cfg.Metrics.Addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsSrv = &http.Server{Addr: cfg.Metrics.Addr, Handler: mux, ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout}
+		go func() {
+			slog.Info("metrics server starting", "addr", cfg.Metrics.Addr)
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("metrics server failed", "error", err)
+			}
+		}()
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "addr", addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+	exitCode := 0
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("server failed", "error", err)
+			exitCode = 1
+		}
+	case <-ctx.Done():
+		stop()
+		slog.Info("shutdown signal received, draining requests", "timeout", cfg.Server.ShutdownTimeout.String())
+	}
+	application.Drain()
+	if exitCode == 0 && cfg.Server.DrainDelay > 0 {
+		slog.Info("readiness set to draining, waiting before closing listener", "delay", cfg.Server.DrainDelay.String())
+		time.Sleep(cfg.Server.DrainDelay)
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http server shutdown incomplete", "error", err)
+		exitCode = 1
 	}
+	if err := application.Shutdown(shutdownCtx); err != nil {
+		slog.Error("application shutdown incomplete", "error", err)
+		exitCode = 1
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("metrics server shutdown incomplete", "error", err)
+		}
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	slog.Info("server stopped")
+	if exitCode != 0 {
+		cancel()
+		stop()
+		os.Exit(exitCode)
+	}
+}
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
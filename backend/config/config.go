@@ -2,20 +2,40 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	OpenAI   OpenAIConfig
-	JWT      JWTConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	OpenAI    OpenAIConfig
+	JWT       JWTConfig
+	Orders    OrdersConfig
+	Storage   StorageConfig
+	Health    HealthConfig
+	Metrics   MetricsConfig
+	Log       LogConfig
+	Tracing   TracingConfig
+	RateLimit RateLimitConfig
+	Guardrail GuardrailConfig
+	Webhook   WebhookConfig
 }
 type ServerConfig struct {
-	Port string
-	Host string
+	Port              string
+	Host              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	DrainDelay        time.Duration
+	TrustedProxies    []string
 }
 type DatabaseConfig struct {
 	Host     string
@@ -26,18 +46,83 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 type OpenAIConfig struct {
-	Secret string
+	Secret                string
+	BaseURL               string
+	SuggestionsDailyLimit int
+	PriceTable            string
 }
 type JWTConfig struct {
 	Secret string
 }
+type OrdersConfig struct {
+	CancellationCutoff time.Duration
+}
+type LogConfig struct {
+	Level            string
+	Format           string
+	SQLSlowThreshold time.Duration
+}
+type TracingConfig struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	ServiceName  string
+	SampleRatio  float64
+}
+type RateLimitConfig struct {
+	Enabled     bool
+	Store       string
+	Login       string
+	Suggestions string
+	API         string
+	Public      string
+}
+type GuardrailConfig struct {
+	Enabled            bool
+	RulesPath          string
+	PrescriptionAction string
+}
+type WebhookConfig struct {
+	RequireHTTPS         bool
+	AllowPrivateNetworks bool
+}
+type HealthConfig struct {
+	CheckTimeout time.Duration
+	CheckAI      bool
+}
+type MetricsConfig struct {
+	Addr  string
+	Token string
+}
+type StorageConfig struct {
+	Driver        string
+	LocalPath     string
+	PublicURL     string
+	SigningSecret string
+	S3            S3Config
+}
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool
+}
 
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "localhost"),
+			Port:              getEnv("SERVER_PORT", "8080"),
+			Host:              getEnv("SERVER_HOST", "localhost"),
+			ReadTimeout:       getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
+			ReadHeaderTimeout: getDurationEnv("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:      getDurationEnv("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       getDurationEnv("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			ShutdownTimeout:   getDurationEnv("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second),
+			DrainDelay:        getDurationEnv("SERVER_DRAIN_DELAY", 0),
+			TrustedProxies:    getListEnv("SERVER_TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -48,11 +133,68 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		OpenAI: OpenAIConfig{
-			Secret: getEnv("OPEN_AI_SECRET", ""),
+			Secret:                getEnv("OPEN_AI_SECRET", ""),
+			BaseURL:               getEnv("OPENAI_BASE_URL", ""),
+			SuggestionsDailyLimit: getIntEnv("AI_SUGGESTIONS_DAILY_LIMIT", 20),
+			PriceTable:            getEnv("AI_PRICE_TABLE", "gpt-4o-mini=0.15:0.60,gpt-4o=2.50:10.00"),
 		},
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", "default-secret-change-in-production"),
 		},
+		Orders: OrdersConfig{
+			CancellationCutoff: getDurationEnv("ORDER_CANCELLATION_CUTOFF", 30*time.Minute),
+		},
+		Log: LogConfig{
+			Level:            getEnv("LOG_LEVEL", "info"),
+			Format:           getEnv("LOG_FORMAT", "json"),
+			SQLSlowThreshold: getDurationEnv("LOG_SQL_SLOW_THRESHOLD", 200*time.Millisecond),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getBoolEnv("TRACING_OTLP_INSECURE", true),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "weel-backend"),
+			SampleRatio:  getFloatEnv("TRACING_SAMPLE_RATIO", 1),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     getBoolEnv("RATE_LIMIT_ENABLED", true),
+			Store:       getEnv("RATE_LIMIT_STORE", "memory"),
+			Login:       getEnv("RATE_LIMIT_LOGIN", "5/1m"),
+			Suggestions: getEnv("RATE_LIMIT_SUGGESTIONS", "10/1m"),
+			API:         getEnv("RATE_LIMIT_API", "300/1m"),
+			Public:      getEnv("RATE_LIMIT_PUBLIC", "60/1m"),
+		},
+		Guardrail: GuardrailConfig{
+			Enabled:            getBoolEnv("GUARDRAIL_ENABLED", true),
+			RulesPath:          getEnv("GUARDRAIL_RULES_PATH", ""),
+			PrescriptionAction: getEnv("GUARDRAIL_PRESCRIPTION_ACTION", "flag"),
+		},
+		Webhook: WebhookConfig{
+			RequireHTTPS:         getBoolEnv("WEBHOOK_REQUIRE_HTTPS", true),
+			AllowPrivateNetworks: getBoolEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		},
+		Health: HealthConfig{
+			CheckTimeout: getDurationEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			CheckAI:      getBoolEnv("HEALTH_CHECK_AI", false),
+		},
+		Metrics: MetricsConfig{
+			Addr:  getEnv("METRICS_ADDR", ""),
+			Token: getEnv("METRICS_TOKEN", ""),
+		},
+		Storage: StorageConfig{
+			Driver:        getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:     getEnv("STORAGE_LOCAL_PATH", "./data/uploads"),
+			PublicURL:     getEnv("STORAGE_PUBLIC_URL", "http://localhost:8080/api/v1/files"),
+			SigningSecret: getEnv("STORAGE_SIGNING_SECRET", ""),
+			S3: S3Config{
+				Endpoint:        getEnv("STORAGE_S3_ENDPOINT", "http://localhost:9000"),
+				Region:          getEnv("STORAGE_S3_REGION", "us-east-1"),
+				Bucket:          getEnv("STORAGE_S3_BUCKET", "weel"),
+				AccessKeyID:     getEnv("STORAGE_S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("STORAGE_S3_SECRET_ACCESS_KEY", ""),
+				UsePathStyle:    getBoolEnv("STORAGE_S3_USE_PATH_STYLE", true),
+			},
+		},
 	}
 	return config, nil
 }
@@ -66,3 +208,60 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid duration for %s (%q), using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid integer for %s (%q), using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return i
+}
+func getBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Warning: invalid boolean for %s (%q), using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+func getFloatEnv(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Warning: invalid number for %s (%q), using default %g", key, value, defaultValue)
+		return defaultValue
+	}
+	return f
+}
@@ -0,0 +1,150 @@
+package aieval_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"weel-backend/internal/aieval"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAIService struct {
+	outputs map[string]string
+	err     error
+}
+
+func (f *fakeAIService) SuggestProducts(ctx context.Context, userID uint, summary string, address *string) (*service.ProductSuggestions, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	raw := f.outputs[summary]
+	products, err := service.ParseSuggestedProducts(raw)
+	return &service.ProductSuggestions{Products: products, Model: "gpt-4o-mini", RawOutput: raw}, err
+}
+func (f *fakeAIService) Ping(ctx context.Context) error {
+	return nil
+}
+func newEvaluator(t *testing.T, cases ...aieval.Case) *aieval.Evaluator {
+	t.Helper()
+	evaluator, err := aieval.NewEvaluator(&aieval.Dataset{Name: "test", MaxUnitPrice: 50, Cases: cases})
+	require.NoError(t, err)
+	return evaluator
+}
+func TestScore(t *testing.T) {
+	c := aieval.Case{ID: "pain", Summary: "Knee pain", Expected: []string{"acetaminophen", "ice pack|cold pack"}, Forbidden: []string{"ibuprofen"}}
+	evaluator := newEvaluator(t, c)
+	result := evaluator.Score(c, &service.ProductSuggestions{Products: []domain.AISuggestedProduct{
+		{Name: "Tylenol 500mg", Quantity: 1, Price: 9.99},
+		{Name: "Reusable Cold Packs", Quantity: 1, Price: 12},
+	}}, nil)
+	assert.True(t, result.Passed)
+	assert.True(t, result.ValidJSON)
+	assert.Equal(t, 1.0, result.Recall)
+	assert.Empty(t, result.Reason())
+	result = evaluator.Score(c, &service.ProductSuggestions{Products: []domain.AISuggestedProduct{
+		{Name: "Advil Liqui-Gels", Quantity: 1, Price: 11},
+		{Name: "Knee brace", Quantity: 0, Price: 80},
+	}}, nil)
+	assert.False(t, result.Passed)
+	assert.Equal(t, 0.0, result.Recall)
+	assert.Equal(t, []string{"ibuprofen"}, result.Forbidden)
+	assert.Len(t, result.PriceIssues, 2)
+	assert.Contains(t, result.Reason(), "missing acetaminophen, ice pack|cold pack")
+}
+func TestScore_Errors(t *testing.T) {
+	c := aieval.Case{ID: "empty", Summary: "Phone charger", ExpectEmpty: true}
+	evaluator := newEvaluator(t, c)
+	invalid := evaluator.Score(c, &service.ProductSuggestions{RawOutput: "Sorry"}, errors.New("failed to parse AI response"))
+	assert.False(t, invalid.ValidJSON)
+	assert.False(t, invalid.NoResponse)
+	assert.Equal(t, "invalid JSON", invalid.Reason())
+	failed := evaluator.Score(c, nil, errors.New("upstream timeout"))
+	assert.True(t, failed.NoResponse)
+	assert.Equal(t, "upstream timeout", failed.Reason())
+	assert.True(t, evaluator.Score(c, &service.ProductSuggestions{}, nil).Passed)
+	notEmpty := evaluator.Score(c, &service.ProductSuggestions{Products: []domain.AISuggestedProduct{{Name: "Vitamin C", Quantity: 1, Price: 5}}}, nil)
+	assert.False(t, notEmpty.Passed)
+}
+func TestRun_Summary(t *testing.T) {
+	cases := []aieval.Case{
+		{ID: "fever", Summary: "Fever", Expected: []string{"acetaminophen"}},
+		{ID: "cough", Summary: "Cough", Expected: []string{"dextromethorphan"}},
+		{ID: "broken", Summary: "Broken"},
+	}
+	ai := &fakeAIService{outputs: map[string]string{
+		"Fever":  "```json\n[{\"name\":\"Paracetamol 500mg\",\"quantity\":1,\"price\":4.5}]\n```",
+		"Cough":  `[{"name":"Cough drops","quantity":1,"price":3}]`,
+		"Broken": "not json",
+	}}
+	run := newEvaluator(t, cases...).Run(context.Background(), "candidate", ai)
+	assert.Equal(t, "candidate", run.Name)
+	assert.Equal(t, 1, run.Summary.Passed)
+	assert.InDelta(t, 1.0/3, run.Summary.PassRate, 1e-9)
+	assert.InDelta(t, 2.0/3, run.Summary.ValidJSONRate, 1e-9)
+	assert.InDelta(t, 1.0/3, run.Summary.MeanRecall, 1e-9)
+	assert.Equal(t, 0, run.Summary.Errors)
+	unavailable := newEvaluator(t, cases...).Run(context.Background(), "down", &fakeAIService{err: errors.New("no network")})
+	assert.Equal(t, 3, unavailable.Summary.Errors)
+	assert.Equal(t, 0.0, unavailable.Summary.ValidJSONRate)
+}
+func TestRecordAndReplay(t *testing.T) {
+	cases := []aieval.Case{{ID: "fever", Summary: "Fever", Expected: []string{"acetaminophen"}}, {ID: "broken", Summary: "Broken"}}
+	live := &fakeAIService{outputs: map[string]string{
+		"Fever":  `[{"name":"Tylenol","quantity":1,"price":8}]`,
+		"Broken": "not json",
+	}}
+	recorder := aieval.NewRecorder("baseline", live)
+	evaluator := newEvaluator(t, cases...)
+	recorded := evaluator.Run(context.Background(), "live", recorder)
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, recorder.Fixture().Save(path))
+	fixture, err := aieval.LoadFixture(path)
+	require.NoError(t, err)
+	require.Len(t, fixture.Recordings, 2)
+	assert.Equal(t, "fever", fixture.Recordings[0].CaseID)
+	replayed := evaluator.Run(context.Background(), "replay", aieval.NewReplayer(fixture))
+	assert.Equal(t, recorded.Summary.Passed, replayed.Summary.Passed)
+	assert.Equal(t, recorded.Summary.ValidJSONRate, replayed.Summary.ValidJSONRate)
+	_, err = aieval.NewReplayer(fixture).SuggestProducts(context.Background(), 0, "Unknown", nil)
+	assert.ErrorIs(t, err, aieval.ErrNoRecording)
+}
+func TestExampleFixture(t *testing.T) {
+	dataset, err := aieval.LoadDataset("testdata/dataset.json")
+	require.NoError(t, err)
+	fixture, err := aieval.LoadFixture("testdata/example_fixture.json")
+	require.NoError(t, err)
+	evaluator, err := aieval.NewEvaluator(dataset)
+	require.NoError(t, err)
+	run := evaluator.Run(context.Background(), "example", aieval.NewReplayer(fixture))
+	assert.Equal(t, 10, run.Summary.Cases)
+	assert.Equal(t, 7, run.Summary.Passed)
+	assert.Equal(t, 1, run.Summary.ForbiddenHits)
+	assert.Equal(t, 1, run.Summary.PriceIssues)
+	var out strings.Builder
+	require.NoError(t, aieval.WriteText(&out, dataset, []*aieval.RunResult{run}))
+	assert.Contains(t, out.String(), "70.0% (7/10)")
+	assert.Contains(t, out.String(), "FAIL: forbidden naproxen")
+}
+func TestLoadDataset_Invalid(t *testing.T) {
+	cases := []string{
+		`{"cases": []}`,
+		`{"cases": [{"summary": "Fever"}]}`,
+		`{"cases": [{"id": "a", "summary": "Fever"}, {"id": "a", "summary": "Cough"}]}`,
+		`{"cases": [{"id": "a"}]}`,
+		`{"cases": [{"id": "a", "summary": "Fever", "expected": ["x"], "expect_empty": true}]}`,
+		`[`,
+	}
+	for _, tc := range cases {
+		path := filepath.Join(t.TempDir(), "dataset.json")
+		require.NoError(t, os.WriteFile(path, []byte(tc), 0o600))
+		_, err := aieval.LoadDataset(path)
+		assert.ErrorIs(t, err, aieval.ErrInvalidDataset, tc)
+	}
+}
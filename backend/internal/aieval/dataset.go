@@ -0,0 +1,67 @@
+package aieval
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+const DefaultMaxUnitPrice = 200
+var ErrInvalidDataset = errors.New("invalid evaluation dataset")
+type Case struct {
+	ID              string   `json:"id"`
+	Summary         string   `json:"summary"`
+	DeliveryAddress string   `json:"delivery_address,omitempty"`
+	Expected        []string `json:"expected,omitempty"`
+	Forbidden       []string `json:"forbidden,omitempty"`
+	ExpectEmpty     bool     `json:"expect_empty,omitempty"`
+	MaxUnitPrice    float64  `json:"max_unit_price,omitempty"`
+}
+type Dataset struct {
+	Name         string  `json:"name"`
+	MaxUnitPrice float64 `json:"max_unit_price,omitempty"`
+	Cases        []Case  `json:"cases"`
+}
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var dataset Dataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDataset, err)
+	}
+	if err := dataset.Validate(); err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+func (d *Dataset) Validate() error {
+	if len(d.Cases) == 0 {
+		return fmt.Errorf("%w: no cases", ErrInvalidDataset)
+	}
+	seen := make(map[string]bool, len(d.Cases))
+	for i, c := range d.Cases {
+		switch {
+		case strings.TrimSpace(c.ID) == "":
+			return fmt.Errorf("%w: case %d has no id", ErrInvalidDataset, i)
+		case seen[c.ID]:
+			return fmt.Errorf("%w: duplicate case id %q", ErrInvalidDataset, c.ID)
+		case strings.TrimSpace(c.Summary) == "":
+			return fmt.Errorf("%w: case %q has no summary", ErrInvalidDataset, c.ID)
+		case c.ExpectEmpty && len(c.Expected) > 0:
+			return fmt.Errorf("%w: case %q expects products and an empty result", ErrInvalidDataset, c.ID)
+		}
+		seen[c.ID] = true
+	}
+	return nil
+}
+func (d *Dataset) maxUnitPrice(c Case) float64 {
+	if c.MaxUnitPrice > 0 {
+		return c.MaxUnitPrice
+	}
+	if d.MaxUnitPrice > 0 {
+		return d.MaxUnitPrice
+	}
+	return DefaultMaxUnitPrice
+}
@@ -0,0 +1,204 @@
+package aieval
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/guardrail"
+	"weel-backend/internal/service"
+)
+type caseIDKey struct{}
+func CaseIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(caseIDKey{}).(string)
+	return id
+}
+type CaseResult struct {
+	ID          string                      `json:"id"`
+	Products    []domain.AISuggestedProduct `json:"products"`
+	Error       string                      `json:"error,omitempty"`
+	NoResponse  bool                        `json:"no_response,omitempty"`
+	ValidJSON   bool                        `json:"valid_json"`
+	Found       []string                    `json:"found,omitempty"`
+	Missing     []string                    `json:"missing,omitempty"`
+	Forbidden   []string                    `json:"forbidden,omitempty"`
+	PriceIssues []string                    `json:"price_issues,omitempty"`
+	Recall      float64                     `json:"recall"`
+	Passed      bool                        `json:"passed"`
+	LatencyMs   int64                       `json:"latency_ms"`
+}
+func (r *CaseResult) Reason() string {
+	var reasons []string
+	switch {
+	case r.NoResponse:
+		reasons = append(reasons, r.Error)
+	case !r.ValidJSON:
+		reasons = append(reasons, "invalid JSON")
+	}
+	if len(r.Missing) > 0 {
+		reasons = append(reasons, "missing "+strings.Join(r.Missing, ", "))
+	}
+	if len(r.Forbidden) > 0 {
+		reasons = append(reasons, "forbidden "+strings.Join(r.Forbidden, ", "))
+	}
+	if len(r.PriceIssues) > 0 {
+		reasons = append(reasons, strings.Join(r.PriceIssues, ", "))
+	}
+	return strings.Join(reasons, "; ")
+}
+type Summary struct {
+	Cases         int     `json:"cases"`
+	Passed        int     `json:"passed"`
+	PassRate      float64 `json:"pass_rate"`
+	ValidJSONRate float64 `json:"valid_json_rate"`
+	MeanRecall    float64 `json:"mean_recall"`
+	ForbiddenHits int     `json:"forbidden_hits"`
+	PriceIssues   int     `json:"price_issues"`
+	Errors        int     `json:"errors"`
+	AvgProducts   float64 `json:"avg_products"`
+	AvgLatencyMs  float64 `json:"avg_latency_ms"`
+}
+type RunResult struct {
+	Name    string        `json:"name"`
+	Summary Summary       `json:"summary"`
+	Cases   []*CaseResult `json:"cases"`
+}
+type Evaluator struct {
+	dataset *Dataset
+	checker *guardrail.Checker
+}
+func NewEvaluator(dataset *Dataset) (*Evaluator, error) {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluator{dataset: dataset, checker: checker}, nil
+}
+func (e *Evaluator) Run(ctx context.Context, name string, ai service.AIService) *RunResult {
+	run := &RunResult{Name: name, Cases: make([]*CaseResult, 0, len(e.dataset.Cases))}
+	for _, c := range e.dataset.Cases {
+		var address *string
+		if c.DeliveryAddress != "" {
+			address = &c.DeliveryAddress
+		}
+		started := time.Now()
+		result, err := ai.SuggestProducts(context.WithValue(ctx, caseIDKey{}, c.ID), 0, c.Summary, address)
+		elapsed := time.Since(started)
+		cr := e.Score(c, result, err)
+		cr.LatencyMs = elapsed.Milliseconds()
+		run.Cases = append(run.Cases, cr)
+	}
+	run.Summary = summarize(run.Cases)
+	return run
+}
+func (e *Evaluator) Score(c Case, result *service.ProductSuggestions, err error) *CaseResult {
+	cr := &CaseResult{ID: c.ID, Products: []domain.AISuggestedProduct{}}
+	if result != nil {
+		cr.Products = append(cr.Products, result.Products...)
+	}
+	if err != nil {
+		cr.Error = err.Error()
+		cr.NoResponse = result == nil || result.RawOutput == ""
+		return cr
+	}
+	cr.ValidJSON = true
+	for _, term := range c.Expected {
+		if e.suggested(cr.Products, term) {
+			cr.Found = append(cr.Found, term)
+		} else {
+			cr.Missing = append(cr.Missing, term)
+		}
+	}
+	for _, term := range c.Forbidden {
+		if e.suggested(cr.Products, term) {
+			cr.Forbidden = append(cr.Forbidden, term)
+		}
+	}
+	maxPrice := e.dataset.maxUnitPrice(c)
+	for _, p := range cr.Products {
+		switch {
+		case p.Price <= 0:
+			cr.PriceIssues = append(cr.PriceIssues, fmt.Sprintf("%s has no price", p.Name))
+		case p.Price > maxPrice:
+			cr.PriceIssues = append(cr.PriceIssues, fmt.Sprintf("%s costs %.2f (max %.2f)", p.Name, p.Price, maxPrice))
+		}
+		if p.Quantity < 1 {
+			cr.PriceIssues = append(cr.PriceIssues, fmt.Sprintf("%s has quantity %d", p.Name, p.Quantity))
+		}
+	}
+	cr.Recall = 1
+	if len(c.Expected) > 0 {
+		cr.Recall = float64(len(cr.Found)) / float64(len(c.Expected))
+	}
+	if c.ExpectEmpty && len(cr.Products) > 0 {
+		cr.Recall = 0
+		cr.Missing = append(cr.Missing, "an empty result")
+	}
+	cr.Passed = len(cr.Missing) == 0 && len(cr.Forbidden) == 0 && len(cr.PriceIssues) == 0
+	return cr
+}
+func (e *Evaluator) suggested(products []domain.AISuggestedProduct, term string) bool {
+	for _, alternative := range strings.Split(term, "|") {
+		want := normalize(alternative)
+		if want == "" {
+			continue
+		}
+		substances := e.checker.Substances(alternative)
+		for _, p := range products {
+			if strings.Contains(normalize(p.Name), want) {
+				return true
+			}
+			for _, s := range e.checker.Substances(p.Name) {
+				for _, wanted := range substances {
+					if s == wanted {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+func summarize(cases []*CaseResult) Summary {
+	s := Summary{Cases: len(cases)}
+	if len(cases) == 0 {
+		return s
+	}
+	var responses, validJSON, products int
+	var recall float64
+	var latency int64
+	for _, c := range cases {
+		if c.Passed {
+			s.Passed++
+		}
+		if c.NoResponse {
+			s.Errors++
+		} else {
+			responses++
+		}
+		if c.ValidJSON {
+			validJSON++
+		}
+		recall += c.Recall
+		s.ForbiddenHits += len(c.Forbidden)
+		s.PriceIssues += len(c.PriceIssues)
+		products += len(c.Products)
+		latency += c.LatencyMs
+	}
+	n := float64(len(cases))
+	s.PassRate = float64(s.Passed) / n
+	if responses > 0 {
+		s.ValidJSONRate = float64(validJSON) / float64(responses)
+	}
+	s.MeanRecall = recall / n
+	s.AvgProducts = float64(products) / n
+	s.AvgLatencyMs = float64(latency) / n
+	return s
+}
+func normalize(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
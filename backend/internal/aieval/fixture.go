@@ -0,0 +1,116 @@
+package aieval
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+)
+var ErrNoRecording = errors.New("no recorded response for this summary")
+type Recording struct {
+	CaseID        string `json:"case_id,omitempty"`
+	Summary       string `json:"summary"`
+	Model         string `json:"model,omitempty"`
+	PromptName    string `json:"prompt_name,omitempty"`
+	PromptVersion int    `json:"prompt_version,omitempty"`
+	RawOutput     string `json:"raw_output"`
+	Error         string `json:"error,omitempty"`
+}
+type Fixture struct {
+	Name       string      `json:"name,omitempty"`
+	Recordings []Recording `json:"recordings"`
+}
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+type Replayer struct {
+	recordings map[string]Recording
+}
+func NewReplayer(fixture *Fixture) *Replayer {
+	r := &Replayer{recordings: make(map[string]Recording, len(fixture.Recordings))}
+	for _, rec := range fixture.Recordings {
+		r.recordings[rec.Summary] = rec
+	}
+	return r
+}
+func (r *Replayer) SuggestProducts(ctx context.Context, userID uint, summary string, address *string) (*service.ProductSuggestions, error) {
+	rec, ok := r.recordings[summary]
+	if !ok {
+		return nil, ErrNoRecording
+	}
+	if rec.Error != "" {
+		return nil, errors.New(rec.Error)
+	}
+	result := &service.ProductSuggestions{
+		Products:      []domain.AISuggestedProduct{},
+		Model:         rec.Model,
+		PromptName:    rec.PromptName,
+		PromptVersion: rec.PromptVersion,
+		RawOutput:     rec.RawOutput,
+	}
+	if rec.RawOutput == "" {
+		return result, nil
+	}
+	products, err := service.ParseSuggestedProducts(rec.RawOutput)
+	if err != nil {
+		return result, err
+	}
+	result.Products = append(result.Products, products...)
+	return result, nil
+}
+func (r *Replayer) Ping(ctx context.Context) error {
+	return nil
+}
+type Recorder struct {
+	next    service.AIService
+	mu      sync.Mutex
+	fixture Fixture
+}
+func NewRecorder(name string, next service.AIService) *Recorder {
+	return &Recorder{next: next, fixture: Fixture{Name: name, Recordings: []Recording{}}}
+}
+func (r *Recorder) SuggestProducts(ctx context.Context, userID uint, summary string, address *string) (*service.ProductSuggestions, error) {
+	result, err := r.next.SuggestProducts(ctx, userID, summary, address)
+	rec := Recording{CaseID: CaseIDFromContext(ctx), Summary: summary}
+	if result != nil {
+		rec.Model = result.Model
+		rec.PromptName = result.PromptName
+		rec.PromptVersion = result.PromptVersion
+		rec.RawOutput = result.RawOutput
+	}
+	if err != nil && rec.RawOutput == "" {
+		rec.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.fixture.Recordings = append(r.fixture.Recordings, rec)
+	r.mu.Unlock()
+	return result, err
+}
+func (r *Recorder) Ping(ctx context.Context) error {
+	return r.next.Ping(ctx)
+}
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fixture := r.fixture
+	fixture.Recordings = append([]Recording(nil), r.fixture.Recordings...)
+	return &fixture
+}
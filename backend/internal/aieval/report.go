@@ -0,0 +1,63 @@
+package aieval
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+func WriteJSON(w io.Writer, runs []*RunResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{"runs": runs})
+}
+func WriteText(w io.Writer, dataset *Dataset, runs []*RunResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	title := dataset.Name
+	if title == "" {
+		title = "dataset"
+	}
+	fmt.Fprintf(tw, "%s (%d cases)\n\n", title, len(dataset.Cases))
+	header := []string{"metric"}
+	for _, run := range runs {
+		header = append(header, run.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	rows := []struct {
+		label string
+		value func(Summary) string
+	}{
+		{"pass rate", func(s Summary) string { return fmt.Sprintf("%.1f%% (%d/%d)", s.PassRate*100, s.Passed, s.Cases) }},
+		{"valid json", func(s Summary) string { return fmt.Sprintf("%.1f%%", s.ValidJSONRate*100) }},
+		{"expected found", func(s Summary) string { return fmt.Sprintf("%.1f%%", s.MeanRecall*100) }},
+		{"forbidden suggested", func(s Summary) string { return fmt.Sprintf("%d", s.ForbiddenHits) }},
+		{"price issues", func(s Summary) string { return fmt.Sprintf("%d", s.PriceIssues) }},
+		{"errors", func(s Summary) string { return fmt.Sprintf("%d", s.Errors) }},
+		{"avg products", func(s Summary) string { return fmt.Sprintf("%.1f", s.AvgProducts) }},
+		{"avg latency", func(s Summary) string { return fmt.Sprintf("%.0fms", s.AvgLatencyMs) }},
+	}
+	for _, row := range rows {
+		cells := []string{row.label}
+		for _, run := range runs {
+			cells = append(cells, row.value(run.Summary))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	fmt.Fprintln(tw)
+	header[0] = "case"
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for i, c := range dataset.Cases {
+		cells := []string{c.ID}
+		for _, run := range runs {
+			cells = append(cells, caseCell(run.Cases[i]))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+func caseCell(c *CaseResult) string {
+	if c.Passed {
+		return "pass"
+	}
+	return "FAIL: " + c.Reason()
+}
@@ -1,13 +1,26 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"weel-backend/config"
 	"weel-backend/internal/container"
 	"weel-backend/internal/database"
+	"weel-backend/internal/health"
+	"weel-backend/internal/metrics"
+	"weel-backend/internal/module/address"
 	"weel-backend/internal/module/auth"
+	"weel-backend/internal/module/delivery"
 	"weel-backend/internal/module/feature_flag"
 	"weel-backend/internal/module/order"
+	"weel-backend/internal/module/pickup"
+	"weel-backend/internal/module/prescription"
+	"weel-backend/internal/module/store"
+	filestorage "weel-backend/internal/module/storage"
 	"weel-backend/internal/module/user"
+	"weel-backend/internal/module/webhook"
+	"weel-backend/internal/ratelimit"
+	"weel-backend/internal/storage"
 )
 
 type App struct {
@@ -21,21 +34,61 @@ func NewApp(cfg *config.Config) (*App, error) {
 		container: container.NewContainer(),
 	}
 	app.container.DB = database.DB
+	app.container.Health = health.NewRegistry(cfg.Health.CheckTimeout)
+	app.container.Health.Register(
+		health.Check{Name: "database", Critical: true, Run: database.Ping},
+		health.Check{Name: "migrations", Critical: true, Run: database.CheckMigrations},
+	)
+	if sqlDB, err := database.DB.DB(); err == nil {
+		if err := metrics.RegisterDB(sqlDB, cfg.Database.Name); err != nil {
+			return nil, err
+		}
+	}
+	app.container.Events.Subscribe(metrics.RecordOrderEvent)
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	app.container.Storage = store
+	limiter, err := ratelimit.New(cfg.RateLimit, database.DB)
+	if err != nil {
+		return nil, err
+	}
+	app.container.RateLimiter = limiter
 	app.registerModules()
 	if err := app.container.Initialize(); err != nil {
 		return nil, err
 	}
+	if err := app.container.Router.GetEngine().SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+	if cfg.Metrics.Addr == "" && cfg.Metrics.Token != "" {
+		app.container.Router.RegisterMetricsRoute(cfg.Metrics.Token)
+	}
 	return app, nil
 }
 func (a *App) registerModules() {
 	a.container.RegisterModule(feature_flag.NewFeatureFlagModule())
 	a.container.RegisterModule(auth.NewAuthModule())
-	a.container.RegisterModule(order.NewOrderModule(a.config))
+	a.container.RegisterModule(address.NewAddressModule())
+	a.container.RegisterModule(delivery.NewDeliveryModule())
+	a.container.RegisterModule(store.NewStoreModule())
+	a.container.RegisterModule(pickup.NewPickupModule())
+	a.container.RegisterModule(order.NewOrderModule(a.config, a.container.Events))
+	a.container.RegisterModule(filestorage.NewStorageModule(a.container.Storage))
+	a.container.RegisterModule(prescription.NewPrescriptionModule(a.container.Storage))
 	a.container.RegisterModule(user.NewUserModule())
+	a.container.RegisterModule(webhook.NewWebhookModule(a.config.Webhook, a.container.Events))
 }
 func (a *App) GetRouter() *container.Container {
 	return a.container
 }
+func (a *App) Drain() {
+	a.container.Drain()
+}
+func (a *App) Shutdown(ctx context.Context) error {
+	return errors.Join(a.container.Shutdown(ctx), database.Close())
+}
 func (a *App) Close() error {
-	return database.Close()
+	return a.Shutdown(context.Background())
 }
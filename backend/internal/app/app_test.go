@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"weel-backend/config"
+	"weel-backend/internal/database"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var pathParam = regexp.MustCompile(`[:*][^/]+`)
+
+var unlimitedRoutes = map[string]bool{
+	"/metrics": true,
+	"/health":  true,
+	"/livez":   true,
+	"/readyz":  true,
+}
+
+func TestEveryAPIRouteIsRateLimited(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=app_test sslmode=disable"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	database.DB = db
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.Metrics = config.MetricsConfig{}
+	cfg.Storage.Driver = "local"
+	cfg.Storage.LocalPath = t.TempDir()
+	cfg.RateLimit = config.RateLimitConfig{Enabled: true, Store: "memory", Login: "1000/1m", Suggestions: "1000/1m", API: "1000/1m", Public: "1000/1m"}
+	application, err := NewApp(cfg)
+	require.NoError(t, err)
+	defer application.Drain()
+	token, err := service.NewJWTService().GenerateToken(1, "admin@example.com", domain.UserRoleAdmin)
+	require.NoError(t, err)
+	engine := application.GetRouter().Router.GetEngine()
+	routes := engine.Routes()
+	require.NotEmpty(t, routes)
+	for _, route := range routes {
+		if unlimitedRoutes[route.Path] {
+			continue
+		}
+		req := httptest.NewRequest(route.Method, pathParam.ReplaceAllString(route.Path, "1"), nil)
+		req.Header.Set(middleware.AuthorizationHeader, middleware.BearerPrefix+token)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		assert.NotEmpty(t, w.Header().Get(middleware.RateLimitLimitHeader), "%s %s has no rate limit", route.Method, route.Path)
+		assert.NotEqual(t, http.StatusTooManyRequests, w.Code)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "metrics must not be public without METRICS_TOKEN")
+}
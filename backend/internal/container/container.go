@@ -1,16 +1,29 @@
 package container
 import (
+	"context"
+	"errors"
+	"fmt"
+	"weel-backend/internal/events"
+	"weel-backend/internal/health"
 	"weel-backend/internal/module"
+	"weel-backend/internal/ratelimit"
 	"weel-backend/internal/router"
+	"weel-backend/internal/storage"
 	"gorm.io/gorm"
 )
 type Container struct {
-	DB      *gorm.DB
-	Router  *router.Router
-	Modules []module.Module
+	DB          *gorm.DB
+	Router      *router.Router
+	Events      *events.Bus
+	Storage     storage.BlobStore
+	Health      *health.Registry
+	RateLimiter *ratelimit.Limiter
+	Modules     []module.Module
 }
 func NewContainer() *Container {
 	return &Container{
+		Events:  events.NewBus(),
+		Health:  health.NewRegistry(health.DefaultTimeout),
 		Modules: make([]module.Module, 0),
 	}
 }
@@ -19,13 +32,38 @@ func (c *Container) RegisterModule(m module.Module) {
 }
 func (c *Container) Initialize() error {
 	c.Router = router.NewRouter()
+	c.Router.SetRateLimiter(c.RateLimiter)
 	for _, m := range c.Modules {
 		if err := m.Initialize(c.DB); err != nil {
 			return err
 		}
 	}
+	for _, m := range c.Modules {
+		if checker, ok := m.(module.HealthChecker); ok {
+			c.Health.Register(checker.HealthChecks()...)
+		}
+	}
+	c.Router.RegisterHealthRoutes(c.Health)
 	for _, m := range c.Modules {
 		m.RegisterRoutes(c.Router)
 	}
 	return nil
 }
+func (c *Container) Drain() {
+	c.Health.Drain()
+}
+func (c *Container) Shutdown(ctx context.Context) error {
+	c.Health.Drain()
+	var errs []error
+	for i := len(c.Modules) - 1; i >= 0; i-- {
+		m := c.Modules[i]
+		shutdowner, ok := m.(module.Shutdowner)
+		if !ok {
+			continue
+		}
+		if err := shutdowner.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
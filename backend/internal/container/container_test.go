@@ -0,0 +1,81 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"weel-backend/internal/health"
+	"weel-backend/internal/router"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeModule struct {
+	name  string
+	err   error
+	order *[]string
+}
+
+func (m *fakeModule) Initialize(db *gorm.DB) error         { return nil }
+func (m *fakeModule) RegisterRoutes(router *router.Router) {}
+func (m *fakeModule) Name() string                         { return m.name }
+func (m *fakeModule) Shutdown(ctx context.Context) error {
+	*m.order = append(*m.order, m.name)
+	return m.err
+}
+
+func TestShutdownRunsModulesInReverseOrder(t *testing.T) {
+	var order []string
+	c := NewContainer()
+	c.RegisterModule(&fakeModule{name: "auth", order: &order})
+	c.RegisterModule(&fakeModule{name: "order", order: &order})
+	c.RegisterModule(&fakeModule{name: "webhook", order: &order})
+
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.Equal(t, []string{"webhook", "order", "auth"}, order)
+}
+
+type routesOnlyModule struct{}
+
+func (routesOnlyModule) Initialize(db *gorm.DB) error         { return nil }
+func (routesOnlyModule) RegisterRoutes(router *router.Router) {}
+func (routesOnlyModule) Name() string                         { return "routes-only" }
+
+func TestShutdownSkipsModulesWithoutShutdown(t *testing.T) {
+	var order []string
+	c := NewContainer()
+	c.RegisterModule(&fakeModule{name: "auth", order: &order})
+	c.RegisterModule(routesOnlyModule{})
+	c.RegisterModule(&fakeModule{name: "webhook", order: &order})
+
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.Equal(t, []string{"webhook", "auth"}, order)
+}
+
+func TestShutdownContinuesAfterModuleError(t *testing.T) {
+	var order []string
+	boom := errors.New("boom")
+	c := NewContainer()
+	c.RegisterModule(&fakeModule{name: "auth", order: &order})
+	c.RegisterModule(&fakeModule{name: "webhook", err: boom, order: &order})
+
+	err := c.Shutdown(context.Background())
+	assert.ErrorIs(t, err, boom)
+	assert.Contains(t, err.Error(), "webhook")
+	assert.Equal(t, []string{"webhook", "auth"}, order)
+}
+
+func TestDrainMarksUnreadyBeforeModulesShutDown(t *testing.T) {
+	var order []string
+	c := NewContainer()
+	c.RegisterModule(&fakeModule{name: "webhook", order: &order})
+
+	c.Drain()
+	assert.Equal(t, health.StatusUnavailable, c.Health.Run(context.Background()).Status)
+	assert.Empty(t, order)
+
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.Equal(t, []string{"webhook"}, order)
+}
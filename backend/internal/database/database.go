@@ -1,35 +1,47 @@
 package database
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"weel-backend/config"
-	"weel-backend/internal/domain"
+	"weel-backend/internal/logging"
+	"weel-backend/internal/migrator"
+	"weel-backend/internal/tracing"
+	"weel-backend/migrations"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 var DB *gorm.DB
 func Connect(cfg *config.Config) error {
 	var err error
 	DB, err = gorm.Open(postgres.Open(cfg.Database.DSN()), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: logging.NewGormLogger(slog.Default(), cfg.Log.SQLSlowThreshold),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
-	log.Println("Database connected successfully")
+	if err := DB.Use(tracing.NewGormPlugin()); err != nil {
+		return fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+	slog.Info("database connected", "host", cfg.Database.Host, "name", cfg.Database.Name)
 	return nil
 }
-func AutoMigrate() error {
-	err := DB.AutoMigrate(
-		&domain.User{},
-		&domain.Order{},
-		&domain.FeatureFlag{},
-	)
+func Migrate() error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	m, err := migrator.New(sqlDB, migrations.FS, migrator.WithLogger(func(format string, args ...interface{}) {
+		slog.Info(fmt.Sprintf(format, args...), "component", "migrator")
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+	applied, err := m.Up(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
-	log.Println("Database migrations completed successfully")
+	slog.Info("database migrations completed", "applied", applied)
 	return nil
 }
 func Close() error {
@@ -39,3 +51,37 @@ func Close() error {
 	}
 	return sqlDB.Close()
 }
+func Ping(ctx context.Context) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+func CheckMigrations(ctx context.Context) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	m, err := migrator.New(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending, drifted := 0, 0
+	for _, s := range statuses {
+		switch s.State() {
+		case "pending":
+			pending++
+		case "modified", "missing":
+			drifted++
+		}
+	}
+	if pending > 0 || drifted > 0 {
+		return fmt.Errorf("%d pending and %d modified or missing migration(s)", pending, drifted)
+	}
+	return nil
+}
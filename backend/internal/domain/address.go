@@ -0,0 +1,73 @@
+package domain
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+type PostalAddress struct {
+	Line1      string   `json:"line1" gorm:"type:varchar(255);not null"`
+	Line2      string   `json:"line2,omitempty" gorm:"type:varchar(255)"`
+	City       string   `json:"city" gorm:"type:varchar(100);not null"`
+	Region     string   `json:"region,omitempty" gorm:"type:varchar(100)"`
+	PostalCode string   `json:"postal_code" gorm:"type:varchar(20);not null"`
+	Country    string   `json:"country" gorm:"type:char(2);not null"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+}
+func (a PostalAddress) Format() string {
+	parts := []string{a.Line1}
+	if a.Line2 != "" {
+		parts = append(parts, a.Line2)
+	}
+	locality := a.City
+	if a.Region != "" {
+		locality += ", " + a.Region
+	}
+	parts = append(parts, locality+" "+a.PostalCode, a.Country)
+	return strings.Join(parts, ", ")
+}
+type AddressSnapshot struct {
+	PostalAddress
+	Label string `json:"label,omitempty"`
+}
+func (a AddressSnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+func (a *AddressSnapshot) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = AddressSnapshot{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into AddressSnapshot", value)
+	}
+	return json.Unmarshal(data, a)
+}
+type Address struct {
+	ID            uint   `json:"id" gorm:"primaryKey"`
+	UserID        uint   `json:"user_id" gorm:"not null;index"`
+	Label         string `json:"label,omitempty" gorm:"type:varchar(50)"`
+	PostalAddress `gorm:"embedded"`
+	IsDefault     bool           `json:"is_default" gorm:"default:false;not null"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+func (a *Address) Snapshot() *AddressSnapshot {
+	return &AddressSnapshot{PostalAddress: a.PostalAddress, Label: a.Label}
+}
+func (Address) TableName() string {
+	return "addresses"
+}
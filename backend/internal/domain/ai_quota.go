@@ -0,0 +1,30 @@
+package domain
+import (
+	"time"
+)
+type AISuggestionQuota struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"uniqueIndex;not null"`
+	DailyLimit int       `json:"daily_limit" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+func (AISuggestionQuota) TableName() string {
+	return "ai_suggestion_quotas"
+}
+type AISuggestionUsage struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey"`
+	Day       time.Time `json:"day" gorm:"primaryKey;type:date"`
+	Count     int       `json:"count" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+func (AISuggestionUsage) TableName() string {
+	return "ai_suggestion_usage"
+}
+type AIQuotaStatus struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Custom    bool      `json:"custom"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
@@ -0,0 +1,136 @@
+package domain
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+type SuggestedProducts []AISuggestedProduct
+func (p SuggestedProducts) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]AISuggestedProduct(p))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+func (p *SuggestedProducts) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*p = SuggestedProducts{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into SuggestedProducts", value)
+	}
+	var products []AISuggestedProduct
+	if err := json.Unmarshal(data, &products); err != nil {
+		return err
+	}
+	*p = products
+	return nil
+}
+type WarningSeverity string
+const (
+	WarningSeverityInfo     WarningSeverity = "info"
+	WarningSeverityWarning  WarningSeverity = "warning"
+	WarningSeverityCritical WarningSeverity = "critical"
+)
+type GuardrailAction string
+const (
+	GuardrailActionFlagged  GuardrailAction = "flagged"
+	GuardrailActionBlocked  GuardrailAction = "blocked"
+	GuardrailActionAdjusted GuardrailAction = "adjusted"
+)
+type SuggestionWarning struct {
+	Code     string          `json:"code"`
+	Severity WarningSeverity `json:"severity"`
+	Action   GuardrailAction `json:"action"`
+	Product  string          `json:"product,omitempty"`
+	Products []string        `json:"products,omitempty"`
+	Message  string          `json:"message"`
+}
+type SuggestionWarnings []SuggestionWarning
+func (w SuggestionWarnings) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]SuggestionWarning(w))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+func (w *SuggestionWarnings) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*w = SuggestionWarnings{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into SuggestionWarnings", value)
+	}
+	var warnings []SuggestionWarning
+	if err := json.Unmarshal(data, &warnings); err != nil {
+		return err
+	}
+	*w = warnings
+	return nil
+}
+type AISuggestionSession struct {
+	ID              uint               `json:"id" gorm:"primaryKey"`
+	UserID          uint               `json:"user_id" gorm:"not null;index"`
+	Summary         string             `json:"summary" gorm:"type:text;not null"`
+	DeliveryAddress *string            `json:"delivery_address,omitempty" gorm:"type:text"`
+	Model           string             `json:"model" gorm:"type:varchar(100);not null"`
+	PromptName      string             `json:"prompt_name,omitempty" gorm:"type:varchar(100)"`
+	PromptVersion   int                `json:"prompt_version,omitempty"`
+	RawOutput       string             `json:"raw_output" gorm:"type:text;not null"`
+	Products        SuggestedProducts  `json:"products" gorm:"type:jsonb;not null"`
+	Warnings        SuggestionWarnings `json:"warnings" gorm:"type:jsonb;not null"`
+	RequiresReview  bool               `json:"requires_review" gorm:"not null;default:false"`
+	OrderID         *uint              `json:"order_id,omitempty" gorm:"index"`
+	AcceptedCount   int                `json:"accepted_count" gorm:"not null;default:0"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+func (AISuggestionSession) TableName() string {
+	return "ai_suggestion_sessions"
+}
+func (s *AISuggestionSession) CountAccepted(selected []AISuggestedProduct) int {
+	suggested := make(map[string]bool, len(s.Products))
+	for _, p := range s.Products {
+		suggested[normalizeProductName(p.Name)] = true
+	}
+	accepted := 0
+	for _, p := range selected {
+		name := normalizeProductName(p.Name)
+		if suggested[name] {
+			accepted++
+			delete(suggested, name)
+		}
+	}
+	return accepted
+}
+func normalizeProductName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+type AISuggestionStats struct {
+	Sessions          int64   `json:"sessions"`
+	ConvertedSessions int64   `json:"converted_sessions"`
+	SuggestedProducts int64   `json:"suggested_products"`
+	AcceptedProducts  int64   `json:"accepted_products"`
+	ConversionRate    float64 `json:"conversion_rate"`
+	AcceptanceRate    float64 `json:"acceptance_rate"`
+}
@@ -0,0 +1,29 @@
+package domain
+import (
+	"time"
+)
+type AIUsage struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	UserID           *uint     `json:"user_id,omitempty" gorm:"index"`
+	Operation        string    `json:"operation" gorm:"type:varchar(50);not null"`
+	Model            string    `json:"model" gorm:"type:varchar(100);not null"`
+	PromptTokens     int       `json:"prompt_tokens" gorm:"not null;default:0"`
+	CompletionTokens int       `json:"completion_tokens" gorm:"not null;default:0"`
+	LatencyMs        int64     `json:"latency_ms" gorm:"not null;default:0"`
+	Outcome          string    `json:"outcome" gorm:"type:varchar(20);not null;index"`
+	EstimatedCostUSD float64   `json:"estimated_cost_usd" gorm:"column:estimated_cost_usd;type:numeric(12,6);not null;default:0"`
+	CreatedAt        time.Time `json:"created_at" gorm:"index"`
+}
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
+type AIUsageSummary struct {
+	Day              time.Time `json:"day"`
+	UserID           *uint     `json:"user_id"`
+	Outcome          string    `json:"outcome"`
+	Requests         int64     `json:"requests"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	EstimatedCostUSD float64   `json:"estimated_cost_usd"`
+	AvgLatencyMs     float64   `json:"avg_latency_ms"`
+}
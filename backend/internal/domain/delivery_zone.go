@@ -0,0 +1,99 @@
+package domain
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+type GeoPolygon [][2]float64
+func (p GeoPolygon) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([][2]float64(p))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+func (p *GeoPolygon) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into GeoPolygon", value)
+	}
+	var points [][2]float64
+	if err := json.Unmarshal(data, &points); err != nil {
+		return err
+	}
+	*p = points
+	return nil
+}
+func (p GeoPolygon) Contains(lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		xi, yi := p[i][0], p[i][1]
+		xj, yj := p[j][0], p[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+type DeliveryZone struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	Name           string         `json:"name" gorm:"type:varchar(100);not null"`
+	Country        string         `json:"country,omitempty" gorm:"type:varchar(2)"`
+	PostalCodes    StringList     `json:"postal_codes" gorm:"type:jsonb;default:'[]';not null"`
+	PostalPrefixes StringList     `json:"postal_prefixes" gorm:"type:jsonb;default:'[]';not null"`
+	Polygon        GeoPolygon     `json:"polygon,omitempty" gorm:"type:jsonb"`
+	Fee            float64        `json:"fee" gorm:"type:numeric(10,2);default:0;not null"`
+	MinOrderAmount float64        `json:"min_order_amount" gorm:"type:numeric(10,2);default:0;not null"`
+	Priority       int            `json:"priority" gorm:"default:0;not null"`
+	Active         bool           `json:"active" gorm:"default:true;not null"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+func (DeliveryZone) TableName() string {
+	return "delivery_zones"
+}
+func (z *DeliveryZone) Match(country, postalCode string, lat, lng *float64) (int, bool) {
+	if z.Country != "" && country != "" && !strings.EqualFold(z.Country, country) {
+		return 0, false
+	}
+	code := CompactPostalCode(postalCode)
+	if code != "" {
+		for _, c := range z.PostalCodes {
+			if CompactPostalCode(c) == code {
+				return 1000, true
+			}
+		}
+		best := 0
+		for _, prefix := range z.PostalPrefixes {
+			p := CompactPostalCode(prefix)
+			if p != "" && strings.HasPrefix(code, p) && len(p) > best {
+				best = len(p)
+			}
+		}
+		if best > 0 {
+			return 100 + best, true
+		}
+	}
+	if lat != nil && lng != nil && len(z.Polygon) >= 3 && z.Polygon.Contains(*lat, *lng) {
+		return 1, true
+	}
+	return 0, false
+}
+func CompactPostalCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
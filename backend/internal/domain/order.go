@@ -1,6 +1,7 @@
 package domain
 import (
 	"encoding/json"
+	"math"
 	"time"
 	"gorm.io/gorm"
 )
@@ -17,6 +18,23 @@ const (
 	DeliveryPreferenceDelivery DeliveryPreference = "DELIVERY"
 	DeliveryPreferenceCurbside DeliveryPreference = "CURBSIDE"
 )
+type CancellationReason string
+const (
+	CancellationReasonChangedMind      CancellationReason = "changed_mind"
+	CancellationReasonOrderedByMistake CancellationReason = "ordered_by_mistake"
+	CancellationReasonDuplicateOrder   CancellationReason = "duplicate_order"
+	CancellationReasonFoundElsewhere   CancellationReason = "found_elsewhere"
+	CancellationReasonTooSlow          CancellationReason = "too_slow"
+	CancellationReasonOther            CancellationReason = "other"
+)
+func (r CancellationReason) IsValid() bool {
+	switch r {
+	case CancellationReasonChangedMind, CancellationReasonOrderedByMistake, CancellationReasonDuplicateOrder,
+		CancellationReasonFoundElsewhere, CancellationReasonTooSlow, CancellationReasonOther:
+		return true
+	}
+	return false
+}
 type AISuggestedProduct struct {
 	Name     string  `json:"name"`
 	Quantity int     `json:"quantity"`
@@ -24,18 +42,35 @@ type AISuggestedProduct struct {
 	Reason   string  `json:"reason,omitempty"`
 }
 type Order struct {
-	ID                  uint               `json:"id" gorm:"primaryKey"`
-	UserID              uint               `json:"user_id" gorm:"not null;index"`
-	User                User               `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Summary             string             `json:"summary" gorm:"type:text;not null"`
-	DeliveryPreference  DeliveryPreference `json:"delivery_preference" gorm:"type:varchar(20);not null"`
-	DeliveryAddress     *string            `json:"delivery_address,omitempty" gorm:"type:text"`
-	PostalCode          *string            `json:"postal_code,omitempty" gorm:"type:varchar(20)"`
-	AISuggestedProducts *string            `json:"ai_suggested_products,omitempty" gorm:"type:jsonb"`
-	Status              OrderStatus        `json:"status" gorm:"type:varchar(20);default:'pending';not null"`
-	CreatedAt           time.Time          `json:"created_at"`
-	UpdatedAt           time.Time          `json:"updated_at"`
-	DeletedAt           gorm.DeletedAt     `json:"-" gorm:"index"`
+	ID                  uint                `json:"id" gorm:"primaryKey"`
+	UserID              uint                `json:"user_id" gorm:"not null;index"`
+	User                User                `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Summary             string              `json:"summary" gorm:"type:text;not null"`
+	DeliveryPreference  DeliveryPreference  `json:"delivery_preference" gorm:"type:varchar(20);not null"`
+	DeliveryAddress     *string             `json:"delivery_address,omitempty" gorm:"type:text"`
+	PostalCode          *string             `json:"postal_code,omitempty" gorm:"type:varchar(20)"`
+	AddressID           *uint               `json:"address_id,omitempty"`
+	ShippingAddress     *AddressSnapshot    `json:"shipping_address,omitempty" gorm:"type:jsonb"`
+	DeliveryZoneID      *uint               `json:"delivery_zone_id,omitempty"`
+	DeliveryFee         float64             `json:"delivery_fee" gorm:"type:numeric(10,2);default:0;not null"`
+	StoreID             *uint               `json:"store_id,omitempty" gorm:"index"`
+	PickupSlotID        *uint               `json:"pickup_slot_id,omitempty"`
+	PickupSlotStart     *time.Time          `json:"pickup_slot_start,omitempty"`
+	PickupSlotEnd       *time.Time          `json:"pickup_slot_end,omitempty"`
+	CustomerArrivedAt   *time.Time          `json:"customer_arrived_at,omitempty"`
+	ArrivalNote         *string             `json:"arrival_note,omitempty" gorm:"type:text"`
+	AISuggestedProducts *string             `json:"ai_suggested_products,omitempty" gorm:"type:jsonb"`
+	SuggestionSessionID *uint               `json:"suggestion_session_id,omitempty" gorm:"index"`
+	Total               float64             `json:"total" gorm:"type:numeric(12,2);default:0;not null"`
+	Status              OrderStatus         `json:"status" gorm:"type:varchar(20);default:'pending';not null"`
+	PrescriptionStatus  *PrescriptionStatus `json:"prescription_status,omitempty" gorm:"type:varchar(20);index"`
+	ProcessingStartedAt *time.Time          `json:"processing_started_at,omitempty"`
+	CancellationReason  *CancellationReason `json:"cancellation_reason,omitempty" gorm:"type:varchar(40)"`
+	CancellationNote    *string             `json:"cancellation_note,omitempty" gorm:"type:text"`
+	CancelledAt         *time.Time          `json:"cancelled_at,omitempty"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt      `json:"-" gorm:"index"`
 }
 func (o *Order) GetAISuggestedProducts() ([]AISuggestedProduct, error) {
 	if o.AISuggestedProducts == nil || *o.AISuggestedProducts == "" {
@@ -48,6 +83,7 @@ func (o *Order) GetAISuggestedProducts() ([]AISuggestedProduct, error) {
 func (o *Order) SetAISuggestedProducts(products []AISuggestedProduct) error {
 	if len(products) == 0 {
 		o.AISuggestedProducts = nil
+		o.Total = 0
 		return nil
 	}
 	data, err := json.Marshal(products)
@@ -56,8 +92,19 @@ func (o *Order) SetAISuggestedProducts(products []AISuggestedProduct) error {
 	}
 	jsonStr := string(data)
 	o.AISuggestedProducts = &jsonStr
+	o.Total = ProductsTotal(products)
 	return nil
 }
+func ProductsTotal(products []AISuggestedProduct) float64 {
+	total := 0.0
+	for _, p := range products {
+		total += p.Price * float64(p.Quantity)
+	}
+	return math.Round(total*100) / 100
+}
+func (o *Order) AwaitingPrescriptionApproval() bool {
+	return o.PrescriptionStatus != nil && *o.PrescriptionStatus != PrescriptionStatusApproved
+}
 func (Order) TableName() string {
 	return "orders"
 }
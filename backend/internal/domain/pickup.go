@@ -0,0 +1,84 @@
+package domain
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+type DailyHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Open    string       `json:"open"`
+	Close   string       `json:"close"`
+}
+type OpeningHours []DailyHours
+func (h OpeningHours) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]DailyHours(h))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+func (h *OpeningHours) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*h = OpeningHours{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into OpeningHours", value)
+	}
+	var hours []DailyHours
+	if err := json.Unmarshal(data, &hours); err != nil {
+		return err
+	}
+	*h = hours
+	return nil
+}
+func (h OpeningHours) For(day time.Weekday) []DailyHours {
+	var result []DailyHours
+	for _, d := range h {
+		if d.Weekday == day {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+func ParseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+type PickupConfig struct {
+	ID              uint         `json:"id" gorm:"primaryKey"`
+	StoreID         uint         `json:"store_id" gorm:"not null;uniqueIndex"`
+	Hours           OpeningHours `json:"hours" gorm:"type:jsonb;default:'[]';not null"`
+	SlotMinutes     int          `json:"slot_minutes" gorm:"default:15;not null"`
+	Capacity        int          `json:"capacity" gorm:"default:4;not null"`
+	LeadTimeMinutes int          `json:"lead_time_minutes" gorm:"default:30;not null"`
+	HorizonDays     int          `json:"horizon_days" gorm:"default:7;not null"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+func (PickupConfig) TableName() string {
+	return "pickup_configs"
+}
+type PickupSlot struct {
+	ID       uint      `json:"id" gorm:"primaryKey"`
+	StoreID  uint      `json:"store_id" gorm:"not null;uniqueIndex:idx_pickup_slots_store_start"`
+	StartsAt time.Time `json:"starts_at" gorm:"not null;uniqueIndex:idx_pickup_slots_store_start"`
+	EndsAt   time.Time `json:"ends_at" gorm:"not null"`
+	Capacity int       `json:"capacity" gorm:"not null"`
+	Reserved int       `json:"reserved" gorm:"default:0;not null"`
+}
+func (PickupSlot) TableName() string {
+	return "pickup_slots"
+}
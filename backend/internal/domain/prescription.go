@@ -0,0 +1,32 @@
+package domain
+import (
+	"time"
+	"gorm.io/gorm"
+)
+type PrescriptionStatus string
+const (
+	PrescriptionStatusRequired  PrescriptionStatus = "required"
+	PrescriptionStatusSubmitted PrescriptionStatus = "submitted"
+	PrescriptionStatusApproved  PrescriptionStatus = "approved"
+	PrescriptionStatusRejected  PrescriptionStatus = "rejected"
+)
+type Prescription struct {
+	ID           uint               `json:"id" gorm:"primaryKey"`
+	OrderID      uint               `json:"order_id" gorm:"not null;index"`
+	UploadedByID uint               `json:"uploaded_by_id" gorm:"not null"`
+	FileKey      string             `json:"-" gorm:"type:text;not null"`
+	FileName     string             `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType  string             `json:"content_type" gorm:"type:varchar(100);not null"`
+	SizeBytes    int64              `json:"size_bytes" gorm:"not null"`
+	Status       PrescriptionStatus `json:"status" gorm:"type:varchar(20);default:'submitted';not null;index"`
+	ReviewedByID *uint              `json:"reviewed_by_id,omitempty"`
+	ReviewedBy   *User              `json:"reviewed_by,omitempty" gorm:"foreignKey:ReviewedByID"`
+	ReviewedAt   *time.Time         `json:"reviewed_at,omitempty"`
+	ReviewNote   *string            `json:"review_note,omitempty" gorm:"type:text"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+func (Prescription) TableName() string {
+	return "prescriptions"
+}
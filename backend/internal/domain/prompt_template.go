@@ -0,0 +1,28 @@
+package domain
+import "time"
+type PromptTemplate struct {
+	ID          uint      `json:"id,omitempty" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_prompt_templates_name_version"`
+	Version     int       `json:"version" gorm:"not null;uniqueIndex:idx_prompt_templates_name_version"`
+	Model       string    `json:"model" gorm:"type:varchar(100);not null"`
+	Temperature float32   `json:"temperature" gorm:"not null"`
+	MaxTokens   int       `json:"max_tokens" gorm:"not null"`
+	System      string    `json:"system" gorm:"column:system_prompt;type:text;not null"`
+	User        string    `json:"user" gorm:"column:user_prompt;type:text;not null"`
+	Active      bool      `json:"active" gorm:"not null;default:false"`
+	BuiltIn     bool      `json:"built_in" gorm:"-"`
+	CreatedBy   *uint     `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
+type RenderedPrompt struct {
+	Name        string  `json:"name"`
+	Version     int     `json:"version"`
+	Model       string  `json:"model"`
+	Temperature float32 `json:"temperature"`
+	MaxTokens   int     `json:"max_tokens"`
+	System      string  `json:"system"`
+	User        string  `json:"user"`
+}
@@ -0,0 +1,53 @@
+package domain
+import (
+	"time"
+	"gorm.io/gorm"
+)
+type Store struct {
+	ID                  uint             `json:"id" gorm:"primaryKey"`
+	Name                string           `json:"name" gorm:"type:varchar(120);not null"`
+	Phone               string           `json:"phone,omitempty" gorm:"type:varchar(32)"`
+	Address             *AddressSnapshot `json:"address,omitempty" gorm:"type:jsonb"`
+	Timezone            string           `json:"timezone" gorm:"type:varchar(64);default:'UTC';not null"`
+	Hours               OpeningHours     `json:"hours" gorm:"type:jsonb;default:'[]';not null"`
+	DeliveryPreferences StringList       `json:"delivery_preferences" gorm:"type:jsonb;default:'[]';not null"`
+	Active              bool             `json:"active" gorm:"default:true;not null"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+func (Store) TableName() string {
+	return "stores"
+}
+func (s *Store) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+func (s *Store) Supports(pref DeliveryPreference) bool {
+	if len(s.DeliveryPreferences) == 0 {
+		return true
+	}
+	for _, p := range s.DeliveryPreferences {
+		if DeliveryPreference(p) == pref {
+			return true
+		}
+	}
+	return false
+}
+type InventoryItem struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	StoreID   uint           `json:"store_id" gorm:"not null;uniqueIndex:idx_inventory_store_sku"`
+	SKU       string         `json:"sku" gorm:"type:varchar(64);not null;uniqueIndex:idx_inventory_store_sku"`
+	Name      string         `json:"name" gorm:"type:varchar(255);not null"`
+	Quantity  int            `json:"quantity" gorm:"default:0;not null"`
+	Price     float64        `json:"price" gorm:"type:numeric(10,2);default:0;not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+func (InventoryItem) TableName() string {
+	return "inventory_items"
+}
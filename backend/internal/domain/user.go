@@ -3,12 +3,26 @@ import (
 	"time"
 	"gorm.io/gorm"
 )
+type UserRole string
+const (
+	UserRoleCustomer   UserRole = "customer"
+	UserRolePharmacist UserRole = "pharmacist"
+	UserRoleAdmin      UserRole = "admin"
+)
+func (r UserRole) IsStaff() bool {
+	return r == UserRolePharmacist || r == UserRoleAdmin
+}
+func (r UserRole) CanReviewPrescriptions() bool {
+	return r.IsStaff()
+}
 type User struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
 	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
 	Password  string         `json:"-" gorm:"not null"`
 	FirstName string         `json:"first_name" gorm:"not null"`
 	LastName  string         `json:"last_name" gorm:"not null"`
+	Role      UserRole       `json:"role" gorm:"type:varchar(20);default:'customer';not null"`
+	StoreID   *uint          `json:"store_id,omitempty" gorm:"index"`
 	LastLogin *time.Time     `json:"last_login,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -0,0 +1,58 @@
+package domain
+import (
+	"time"
+	"gorm.io/gorm"
+)
+type WebhookDeliveryStatus string
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+type WebhookSubscription struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	URL         string         `json:"url" gorm:"type:text;not null"`
+	Events      StringList     `json:"events" gorm:"type:jsonb;not null"`
+	Secret      string         `json:"-" gorm:"not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	Active      bool           `json:"active" gorm:"default:true;not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+type WebhookDelivery struct {
+	ID               uint                     `json:"id" gorm:"primaryKey"`
+	SubscriptionID   uint                     `json:"subscription_id" gorm:"not null;index"`
+	EventID          string                   `json:"event_id" gorm:"type:varchar(64);not null;index"`
+	EventType        string                   `json:"event_type" gorm:"type:varchar(50);not null"`
+	Payload          string                   `json:"payload" gorm:"type:jsonb;not null"`
+	Status           WebhookDeliveryStatus    `json:"status" gorm:"type:varchar(20);default:'pending';not null;index"`
+	AttemptCount     int                      `json:"attempt_count" gorm:"default:0;not null"`
+	NextAttemptAt    *time.Time               `json:"next_attempt_at,omitempty" gorm:"index"`
+	LastResponseCode *int                     `json:"last_response_code,omitempty"`
+	LastError        *string                  `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt      *time.Time               `json:"delivered_at,omitempty"`
+	Attempts         []WebhookDeliveryAttempt `json:"attempts,omitempty" gorm:"foreignKey:DeliveryID"`
+	CreatedAt        time.Time                `json:"created_at"`
+	UpdatedAt        time.Time                `json:"updated_at"`
+}
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+type WebhookDeliveryAttempt struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	DeliveryID   uint      `json:"delivery_id" gorm:"not null;index"`
+	Attempt      int       `json:"attempt" gorm:"not null"`
+	ResponseCode *int      `json:"response_code,omitempty"`
+	ResponseBody *string   `json:"response_body,omitempty" gorm:"type:text"`
+	Error        *string   `json:"error,omitempty" gorm:"type:text"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
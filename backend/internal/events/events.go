@@ -0,0 +1,77 @@
+package events
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+	"weel-backend/internal/domain"
+)
+const (
+	OrderCreated         = "order.created"
+	OrderUpdated         = "order.updated"
+	OrderStatusChanged   = "order.status_changed"
+	OrderCancelled       = "order.cancelled"
+	OrderCustomerArrived = "order.customer_arrived"
+)
+var OrderEventTypes = []string{
+	OrderCreated,
+	OrderUpdated,
+	OrderStatusChanged,
+	OrderCancelled,
+	OrderCustomerArrived,
+}
+type Event struct {
+	ID         string        `json:"id"`
+	Type       string        `json:"type"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	Order      *domain.Order `json:"order"`
+}
+type Handler func(ctx context.Context, event Event)
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+func NewBus() *Bus {
+	return &Bus{}
+}
+func NewOrderEvent(eventType string, order *domain.Order) Event {
+	return Event{
+		ID:         NewID(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Order:      order,
+	}
+}
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := make([]Handler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+func IsOrderEventType(eventType string) bool {
+	for _, t := range OrderEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return hex.EncodeToString([]byte(time.Now().UTC().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
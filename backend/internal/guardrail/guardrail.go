@@ -0,0 +1,359 @@
+package guardrail
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"weel-backend/config"
+	"weel-backend/internal/domain"
+)
+const (
+	PrescriptionFlag        = "flag"
+	PrescriptionBlock       = "block"
+	CodeControlledSubstance = "controlled_substance"
+	CodePrescriptionOnly    = "prescription_only"
+	CodeQuantityLimit       = "quantity_limit"
+	CodeInteraction         = "drug_interaction"
+	CodeDuplicateIngredient = "duplicate_ingredient"
+	SeverityMajor           = "major"
+	SeverityModerate        = "moderate"
+	SeverityMinor           = "minor"
+)
+var (
+	ErrInvalidRules              = errors.New("invalid guardrail rules")
+	ErrInvalidPrescriptionAction = errors.New("guardrail prescription action must be flag or block")
+)
+//go:embed default_rules.json
+var defaultRules []byte
+type Interaction struct {
+	A           []string `json:"a"`
+	B           []string `json:"b"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+}
+type Rules struct {
+	DefaultMaxQuantity int               `json:"default_max_quantity"`
+	MaxQuantity        map[string]int    `json:"max_quantity"`
+	Controlled         []string          `json:"controlled"`
+	PrescriptionOnly   []string          `json:"prescription_only"`
+	Aliases            map[string]string `json:"aliases"`
+	Interactions       []Interaction     `json:"interactions"`
+}
+func DefaultRules() *Rules {
+	rules, err := ParseRules(defaultRules)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+func LoadRules(path string) (*Rules, error) {
+	if path == "" {
+		return DefaultRules(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read guardrail rules: %w", err)
+	}
+	return ParseRules(data)
+}
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	if rules.DefaultMaxQuantity < 0 {
+		return nil, fmt.Errorf("%w: default_max_quantity must not be negative", ErrInvalidRules)
+	}
+	for name, max := range rules.MaxQuantity {
+		if max <= 0 {
+			return nil, fmt.Errorf("%w: max_quantity for %q must be positive", ErrInvalidRules, name)
+		}
+	}
+	for i, in := range rules.Interactions {
+		if len(in.A) == 0 || len(in.B) == 0 {
+			return nil, fmt.Errorf("%w: interaction %d needs substances on both sides", ErrInvalidRules, i)
+		}
+		switch in.Severity {
+		case SeverityMajor, SeverityModerate, SeverityMinor:
+		default:
+			return nil, fmt.Errorf("%w: interaction %d has unknown severity %q", ErrInvalidRules, i, in.Severity)
+		}
+	}
+	return &rules, nil
+}
+type Result struct {
+	Products             []domain.AISuggestedProduct `json:"products"`
+	Warnings             []domain.SuggestionWarning  `json:"warnings"`
+	RequiresReview       bool                        `json:"requires_review"`
+	PrescriptionRequired bool                        `json:"prescription_required"`
+}
+type Checker struct {
+	rules             *Rules
+	blockPrescription bool
+	terms             []string
+	canonical         map[string]string
+	controlled        map[string]bool
+	prescriptionOnly  map[string]bool
+	maxQuantity       map[string]int
+}
+func NewChecker(rules *Rules, prescriptionAction string) (*Checker, error) {
+	c := &Checker{
+		rules:            rules,
+		canonical:        make(map[string]string),
+		controlled:       make(map[string]bool),
+		prescriptionOnly: make(map[string]bool),
+		maxQuantity:      make(map[string]int),
+	}
+	switch prescriptionAction {
+	case PrescriptionFlag, "":
+	case PrescriptionBlock:
+		c.blockPrescription = true
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPrescriptionAction, prescriptionAction)
+	}
+	add := func(name string) string {
+		n := normalize(name)
+		if n != "" {
+			if _, ok := c.canonical[n]; !ok {
+				c.canonical[n] = n
+			}
+		}
+		return n
+	}
+	for _, name := range rules.Controlled {
+		c.controlled[add(name)] = true
+	}
+	for _, name := range rules.PrescriptionOnly {
+		c.prescriptionOnly[add(name)] = true
+	}
+	for name, max := range rules.MaxQuantity {
+		c.maxQuantity[add(name)] = max
+	}
+	for _, in := range rules.Interactions {
+		for _, name := range append(append([]string{}, in.A...), in.B...) {
+			add(name)
+		}
+	}
+	for alias, name := range rules.Aliases {
+		if a := normalize(alias); a != "" {
+			c.canonical[a] = add(name)
+		}
+	}
+	for term := range c.canonical {
+		c.terms = append(c.terms, term)
+	}
+	sort.Strings(c.terms)
+	return c, nil
+}
+func New(cfg config.GuardrailConfig) (*Checker, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	rules, err := LoadRules(cfg.RulesPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewChecker(rules, cfg.PrescriptionAction)
+}
+func (c *Checker) Substances(text string) []string {
+	padded := " " + normalize(text) + " "
+	found := make(map[string]bool)
+	for _, term := range c.terms {
+		if strings.Contains(padded, " "+term+" ") {
+			found[c.canonical[term]] = true
+		}
+	}
+	substances := make([]string, 0, len(found))
+	for s := range found {
+		substances = append(substances, s)
+	}
+	sort.Strings(substances)
+	return substances
+}
+type checkedProduct struct {
+	product    domain.AISuggestedProduct
+	substances []string
+}
+func (c *Checker) Check(summary string, products []domain.AISuggestedProduct) Result {
+	result := Result{Products: []domain.AISuggestedProduct{}, Warnings: []domain.SuggestionWarning{}}
+	kept := make([]checkedProduct, 0, len(products))
+	for _, p := range products {
+		substances := c.Substances(p.Name)
+		if s := c.firstOf(substances, c.controlled); s != "" {
+			result.Warnings = append(result.Warnings, domain.SuggestionWarning{
+				Code:     CodeControlledSubstance,
+				Severity: domain.WarningSeverityCritical,
+				Action:   domain.GuardrailActionBlocked,
+				Product:  p.Name,
+				Message:  fmt.Sprintf("%s contains the controlled substance %s and was removed from the suggestions", p.Name, s),
+			})
+			continue
+		}
+		if s := c.firstOf(substances, c.prescriptionOnly); s != "" {
+			warning := domain.SuggestionWarning{
+				Code:     CodePrescriptionOnly,
+				Severity: domain.WarningSeverityWarning,
+				Action:   domain.GuardrailActionFlagged,
+				Product:  p.Name,
+				Message:  fmt.Sprintf("%s contains %s, which requires a valid prescription", p.Name, s),
+			}
+			if c.blockPrescription {
+				warning.Action = domain.GuardrailActionBlocked
+				warning.Message += "; it was removed from the suggestions"
+				result.Warnings = append(result.Warnings, warning)
+				continue
+			}
+			result.Warnings = append(result.Warnings, warning)
+			result.PrescriptionRequired = true
+		}
+		if max := c.maxQuantityFor(substances); max > 0 && p.Quantity > max {
+			result.Warnings = append(result.Warnings, domain.SuggestionWarning{
+				Code:     CodeQuantityLimit,
+				Severity: domain.WarningSeverityInfo,
+				Action:   domain.GuardrailActionAdjusted,
+				Product:  p.Name,
+				Message:  fmt.Sprintf("quantity of %s reduced from %d to the maximum of %d", p.Name, p.Quantity, max),
+			})
+			p.Quantity = max
+		}
+		kept = append(kept, checkedProduct{product: p, substances: substances})
+	}
+	result.Warnings = append(result.Warnings, c.duplicates(kept)...)
+	result.Warnings = append(result.Warnings, c.interactions(kept, c.Substances(summary))...)
+	for _, k := range kept {
+		result.Products = append(result.Products, k.product)
+	}
+	for _, w := range result.Warnings {
+		if w.Severity != domain.WarningSeverityInfo {
+			result.RequiresReview = true
+			break
+		}
+	}
+	return result
+}
+func (c *Checker) firstOf(substances []string, set map[string]bool) string {
+	for _, s := range substances {
+		if set[s] {
+			return s
+		}
+	}
+	return ""
+}
+func (c *Checker) maxQuantityFor(substances []string) int {
+	max := c.rules.DefaultMaxQuantity
+	for _, s := range substances {
+		if limit, ok := c.maxQuantity[s]; ok && (max == 0 || limit < max) {
+			max = limit
+		}
+	}
+	return max
+}
+func (c *Checker) duplicates(kept []checkedProduct) []domain.SuggestionWarning {
+	bySubstance := make(map[string][]string)
+	var order []string
+	for _, k := range kept {
+		for _, s := range k.substances {
+			if _, ok := bySubstance[s]; !ok {
+				order = append(order, s)
+			}
+			bySubstance[s] = append(bySubstance[s], k.product.Name)
+		}
+	}
+	var warnings []domain.SuggestionWarning
+	for _, s := range order {
+		if names := bySubstance[s]; len(names) > 1 {
+			warnings = append(warnings, domain.SuggestionWarning{
+				Code:     CodeDuplicateIngredient,
+				Severity: domain.WarningSeverityWarning,
+				Action:   domain.GuardrailActionFlagged,
+				Products: names,
+				Message:  fmt.Sprintf("%s contain the same active ingredient (%s); taking them together risks exceeding the safe dose", strings.Join(names, " and "), s),
+			})
+		}
+	}
+	return warnings
+}
+func (c *Checker) interactions(kept []checkedProduct, reported []string) []domain.SuggestionWarning {
+	var warnings []domain.SuggestionWarning
+	for _, in := range c.rules.Interactions {
+		a, b := c.normalizeAll(in.A), c.normalizeAll(in.B)
+		for i := range kept {
+			for j := i + 1; j < len(kept); j++ {
+				x, y := kept[i], kept[j]
+				sa, sb, ok := pairMatch(x.substances, y.substances, a, b)
+				if !ok {
+					continue
+				}
+				warnings = append(warnings, domain.SuggestionWarning{
+					Code:     CodeInteraction,
+					Severity: interactionSeverity(in.Severity),
+					Action:   domain.GuardrailActionFlagged,
+					Products: []string{x.product.Name, y.product.Name},
+					Message:  fmt.Sprintf("%s (%s) and %s (%s): %s", x.product.Name, sa, y.product.Name, sb, in.Description),
+				})
+			}
+			x := kept[i]
+			sa, sb, ok := pairMatch(x.substances, reported, a, b)
+			if !ok || contains(x.substances, sb) {
+				continue
+			}
+			warnings = append(warnings, domain.SuggestionWarning{
+				Code:     CodeInteraction,
+				Severity: interactionSeverity(in.Severity),
+				Action:   domain.GuardrailActionFlagged,
+				Product:  x.product.Name,
+				Message:  fmt.Sprintf("%s (%s) may interact with %s mentioned in the request: %s", x.product.Name, sa, sb, in.Description),
+			})
+		}
+	}
+	return warnings
+}
+func (c *Checker) normalizeAll(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		n := normalize(name)
+		if canonical, ok := c.canonical[n]; ok {
+			n = canonical
+		}
+		set[n] = true
+	}
+	return set
+}
+func pairMatch(x, y []string, a, b map[string]bool) (string, string, bool) {
+	for _, sx := range x {
+		for _, sy := range y {
+			if (a[sx] && b[sy]) || (b[sx] && a[sy]) {
+				return sx, sy, true
+			}
+		}
+	}
+	return "", "", false
+}
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+func interactionSeverity(severity string) domain.WarningSeverity {
+	switch severity {
+	case SeverityMajor:
+		return domain.WarningSeverityCritical
+	case SeverityModerate:
+		return domain.WarningSeverityWarning
+	default:
+		return domain.WarningSeverityInfo
+	}
+}
+func normalize(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
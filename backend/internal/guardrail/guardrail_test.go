@@ -0,0 +1,154 @@
+package guardrail_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/guardrail"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newChecker(t *testing.T, action string) *guardrail.Checker {
+	t.Helper()
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), action)
+	require.NoError(t, err)
+	return checker
+}
+func warningCodes(result guardrail.Result) []string {
+	codes := []string{}
+	for _, w := range result.Warnings {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+func TestSubstances(t *testing.T) {
+	checker := newChecker(t, guardrail.PrescriptionFlag)
+	assert.Equal(t, []string{"ibuprofen"}, checker.Substances("Advil Liqui-Gels 200mg"))
+	assert.Equal(t, []string{"acetaminophen"}, checker.Substances("Paracetamol 500 mg tablets"))
+	assert.Equal(t, []string{"st johns wort"}, checker.Substances("St. John's Wort capsules"))
+	assert.Equal(t, []string{"aspirin", "warfarin"}, checker.Substances("I take Coumadin and low-dose aspirin"))
+	assert.Empty(t, checker.Substances("Vitamin C gummies"))
+	assert.Empty(t, checker.Substances("codeinex"))
+}
+func TestCheck_BlocksControlledSubstances(t *testing.T) {
+	checker := newChecker(t, guardrail.PrescriptionFlag)
+	result := checker.Check("Can't sleep", []domain.AISuggestedProduct{
+		{Name: "Xanax 0.5mg", Quantity: 1},
+		{Name: "Melatonin 3mg", Quantity: 1},
+	})
+	require.Len(t, result.Products, 1)
+	assert.Equal(t, "Melatonin 3mg", result.Products[0].Name)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, guardrail.CodeControlledSubstance, result.Warnings[0].Code)
+	assert.Equal(t, domain.WarningSeverityCritical, result.Warnings[0].Severity)
+	assert.Equal(t, domain.GuardrailActionBlocked, result.Warnings[0].Action)
+	assert.True(t, result.RequiresReview)
+}
+func TestCheck_PrescriptionOnly(t *testing.T) {
+	products := []domain.AISuggestedProduct{{Name: "Amoxicillin 500mg", Quantity: 1}}
+	flagged := newChecker(t, guardrail.PrescriptionFlag).Check("Sore throat", products)
+	require.Len(t, flagged.Products, 1)
+	assert.Equal(t, []string{guardrail.CodePrescriptionOnly}, warningCodes(flagged))
+	assert.Equal(t, domain.GuardrailActionFlagged, flagged.Warnings[0].Action)
+	assert.True(t, flagged.RequiresReview)
+	assert.True(t, flagged.PrescriptionRequired)
+	blocked := newChecker(t, guardrail.PrescriptionBlock).Check("Sore throat", products)
+	assert.Empty(t, blocked.Products)
+	assert.False(t, blocked.PrescriptionRequired)
+	assert.Equal(t, domain.GuardrailActionBlocked, blocked.Warnings[0].Action)
+}
+func TestCheck_ClampsQuantities(t *testing.T) {
+	checker := newChecker(t, guardrail.PrescriptionFlag)
+	result := checker.Check("Blocked nose", []domain.AISuggestedProduct{
+		{Name: "Sudafed 12 hour", Quantity: 4},
+		{Name: "Saline nasal spray", Quantity: 5},
+		{Name: "Tissues", Quantity: 2},
+	})
+	require.Len(t, result.Products, 3)
+	assert.Equal(t, 1, result.Products[0].Quantity)
+	assert.Equal(t, 3, result.Products[1].Quantity)
+	assert.Equal(t, 2, result.Products[2].Quantity)
+	assert.Equal(t, []string{guardrail.CodeQuantityLimit, guardrail.CodeQuantityLimit}, warningCodes(result))
+	assert.Equal(t, domain.GuardrailActionAdjusted, result.Warnings[0].Action)
+	assert.False(t, result.RequiresReview)
+}
+func TestCheck_Interactions(t *testing.T) {
+	checker := newChecker(t, guardrail.PrescriptionFlag)
+	result := checker.Check("Knee pain", []domain.AISuggestedProduct{
+		{Name: "Aleve 220mg", Quantity: 1},
+		{Name: "Bayer Aspirin 81mg", Quantity: 1},
+	})
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, guardrail.CodeInteraction, result.Warnings[0].Code)
+	assert.Equal(t, domain.WarningSeverityWarning, result.Warnings[0].Severity)
+	assert.Equal(t, []string{"Aleve 220mg", "Bayer Aspirin 81mg"}, result.Warnings[0].Products)
+	result = checker.Check("Headache, I'm on Coumadin for my heart", []domain.AISuggestedProduct{
+		{Name: "Ibuprofen 200mg", Quantity: 1},
+		{Name: "Tylenol Extra Strength", Quantity: 1},
+	})
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, guardrail.CodeInteraction, result.Warnings[0].Code)
+	assert.Equal(t, domain.WarningSeverityCritical, result.Warnings[0].Severity)
+	assert.Equal(t, "Ibuprofen 200mg", result.Warnings[0].Product)
+	assert.Contains(t, result.Warnings[0].Message, "warfarin")
+	assert.True(t, result.RequiresReview)
+}
+func TestCheck_DuplicateIngredient(t *testing.T) {
+	checker := newChecker(t, guardrail.PrescriptionFlag)
+	result := checker.Check("Fever", []domain.AISuggestedProduct{
+		{Name: "Tylenol 500mg", Quantity: 1},
+		{Name: "Panadol Cold & Flu", Quantity: 1},
+	})
+	assert.Equal(t, []string{guardrail.CodeDuplicateIngredient}, warningCodes(result))
+	assert.Equal(t, []string{"Tylenol 500mg", "Panadol Cold & Flu"}, result.Warnings[0].Products)
+}
+func TestCheck_NoWarnings(t *testing.T) {
+	checker := newChecker(t, guardrail.PrescriptionFlag)
+	result := checker.Check("Dry skin", []domain.AISuggestedProduct{{Name: "Moisturizing lotion", Quantity: 1}})
+	assert.Len(t, result.Products, 1)
+	assert.Empty(t, result.Warnings)
+	assert.False(t, result.RequiresReview)
+	assert.False(t, result.PrescriptionRequired)
+}
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"default_max_quantity": 2,
+		"controlled": ["ketamine"],
+		"aliases": {"brand x": "foo"},
+		"interactions": [{"a": ["foo"], "b": ["bar"], "severity": "major", "description": "do not combine"}]
+	}`), 0o600))
+	rules, err := guardrail.LoadRules(path)
+	require.NoError(t, err)
+	checker, err := guardrail.NewChecker(rules, guardrail.PrescriptionFlag)
+	require.NoError(t, err)
+	result := checker.Check("", []domain.AISuggestedProduct{
+		{Name: "Brand X", Quantity: 3},
+		{Name: "Bar tablets", Quantity: 1},
+		{Name: "Ketamine", Quantity: 1},
+	})
+	assert.Len(t, result.Products, 2)
+	assert.Equal(t, 2, result.Products[0].Quantity)
+	assert.ElementsMatch(t, []string{guardrail.CodeControlledSubstance, guardrail.CodeQuantityLimit, guardrail.CodeInteraction}, warningCodes(result))
+}
+func TestParseRules_Invalid(t *testing.T) {
+	cases := []string{
+		`not json`,
+		`{"default_max_quantity": -1}`,
+		`{"max_quantity": {"foo": 0}}`,
+		`{"interactions": [{"a": ["foo"], "b": [], "severity": "major"}]}`,
+		`{"interactions": [{"a": ["foo"], "b": ["bar"], "severity": "deadly"}]}`,
+	}
+	for _, tc := range cases {
+		_, err := guardrail.ParseRules([]byte(tc))
+		assert.True(t, errors.Is(err, guardrail.ErrInvalidRules), tc)
+	}
+}
+func TestNewChecker_InvalidAction(t *testing.T) {
+	_, err := guardrail.NewChecker(guardrail.DefaultRules(), "ignore")
+	assert.ErrorIs(t, err, guardrail.ErrInvalidPrescriptionAction)
+}
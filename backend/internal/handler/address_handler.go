@@ -0,0 +1,157 @@
+package handler
+import (
+	"net/http"
+	"strconv"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type AddressHandler struct {
+	addressService service.AddressService
+}
+func NewAddressHandler(addressService service.AddressService) *AddressHandler {
+	return &AddressHandler{addressService: addressService}
+}
+func (h *AddressHandler) RegisterRoutes(router *gin.RouterGroup) {
+	addresses := router.Group("/me/addresses")
+	{
+		addresses.GET("", h.ListAddresses)
+		addresses.POST("", h.CreateAddress)
+		addresses.GET("/:id", h.GetAddress)
+		addresses.PUT("/:id", h.UpdateAddress)
+		addresses.DELETE("/:id", h.DeleteAddress)
+		addresses.POST("/:id/default", h.SetDefaultAddress)
+	}
+}
+func (h *AddressHandler) ListAddresses(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	addresses, err := h.addressService.ListAddresses(c.Request.Context(), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch addresses"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  addresses,
+		"count": len(addresses),
+	})
+}
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req service.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	address, err := h.addressService.CreateAddress(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if isAddressValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create address"})
+		return
+	}
+	c.JSON(http.StatusCreated, address)
+}
+func (h *AddressHandler) GetAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address ID"})
+		return
+	}
+	address, err := h.addressService.GetAddress(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address ID"})
+		return
+	}
+	var req service.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	address, err := h.addressService.UpdateAddress(c.Request.Context(), userID.(uint), uint(id), &req)
+	if err != nil {
+		if err == service.ErrAddressNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if isAddressValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update address"})
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address ID"})
+		return
+	}
+	if err := h.addressService.DeleteAddress(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		if err == service.ErrAddressNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete address"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "address deleted successfully"})
+}
+func (h *AddressHandler) SetDefaultAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address ID"})
+		return
+	}
+	address, err := h.addressService.SetDefaultAddress(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		if err == service.ErrAddressNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set default address"})
+		return
+	}
+	c.JSON(http.StatusOK, address)
+}
+func isAddressValidationError(err error) bool {
+	return err == service.ErrInvalidAddress || err == service.ErrInvalidCountry || err == service.ErrInvalidPostalCode
+}
@@ -0,0 +1,94 @@
+package handler
+import (
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type AIQuotaHandler struct {
+	quotaService service.AIQuotaService
+}
+func NewAIQuotaHandler(quotaService service.AIQuotaService) *AIQuotaHandler {
+	return &AIQuotaHandler{quotaService: quotaService}
+}
+func (h *AIQuotaHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/orders/suggestions/quota", h.GetMyQuota)
+	admin := router.Group("/users/:id/ai-quota")
+	admin.Use(middleware.RequireRole(domain.UserRoleAdmin))
+	{
+		admin.GET("", h.GetUserQuota)
+		admin.PUT("", h.SetUserQuota)
+		admin.DELETE("", h.ResetUserQuota)
+	}
+}
+func (h *AIQuotaHandler) GetMyQuota(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	status, err := h.quotaService.GetStatus(c.Request.Context(), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get AI suggestion quota"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+func (h *AIQuotaHandler) GetUserQuota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	status, err := h.quotaService.GetStatus(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get AI suggestion quota"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+func (h *AIQuotaHandler) SetUserQuota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	var req service.AIQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status, err := h.quotaService.SetLimit(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidInput {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update AI suggestion quota"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+func (h *AIQuotaHandler) ResetUserQuota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	status, err := h.quotaService.ResetLimit(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset AI suggestion quota"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
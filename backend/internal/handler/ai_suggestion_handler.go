@@ -0,0 +1,70 @@
+package handler
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type AISuggestionHandler struct {
+	suggestionService service.AISuggestionService
+}
+func NewAISuggestionHandler(suggestionService service.AISuggestionService) *AISuggestionHandler {
+	return &AISuggestionHandler{suggestionService: suggestionService}
+}
+func (h *AISuggestionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/orders/suggestions/:id", h.GetSession)
+	admin := router.Group("/ai-usage")
+	admin.Use(middleware.RequireRole(domain.UserRoleAdmin))
+	{
+		admin.GET("/suggestions", h.GetStats)
+	}
+}
+func (h *AISuggestionHandler) GetSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid suggestion session ID"})
+		return
+	}
+	role, _ := c.Get("userRole")
+	userRole, _ := role.(domain.UserRole)
+	session, err := h.suggestionService.GetSession(c.Request.Context(), uint(id), userID.(uint), userRole)
+	if err != nil {
+		if err == service.ErrSuggestionSessionNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get AI suggestion session"})
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+func (h *AISuggestionHandler) GetStats(c *gin.Context) {
+	var query service.AISuggestionStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.suggestionService.Stats(c.Request.Context(), &query)
+	if err != nil {
+		var filterErr *service.InvalidFilterError
+		if errors.As(err, &filterErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": filterErr.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build AI suggestion stats"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,40 @@
+package handler
+import (
+	"errors"
+	"net/http"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type AIUsageHandler struct {
+	usageService service.AIUsageService
+}
+func NewAIUsageHandler(usageService service.AIUsageService) *AIUsageHandler {
+	return &AIUsageHandler{usageService: usageService}
+}
+func (h *AIUsageHandler) RegisterRoutes(router *gin.RouterGroup) {
+	usage := router.Group("/ai-usage")
+	usage.Use(middleware.RequireRole(domain.UserRoleAdmin))
+	{
+		usage.GET("/report", h.GetReport)
+	}
+}
+func (h *AIUsageHandler) GetReport(c *gin.Context) {
+	var query service.AIUsageReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.usageService.Report(c.Request.Context(), &query)
+	if err != nil {
+		var filterErr *service.InvalidFilterError
+		if errors.As(err, &filterErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": filterErr.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build AI usage report"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
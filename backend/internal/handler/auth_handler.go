@@ -30,7 +30,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	response, err := h.authService.Login(req.Email, req.Password)
+	response, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		if err == service.ErrInvalidCredentials {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
@@ -47,7 +47,7 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
-	user, err := h.authService.GetCurrentUser(userID.(uint))
+	user, err := h.authService.GetCurrentUser(c.Request.Context(), userID.(uint))
 	if err != nil {
 		if err == service.ErrUserNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -0,0 +1,126 @@
+package handler
+import (
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type DeliveryHandler struct {
+	zoneService service.DeliveryZoneService
+}
+func NewDeliveryHandler(zoneService service.DeliveryZoneService) *DeliveryHandler {
+	return &DeliveryHandler{zoneService: zoneService}
+}
+func (h *DeliveryHandler) RegisterRoutes(router *gin.RouterGroup) {
+	zones := router.Group("/delivery/zones")
+	zones.Use(middleware.RequireRole(domain.UserRoleAdmin))
+	{
+		zones.GET("", h.ListZones)
+		zones.POST("", h.CreateZone)
+		zones.GET("/:id", h.GetZone)
+		zones.PUT("/:id", h.UpdateZone)
+		zones.DELETE("/:id", h.DeleteZone)
+	}
+}
+func (h *DeliveryHandler) GetQuote(c *gin.Context) {
+	var req service.DeliveryQuoteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	quote, err := h.zoneService.Quote(c.Request.Context(), &req)
+	if err != nil {
+		if err == service.ErrInvalidCountry || err == service.ErrInvalidPostalCode || err == service.ErrPostalCodeRequired {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to quote delivery"})
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+func (h *DeliveryHandler) ListZones(c *gin.Context) {
+	zones, err := h.zoneService.ListZones(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch delivery zones"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  zones,
+		"count": len(zones),
+	})
+}
+func (h *DeliveryHandler) GetZone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery zone ID"})
+		return
+	}
+	zone, err := h.zoneService.GetZone(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, zone)
+}
+func (h *DeliveryHandler) CreateZone(c *gin.Context) {
+	var req service.DeliveryZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	zone, err := h.zoneService.CreateZone(c.Request.Context(), &req)
+	if err != nil {
+		if err == service.ErrInvalidDeliveryZone || err == service.ErrInvalidCountry {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create delivery zone"})
+		return
+	}
+	c.JSON(http.StatusCreated, zone)
+}
+func (h *DeliveryHandler) UpdateZone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery zone ID"})
+		return
+	}
+	var req service.DeliveryZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	zone, err := h.zoneService.UpdateZone(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if err == service.ErrDeliveryZoneNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidDeliveryZone || err == service.ErrInvalidCountry {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update delivery zone"})
+		return
+	}
+	c.JSON(http.StatusOK, zone)
+}
+func (h *DeliveryHandler) DeleteZone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery zone ID"})
+		return
+	}
+	if err := h.zoneService.DeleteZone(c.Request.Context(), uint(id)); err != nil {
+		if err == service.ErrDeliveryZoneNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete delivery zone"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "delivery zone deleted successfully"})
+}
@@ -18,7 +18,7 @@ func (h *FeatureFlagHandler) RegisterRoutes(router *gin.RouterGroup) {
 	}
 }
 func (h *FeatureFlagHandler) GetAllFlags(c *gin.Context) {
-	flags, err := h.flagService.GetAllFlags()
+	flags, err := h.flagService.GetAllFlags(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch feature flags"})
 		return
@@ -34,7 +34,7 @@ func (h *FeatureFlagHandler) GetAllFlags(c *gin.Context) {
 }
 func (h *FeatureFlagHandler) GetFlagByName(c *gin.Context) {
 	name := c.Param("name")
-	flag, err := h.flagService.GetFlagByName(name)
+	flag, err := h.flagService.GetFlagByName(c.Request.Context(), name)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "feature flag not found"})
 		return
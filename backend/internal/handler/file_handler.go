@@ -0,0 +1,42 @@
+package handler
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"weel-backend/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+type FileHandler struct {
+	store *storage.LocalStore
+}
+func NewFileHandler(store *storage.LocalStore) *FileHandler {
+	return &FileHandler{store: store}
+}
+func (h *FileHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/files/*key", h.ServeFile)
+}
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := h.store.VerifySignedURL(key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": storage.ErrInvalidSignature.Error()})
+		return
+	}
+	rc, obj, err := h.store.Get(c.Request.Context(), key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer rc.Close()
+	if obj.ContentType != "" {
+		c.Header("Content-Type", obj.ContentType)
+	}
+	c.Header("Content-Length", strconv.FormatInt(obj.Size, 10))
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, rc)
+}
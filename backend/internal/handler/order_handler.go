@@ -1,5 +1,6 @@
 package handler
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"weel-backend/internal/service"
@@ -18,23 +19,31 @@ func (h *OrderHandler) RegisterRoutes(router *gin.RouterGroup) {
 		orders.POST("", h.CreateOrder)
 		orders.GET("/:id", h.GetOrder)
 		orders.PUT("/:id", h.UpdateOrder)
+		orders.POST("/:id/cancel", h.CancelOrder)
+		orders.POST("/:id/arrived", h.CheckIn)
 	}
 }
 func (h *OrderHandler) GetAISuggestions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 	var req service.GetAISuggestionsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	suggestions, err := h.orderService.GetAISuggestions(&req)
+	result, err := h.orderService.GetAISuggestions(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
+		if err == service.ErrAIQuotaExceeded {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "quota": result.Quota})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get AI suggestions"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"suggestions": suggestions,
-		"count":       len(suggestions),
-	})
+	c.JSON(http.StatusOK, result)
 }
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -47,12 +56,28 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	order, err := h.orderService.CreateOrder(userID.(uint), &req)
+	order, err := h.orderService.CreateOrder(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
-		if err == service.ErrInvalidInput {
+		if err == service.ErrInvalidInput || err == service.ErrAddressNotFound || err == service.ErrInvalidAddress ||
+			err == service.ErrInvalidCountry || err == service.ErrInvalidPostalCode || err == service.ErrPostalCodeRequired ||
+			err == service.ErrStoreNotFound || err == service.ErrPickupStoreRequired || err == service.ErrInvalidPickupSlot ||
+			err == service.ErrSuggestionSessionNotFound {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if err == service.ErrOutOfDeliveryZone || err == service.ErrBelowMinimumOrder || err == service.ErrPickupNotConfigured ||
+			err == service.ErrDeliveryPreferenceNotSupported {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrProductNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrPickupSlotFull || err == service.ErrSuggestionSessionUsed {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create order"})
 		return
 	}
@@ -69,14 +94,32 @@ func (h *OrderHandler) GetOrders(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	orders, err := h.orderService.GetOrders(userID.(uint), &filters)
+	orders, page, err := h.orderService.GetOrders(c.Request.Context(), userID.(uint), &filters)
 	if err != nil {
+		var sortErr *service.InvalidSortError
+		if errors.As(err, &sortErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":               sortErr.Error(),
+				"allowed_sort_fields": sortErr.Allowed,
+			})
+			return
+		}
+		var filterErr *service.InvalidFilterError
+		if errors.As(err, &filterErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": filterErr.Error()})
+			return
+		}
+		if err == service.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch orders"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"orders": orders,
-		"count":  len(orders),
+		"data":       orders,
+		"count":      len(orders),
+		"pagination": page,
 	})
 }
 func (h *OrderHandler) GetOrder(c *gin.Context) {
@@ -90,7 +133,7 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
 		return
 	}
-	order, err := h.orderService.GetOrderByID(uint(id), userID.(uint))
+	order, err := h.orderService.GetOrderByID(c.Request.Context(), uint(id), userID.(uint))
 	if err != nil {
 		if err == service.ErrOrderNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -121,13 +164,13 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	order, err := h.orderService.UpdateOrder(uint(id), userID.(uint), &req)
+	order, err := h.orderService.UpdateOrder(c.Request.Context(), uint(id), userID.(uint), userRole(c), &req)
 	if err != nil {
 		if err == service.ErrOrderNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
-		if err == service.ErrUnauthorizedAccess {
+		if err == service.ErrUnauthorizedAccess || err == service.ErrStatusChangeNotAllowed {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
@@ -135,8 +178,92 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrProductNotAllowed) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrOrderAlreadyCancelled || err == service.ErrOrderNotCancellable || err == service.ErrCancellationWindowOver || err == service.ErrPrescriptionNotApproved {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update order"})
 		return
 	}
 	c.JSON(http.StatusOK, order)
 }
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+	var req service.CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	order, err := h.orderService.CancelOrder(c.Request.Context(), uint(id), userID.(uint), &req)
+	if err != nil {
+		if err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidCancelReason {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrOrderAlreadyCancelled || err == service.ErrOrderNotCancellable || err == service.ErrCancellationWindowOver {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel order"})
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+func (h *OrderHandler) CheckIn(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+	var req service.CheckInRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	order, err := h.orderService.CheckIn(c.Request.Context(), uint(id), userID.(uint), &req)
+	if err != nil {
+		if err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrCheckInNotAllowed || err == service.ErrAlreadyCheckedIn {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check in"})
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,119 @@
+package handler
+import (
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type PickupHandler struct {
+	pickupService service.PickupService
+	staff         middleware.StoreStaffLookup
+}
+func NewPickupHandler(pickupService service.PickupService, staff middleware.StoreStaffLookup) *PickupHandler {
+	return &PickupHandler{pickupService: pickupService, staff: staff}
+}
+func (h *PickupHandler) RegisterRoutes(router *gin.RouterGroup) {
+	stores := router.Group("/stores/:id")
+	{
+		config := stores.Group("/pickup-config")
+		config.Use(middleware.RequireRole(domain.UserRoleAdmin))
+		config.GET("", h.GetConfig)
+		config.PUT("", h.UpdateConfig)
+		arrivals := stores.Group("/arrivals")
+		arrivals.Use(middleware.RequireRole(domain.UserRolePharmacist, domain.UserRoleAdmin), middleware.RequireStoreAccess(h.staff, "id"))
+		arrivals.GET("", h.ListArrivals)
+	}
+}
+func (h *PickupHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/stores/:id/pickup-slots", h.GetSlots)
+}
+func (h *PickupHandler) GetSlots(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	var query service.PickupSlotsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	slots, err := h.pickupService.AvailableSlots(c.Request.Context(), uint(id), &query)
+	if err != nil {
+		if err == service.ErrStoreNotFound || err == service.ErrPickupNotConfigured {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidPickupSlot {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must use YYYY-MM-DD"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch pickup slots"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  slots,
+		"count": len(slots),
+	})
+}
+func (h *PickupHandler) GetConfig(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	config, err := h.pickupService.GetConfig(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, config)
+}
+func (h *PickupHandler) UpdateConfig(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	var req service.PickupConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	config, err := h.pickupService.UpdateConfig(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidPickupConfig {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update pickup config"})
+		return
+	}
+	c.JSON(http.StatusOK, config)
+}
+func (h *PickupHandler) ListArrivals(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	orders, err := h.pickupService.ListArrivals(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch arrivals"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  orders,
+		"count": len(orders),
+	})
+}
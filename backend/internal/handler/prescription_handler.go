@@ -0,0 +1,244 @@
+package handler
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"weel-backend/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+type PrescriptionHandler struct {
+	prescriptionService service.PrescriptionService
+}
+func NewPrescriptionHandler(prescriptionService service.PrescriptionService) *PrescriptionHandler {
+	return &PrescriptionHandler{prescriptionService: prescriptionService}
+}
+func (h *PrescriptionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/orders/:id/prescriptions", h.UploadPrescription)
+	router.GET("/orders/:id/prescriptions", h.ListOrderPrescriptions)
+	router.GET("/prescriptions/:id/file", h.DownloadPrescription)
+	router.GET("/prescriptions/:id/url", h.GetPrescriptionURL)
+	reviewers := router.Group("/prescriptions")
+	reviewers.Use(middleware.RequireRole(domain.UserRolePharmacist, domain.UserRoleAdmin))
+	{
+		reviewers.GET("", h.ListQueue)
+		reviewers.POST("/:id/review", h.ReviewPrescription)
+	}
+}
+func (h *PrescriptionHandler) UploadPrescription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxPrescriptionSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > service.MaxPrescriptionSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrPrescriptionTooLarge.Error()})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+	prescription, err := h.prescriptionService.Upload(c.Request.Context(), uint(id), userID.(uint), filepath.Base(fileHeader.Filename), file)
+	if err != nil {
+		if err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrPrescriptionTooLarge {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnsupportedFileType {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrPrescriptionNotRequired || err == service.ErrPrescriptionReviewed {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload prescription"})
+		return
+	}
+	c.JSON(http.StatusCreated, prescription)
+}
+func (h *PrescriptionHandler) ListOrderPrescriptions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+	prescriptions, err := h.prescriptionService.ListForOrder(c.Request.Context(), uint(id), userID.(uint), userRole(c))
+	if err != nil {
+		if err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch prescriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  prescriptions,
+		"count": len(prescriptions),
+	})
+}
+func (h *PrescriptionHandler) ListQueue(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	status := domain.PrescriptionStatus(c.Query("status"))
+	prescriptions, err := h.prescriptionService.ListQueue(c.Request.Context(), userID.(uint), userRole(c), status)
+	if err != nil {
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch prescriptions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  prescriptions,
+		"count": len(prescriptions),
+	})
+}
+func (h *PrescriptionHandler) DownloadPrescription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prescription ID"})
+		return
+	}
+	rc, prescription, err := h.prescriptionService.Open(c.Request.Context(), uint(id), userID.(uint), userRole(c))
+	if err != nil {
+		if err == service.ErrPrescriptionNotFound || err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read prescription"})
+		return
+	}
+	defer rc.Close()
+	c.Header("Content-Disposition", "inline; filename=\""+prescription.FileName+"\"")
+	c.Header("Content-Type", prescription.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(prescription.SizeBytes, 10))
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, rc)
+}
+func (h *PrescriptionHandler) GetPrescriptionURL(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prescription ID"})
+		return
+	}
+	url, expiresAt, err := h.prescriptionService.SignedURL(c.Request.Context(), uint(id), userID.(uint), userRole(c))
+	if err != nil {
+		if err == service.ErrPrescriptionNotFound || err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err == storage.ErrSigningDisabled {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create download link"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"url":        url,
+		"expires_at": expiresAt,
+	})
+}
+func (h *PrescriptionHandler) ReviewPrescription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prescription ID"})
+		return
+	}
+	var req service.ReviewPrescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	prescription, err := h.prescriptionService.Review(c.Request.Context(), uint(id), userID.(uint), userRole(c), &req)
+	if err != nil {
+		if err == service.ErrPrescriptionNotFound || err == service.ErrOrderNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidReviewDecision {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrPrescriptionReviewed {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrUnauthorizedAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to review prescription"})
+		return
+	}
+	c.JSON(http.StatusOK, prescription)
+}
+func userRole(c *gin.Context) domain.UserRole {
+	role, exists := c.Get("userRole")
+	if !exists {
+		return domain.UserRoleCustomer
+	}
+	return role.(domain.UserRole)
+}
@@ -0,0 +1,118 @@
+package handler
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type PromptHandler struct {
+	promptService service.PromptService
+}
+func NewPromptHandler(promptService service.PromptService) *PromptHandler {
+	return &PromptHandler{promptService: promptService}
+}
+func (h *PromptHandler) RegisterRoutes(router *gin.RouterGroup) {
+	prompts := router.Group("/prompts")
+	prompts.Use(middleware.RequireRole(domain.UserRoleAdmin))
+	{
+		prompts.GET("", h.ListPrompts)
+		prompts.GET("/:name", h.GetPrompt)
+		prompts.POST("/:name/versions", h.CreateVersion)
+		prompts.PUT("/:name/versions/:version/activate", h.ActivateVersion)
+		prompts.POST("/:name/preview", h.Preview)
+	}
+}
+func (h *PromptHandler) ListPrompts(c *gin.Context) {
+	lists, err := h.promptService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list prompt templates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  lists,
+		"count": len(lists),
+	})
+}
+func (h *PromptHandler) GetPrompt(c *gin.Context) {
+	list, err := h.promptService.Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		if err == service.ErrPromptTemplateNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get prompt template"})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+func (h *PromptHandler) CreateVersion(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req service.PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template, err := h.promptService.Create(c.Request.Context(), c.Param("name"), userID.(uint), &req)
+	if err != nil {
+		if err == service.ErrPromptTemplateNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPromptTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create prompt template"})
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+func (h *PromptHandler) ActivateVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prompt version"})
+		return
+	}
+	template, err := h.promptService.Activate(c.Request.Context(), c.Param("name"), version)
+	if err != nil {
+		if err == service.ErrPromptTemplateNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrPromptTemplateRetired {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to activate prompt template"})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+func (h *PromptHandler) Preview(c *gin.Context) {
+	var req service.PromptPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rendered, err := h.promptService.Preview(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		if err == service.ErrPromptTemplateNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidPromptTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render prompt template"})
+		return
+	}
+	c.JSON(http.StatusOK, rendered)
+}
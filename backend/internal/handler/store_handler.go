@@ -0,0 +1,251 @@
+package handler
+import (
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type StoreHandler struct {
+	storeService service.StoreService
+}
+func NewStoreHandler(storeService service.StoreService) *StoreHandler {
+	return &StoreHandler{storeService: storeService}
+}
+func (h *StoreHandler) RegisterRoutes(router *gin.RouterGroup) {
+	admin := router.Group("/stores")
+	admin.Use(middleware.RequireRole(domain.UserRoleAdmin))
+	{
+		admin.POST("", h.CreateStore)
+		admin.PUT("/:id", h.UpdateStore)
+		admin.GET("/:id/staff", h.ListStaff)
+		admin.PUT("/:id/staff/:user_id", h.AssignStaff)
+		admin.DELETE("/:id/staff/:user_id", h.RemoveStaff)
+	}
+	staff := router.Group("/stores/:id")
+	staff.Use(middleware.RequireRole(domain.UserRolePharmacist, domain.UserRoleAdmin), middleware.RequireStoreAccess(h.storeService, "id"))
+	{
+		staff.GET("/orders", h.OrderQueue)
+		staff.GET("/inventory", h.ListInventory)
+		staff.PUT("/inventory/:sku", h.UpsertInventoryItem)
+	}
+}
+func (h *StoreHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/stores", h.ListStores)
+	router.GET("/stores/:id", h.GetStore)
+}
+func (h *StoreHandler) ListStores(c *gin.Context) {
+	stores, err := h.storeService.ListStores(c.Request.Context(), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch stores"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  stores,
+		"count": len(stores),
+	})
+}
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	store, err := h.storeService.GetStore(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, store)
+}
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	var req service.StoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	store, err := h.storeService.CreateStore(c.Request.Context(), &req)
+	if err != nil {
+		if isStoreValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create store"})
+		return
+	}
+	c.JSON(http.StatusCreated, store)
+}
+func (h *StoreHandler) UpdateStore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	var req service.StoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	store, err := h.storeService.UpdateStore(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if isStoreValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update store"})
+		return
+	}
+	c.JSON(http.StatusOK, store)
+}
+func (h *StoreHandler) ListStaff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	users, err := h.storeService.ListStaff(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch staff"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  users,
+		"count": len(users),
+	})
+}
+func (h *StoreHandler) AssignStaff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	user, err := h.storeService.AssignStaff(c.Request.Context(), uint(id), uint(userID))
+	if err != nil {
+		if err == service.ErrStoreNotFound || err == service.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrNotStaff {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to assign staff"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+func (h *StoreHandler) RemoveStaff(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	if err := h.storeService.RemoveStaff(c.Request.Context(), uint(id), uint(userID)); err != nil {
+		if err == service.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove staff"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "staff removed from store"})
+}
+func (h *StoreHandler) OrderQueue(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	var query service.StoreQueueQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	orders, err := h.storeService.OrderQueue(c.Request.Context(), uint(id), &query)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch store orders"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  orders,
+		"count": len(orders),
+	})
+}
+func (h *StoreHandler) ListInventory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	var query service.InventoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	items, err := h.storeService.ListInventory(c.Request.Context(), uint(id), &query)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch inventory"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"count": len(items),
+	})
+}
+func (h *StoreHandler) UpsertInventoryItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+		return
+	}
+	var req service.InventoryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item, err := h.storeService.UpsertInventoryItem(c.Request.Context(), uint(id), c.Param("sku"), &req)
+	if err != nil {
+		if err == service.ErrStoreNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidSKU {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save inventory item"})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+func isStoreValidationError(err error) bool {
+	return err == service.ErrInvalidTimezone || err == service.ErrInvalidOpeningHours || err == service.ErrInvalidAddress ||
+		err == service.ErrInvalidCountry || err == service.ErrInvalidPostalCode
+}
@@ -2,6 +2,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"weel-backend/internal/pagination"
 	"weel-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -27,7 +28,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.userService.CreateUser(c.Request.Context(), &req)
 	if err != nil {
 		if err == service.ErrEmailExists {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
@@ -44,7 +45,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
 		return
 	}
-	user, err := h.userService.GetUserByID(uint(id))
+	user, err := h.userService.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
 		if err == service.ErrUserNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -66,7 +67,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	user, err := h.userService.UpdateUser(uint(id), &req)
+	user, err := h.userService.UpdateUser(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		if err == service.ErrUserNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -87,7 +88,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
 		return
 	}
-	err = h.userService.DeleteUser(uint(id))
+	err = h.userService.DeleteUser(c.Request.Context(), uint(id))
 	if err != nil {
 		if err == service.ErrUserNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -99,23 +100,23 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
 }
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	if limit <= 0 {
-		limit = 10
-	}
-	if offset < 0 {
-		offset = 0
+	var params pagination.Params
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	users, total, err := h.userService.ListUsers(limit, offset)
+	users, page, err := h.userService.ListUsers(c.Request.Context(), params)
 	if err != nil {
+		if err == service.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list users"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"data":   users,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"data":       users,
+		"count":      len(users),
+		"pagination": page,
 	})
 }
@@ -0,0 +1,227 @@
+package handler
+import (
+	"net/http"
+	"strconv"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.GET("", h.ListWebhooks)
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.GET("/:id", h.GetWebhook)
+		webhooks.PUT("/:id", h.UpdateWebhook)
+		webhooks.DELETE("/:id", h.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.ListDeliveries)
+		webhooks.GET("/:id/deliveries/:deliveryId", h.GetDelivery)
+		webhooks.POST("/:id/deliveries/:deliveryId/redeliver", h.Redeliver)
+	}
+}
+type createWebhookResponse struct {
+	*domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var req service.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := h.webhookService.CreateSubscription(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		if err == service.ErrInvalidWebhookURL || err == service.ErrInvalidWebhookEvent {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create webhook"})
+		return
+	}
+	c.JSON(http.StatusCreated, createWebhookResponse{WebhookSubscription: sub, Secret: sub.Secret})
+}
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	subs, err := h.webhookService.ListSubscriptions(c.Request.Context(), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	sub, err := h.webhookService.GetSubscription(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		if err == service.ErrWebhookNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	var req service.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := h.webhookService.UpdateSubscription(c.Request.Context(), uint(id), userID.(uint), &req)
+	if err != nil {
+		if err == service.ErrWebhookNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrInvalidWebhookURL || err == service.ErrInvalidWebhookEvent {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		if err == service.ErrWebhookNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted successfully"})
+}
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), uint(id), userID.(uint), limit)
+	if err != nil {
+		if err == service.ErrWebhookNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch webhook deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery ID"})
+		return
+	}
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), uint(id), uint(deliveryID), userID.(uint))
+	if err != nil {
+		if err == service.ErrWebhookNotFound || err == service.ErrWebhookDeliveryNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get webhook delivery"})
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery ID"})
+		return
+	}
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), uint(id), uint(deliveryID), userID.(uint))
+	if err != nil {
+		if err == service.ErrWebhookNotFound || err == service.ErrWebhookDeliveryNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err == service.ErrWebhookDeliveryInProgress {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to redeliver webhook"})
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
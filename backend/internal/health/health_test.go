@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(ctx context.Context) error { return nil }
+
+func failing(ctx context.Context) error { return errors.New("connection refused") }
+
+func TestRunAllChecksPassing(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register(Check{Name: "database", Critical: true, Run: ok}, Check{Name: "ai_provider", Run: ok})
+
+	report := r.Run(context.Background())
+	assert.Equal(t, StatusOK, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "database", report.Checks[0].Name)
+	assert.Equal(t, "ai_provider", report.Checks[1].Name)
+	assert.Equal(t, StatusOK, report.Checks[0].Status)
+	assert.Empty(t, report.Checks[0].Error)
+}
+
+func TestRunOptionalFailureIsDegraded(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register(Check{Name: "database", Critical: true, Run: ok}, Check{Name: "ai_provider", Run: failing})
+
+	report := r.Run(context.Background())
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusFailing, report.Checks[1].Status)
+	assert.Equal(t, "connection refused", report.Checks[1].Error)
+}
+
+func TestRunCriticalFailureIsUnavailable(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register(Check{Name: "ai_provider", Run: failing}, Check{Name: "database", Critical: true, Run: failing})
+
+	assert.Equal(t, StatusUnavailable, r.Run(context.Background()).Status)
+}
+
+func TestRunTimesOutSlowChecks(t *testing.T) {
+	r := NewRegistry(20 * time.Millisecond)
+	r.Register(Check{Name: "database", Critical: true, Run: func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}})
+
+	started := time.Now()
+	report := r.Run(context.Background())
+	assert.Less(t, time.Since(started), 500*time.Millisecond)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewRegistry(time.Second)
+	r.Register(Check{Name: "database", Critical: true, Run: ok})
+	engine := gin.New()
+	engine.GET("/readyz", r.Readiness)
+	engine.GET("/livez", Liveness)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, "database", report.Checks[0].Name)
+
+	r.Drain()
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
@@ -0,0 +1,198 @@
+package httpfixture
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+const RecordEnv = "RECORD_FIXTURES"
+var ErrNoInteraction = errors.New("no recorded interaction matches request")
+type Mode int
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) == "1" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+type Transport struct {
+	path     string
+	mode     Mode
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+func New(path string, mode Mode) (*Transport, error) {
+	t := &Transport{path: path, mode: mode, next: http.DefaultTransport, cassette: Cassette{Interactions: []Interaction{}}}
+	if mode == ModeRecord {
+		return t, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixture %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &t.cassette); err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+	}
+	t.used = make([]bool, len(t.cassette.Interactions))
+	return t, nil
+}
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := Request{Method: req.Method, Path: req.URL.Path, Body: encodeBody(body)}
+	if t.mode == ModeRecord {
+		return t.record(req, recorded)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		t.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s %s", ErrNoInteraction, req.Method, req.URL.Path, body)
+}
+func (t *Transport) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	response := Response{Status: resp.StatusCode, Headers: map[string]string{}, Body: encodeBody(body)}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			response.Headers[name] = value
+		}
+	}
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{Request: recorded, Response: response})
+	t.mu.Unlock()
+	return response.toHTTP(req), nil
+}
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, append(data, '\n'), 0o644)
+}
+func (t *Transport) Unused() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	unused := 0
+	for _, used := range t.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := make(http.Header, len(r.Headers))
+	for name, value := range r.Headers {
+		header.Set(name, value)
+	}
+	body := decodeBody(r.Body)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+// encodeBody keeps JSON bodies as JSON so golden files stay readable; anything else is stored as a JSON string.
+func encodeBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, body); err == nil {
+			return compact.Bytes()
+		}
+	}
+	encoded, _ := json.Marshal(string(body))
+	return encoded
+}
+func decodeBody(body json.RawMessage) []byte {
+	var text string
+	if len(body) > 0 && body[0] == '"' && json.Unmarshal(body, &text) == nil {
+		return []byte(text)
+	}
+	return body
+}
+func matches(recorded, actual Request) bool {
+	if recorded.Method != actual.Method || recorded.Path != actual.Path {
+		return false
+	}
+	if len(recorded.Body) == 0 || len(actual.Body) == 0 {
+		return len(recorded.Body) == len(actual.Body)
+	}
+	var want, got interface{}
+	if json.Unmarshal(recorded.Body, &want) != nil || json.Unmarshal(actual.Body, &got) != nil {
+		return bytes.Equal(recorded.Body, actual.Body)
+	}
+	return reflect.DeepEqual(want, got)
+}
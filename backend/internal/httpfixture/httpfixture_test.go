@@ -0,0 +1,101 @@
+package httpfixture_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"weel-backend/internal/httpfixture"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, client *http.Client, method, url, body string) (*http.Response, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data), nil
+}
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/text" {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("plain body"))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req_123")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "nested", "cassette.json")
+	recorder, err := httpfixture.New(path, httpfixture.ModeRecord)
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder}
+	resp, body, err := do(t, client, http.MethodPost, server.URL+"/v1/echo", `{"b": 2, "a": 1}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.JSONEq(t, `{"echo":{"a":1,"b":2}}`, body)
+	_, body, err = do(t, client, http.MethodGet, server.URL+"/v1/text", "")
+	require.NoError(t, err)
+	assert.Equal(t, "plain body", body)
+	require.NoError(t, recorder.Save())
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "sk-secret")
+	assert.NotContains(t, string(saved), "req_123")
+	replayer, err := httpfixture.New(path, httpfixture.ModeReplay)
+	require.NoError(t, err)
+	client = &http.Client{Transport: replayer}
+	assert.Equal(t, 2, replayer.Unused())
+	resp, body, err = do(t, client, http.MethodPost, "http://replay.invalid/v1/echo", `{"a":1,"b":2}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"echo":{"a":1,"b":2}}`, body)
+	_, body, err = do(t, client, http.MethodGet, "http://replay.invalid/v1/text", "")
+	require.NoError(t, err)
+	assert.Equal(t, "plain body", body)
+	assert.Equal(t, 0, replayer.Unused())
+	_, _, err = do(t, client, http.MethodPost, "http://replay.invalid/v1/echo", `{"a":1,"b":2}`)
+	assert.ErrorIs(t, err, httpfixture.ErrNoInteraction)
+}
+func TestReplay_MismatchedBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"interactions": [
+		{"request": {"method": "POST", "path": "/v1/chat/completions", "body": {"model": "gpt-4o-mini"}},
+		 "response": {"status": 200, "body": {"ok": true}}}
+	]}`), 0o600))
+	replayer, err := httpfixture.New(path, httpfixture.ModeReplay)
+	require.NoError(t, err)
+	client := &http.Client{Transport: replayer}
+	_, _, err = do(t, client, http.MethodPost, "http://replay.invalid/v1/chat/completions", `{"model":"gpt-4o"}`)
+	assert.ErrorIs(t, err, httpfixture.ErrNoInteraction)
+	_, _, err = do(t, client, http.MethodGet, "http://replay.invalid/v1/chat/completions", `{"model":"gpt-4o-mini"}`)
+	assert.ErrorIs(t, err, httpfixture.ErrNoInteraction)
+	assert.Equal(t, 1, replayer.Unused())
+}
+func TestNew_MissingFixture(t *testing.T) {
+	_, err := httpfixture.New(filepath.Join(t.TempDir(), "missing.json"), httpfixture.ModeReplay)
+	assert.Error(t, err)
+}
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(httpfixture.RecordEnv, "")
+	assert.Equal(t, httpfixture.ModeReplay, httpfixture.ModeFromEnv())
+	t.Setenv(httpfixture.RecordEnv, "1")
+	assert.Equal(t, httpfixture.ModeRecord, httpfixture.ModeFromEnv())
+}
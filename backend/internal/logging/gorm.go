@@ -0,0 +1,75 @@
+package logging
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+type GormLogger struct {
+	logger        *slog.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{
+		logger:        logger,
+		level:         gormlogger.Info,
+		slowThreshold: slowThreshold,
+	}
+}
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+	}
+}
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	level := slog.LevelDebug
+	msg := "sql query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		level, msg = slog.LevelError, "sql query failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		level, msg = slog.LevelWarn, "slow sql query"
+	case l.level < gormlogger.Info:
+		return
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	sql, rows := fc()
+	attrs := []any{
+		"component", "gorm",
+		"sql", sql,
+		"rows", rows,
+		"elapsed_ms", float64(elapsed.Microseconds()) / 1000,
+	}
+	if err != nil && level == slog.LevelError {
+		attrs = append(attrs, "error", err.Error())
+	}
+	l.logger.Log(ctx, level, msg, attrs...)
+}
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"weel-backend/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLoggerAddsRequestIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.LogConfig{Level: "info", Format: "json"}, &buf)
+	ctx := WithRequestID(context.Background(), "req-123")
+
+	logger.InfoContext(ctx, "order created", "order_id", 7)
+	logger.Info("no request")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "req-123", lines[0]["request_id"])
+	assert.Equal(t, float64(7), lines[0]["order_id"])
+	assert.NotContains(t, lines[1], "request_id")
+}
+
+func TestLoggerRedactsSensitiveKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.LogConfig{Level: "info", Format: "json"}, &buf)
+
+	logger.Info("login", "email", "a@example.com", "password", "hunter2", "access_token", "abc",
+		slog.Group("headers", "Authorization", "Bearer xyz", "Accept", "application/json"))
+
+	out := buf.String()
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "Bearer xyz")
+	assert.NotContains(t, out, `"abc"`)
+	lines := decodeLines(t, &buf)
+	assert.Equal(t, "a@example.com", lines[0]["email"])
+	assert.Equal(t, redacted, lines[0]["password"])
+	assert.Equal(t, redacted, lines[0]["access_token"])
+	headers := lines[0]["headers"].(map[string]interface{})
+	assert.Equal(t, redacted, headers["Authorization"])
+	assert.Equal(t, "application/json", headers["Accept"])
+}
+
+func TestLoggerLevelAndFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.LogConfig{Level: "warn", Format: "text"}, &buf)
+
+	logger.Info("hidden")
+	logger.Warn("shown", "password", "secret-value")
+
+	out := buf.String()
+	assert.NotContains(t, out, "hidden")
+	assert.Contains(t, out, "msg=shown")
+	assert.Contains(t, out, "password="+redacted)
+	assert.Equal(t, slog.LevelDebug, ParseLevel("DEBUG"))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("verbose"))
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	for _, key := range []string{"password", "new_password", "refresh_token", "X-Api-Key", "apikey", "Set-Cookie", "jwt"} {
+		assert.True(t, IsSensitiveKey(key), key)
+	}
+	for _, key := range []string{"prompt_tokens", "email", "order_id", "api_version"} {
+		assert.False(t, IsSensitiveKey(key), key)
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	a, b := NewRequestID(), NewRequestID()
+	assert.Len(t, a, 32)
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, "", RequestID(context.Background()))
+}
+
+func TestGormLoggerTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.LogConfig{Level: "debug", Format: "json"}, &buf)
+	gl := NewGormLogger(logger, 100*time.Millisecond)
+	ctx := WithRequestID(context.Background(), "req-9")
+	query := func() (string, int64) { return `SELECT * FROM "users" WHERE email = $1`, 1 }
+
+	gl.Trace(ctx, time.Now(), query, nil)
+	gl.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	gl.Trace(ctx, time.Now(), query, errors.New("connection reset"))
+	gl.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	gl.LogMode(gormlogger.Silent).Trace(ctx, time.Now(), query, errors.New("ignored"))
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "DEBUG", lines[0]["level"])
+	assert.Equal(t, "req-9", lines[0]["request_id"])
+	assert.Equal(t, `SELECT * FROM "users" WHERE email = $1`, lines[0]["sql"])
+	assert.Equal(t, "WARN", lines[1]["level"])
+	assert.Equal(t, "slow sql query", lines[1]["msg"])
+	assert.Equal(t, "ERROR", lines[2]["level"])
+	assert.Equal(t, "connection reset", lines[2]["error"])
+	assert.Equal(t, "DEBUG", lines[3]["level"])
+
+	sql, params := gl.ParamsFilter(ctx, "SELECT $1", "secret")
+	assert.Equal(t, "SELECT $1", sql)
+	assert.Nil(t, params)
+}
@@ -0,0 +1,132 @@
+package metrics
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+	"weel-backend/internal/events"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+const namespace = "weel"
+var (
+	Registry          = prometheus.NewRegistry()
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	httpRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+	aiRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_requests_total",
+		Help:      "AI provider calls by operation, model and outcome.",
+	}, []string{"operation", "model", "outcome"})
+	aiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_request_duration_seconds",
+		Help:      "AI provider call latency by operation and model.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32},
+	}, []string{"operation", "model"})
+	aiTokensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_tokens_total",
+		Help:      "Tokens consumed by AI provider calls, by operation, model and kind (prompt or completion).",
+	}, []string{"operation", "model", "kind"})
+	ordersCreatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Orders created by delivery preference.",
+	}, []string{"delivery_preference"})
+	ordersCancelledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_cancelled_total",
+		Help:      "Orders cancelled by delivery preference and reason.",
+	}, []string{"delivery_preference", "reason"})
+	orderStatusChangesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_status_changes_total",
+		Help:      "Order status transitions by new status.",
+	}, []string{"status"})
+	rateLimitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected by the rate limiter, by policy.",
+	}, []string{"policy"})
+)
+const (
+	AIOutcomeSuccess    = "success"
+	AIOutcomeError      = "error"
+	AIOutcomeParseError = "parse_error"
+)
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		aiRequestsTotal,
+		aiRequestDuration,
+		aiTokensTotal,
+		ordersCreatedTotal,
+		ordersCancelledTotal,
+		orderStatusChangesTotal,
+		rateLimitedTotal,
+	)
+}
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+func InFlight(delta float64) {
+	httpRequestsInFlight.Add(delta)
+}
+func ObserveHTTPRequest(method, route, status string, elapsed time.Duration) {
+	httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(elapsed.Seconds())
+}
+func RateLimited(policy string) {
+	rateLimitedTotal.WithLabelValues(policy).Inc()
+}
+func ObserveAIRequest(operation, model, outcome string, elapsed time.Duration, promptTokens, completionTokens int) {
+	aiRequestsTotal.WithLabelValues(operation, model, outcome).Inc()
+	aiRequestDuration.WithLabelValues(operation, model).Observe(elapsed.Seconds())
+	if promptTokens > 0 {
+		aiTokensTotal.WithLabelValues(operation, model, "prompt").Add(float64(promptTokens))
+	}
+	if completionTokens > 0 {
+		aiTokensTotal.WithLabelValues(operation, model, "completion").Add(float64(completionTokens))
+	}
+}
+func RecordOrderEvent(ctx context.Context, event events.Event) {
+	if event.Order == nil {
+		return
+	}
+	preference := string(event.Order.DeliveryPreference)
+	switch event.Type {
+	case events.OrderCreated:
+		ordersCreatedTotal.WithLabelValues(preference).Inc()
+	case events.OrderCancelled:
+		reason := "unspecified"
+		if event.Order.CancellationReason != nil {
+			reason = string(*event.Order.CancellationReason)
+		}
+		ordersCancelledTotal.WithLabelValues(preference, reason).Inc()
+	case events.OrderStatusChanged:
+		orderStatusChangesTotal.WithLabelValues(string(event.Order.Status)).Inc()
+	}
+}
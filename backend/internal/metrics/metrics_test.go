@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weel-backend/internal/domain"
+	"weel-backend/internal/events"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordOrderEvent(t *testing.T) {
+	reason := domain.CancellationReasonTooSlow
+	order := &domain.Order{DeliveryPreference: domain.DeliveryPreferenceCurbside, Status: domain.OrderStatusProcessing}
+	created := testutil.ToFloat64(ordersCreatedTotal.WithLabelValues("CURBSIDE"))
+	processing := testutil.ToFloat64(orderStatusChangesTotal.WithLabelValues("processing"))
+	cancelled := testutil.ToFloat64(ordersCancelledTotal.WithLabelValues("CURBSIDE", "too_slow"))
+
+	RecordOrderEvent(context.Background(), events.NewOrderEvent(events.OrderCreated, order))
+	RecordOrderEvent(context.Background(), events.NewOrderEvent(events.OrderStatusChanged, order))
+	order.CancellationReason = &reason
+	RecordOrderEvent(context.Background(), events.NewOrderEvent(events.OrderCancelled, order))
+	RecordOrderEvent(context.Background(), events.Event{Type: events.OrderCreated})
+
+	assert.Equal(t, created+1, testutil.ToFloat64(ordersCreatedTotal.WithLabelValues("CURBSIDE")))
+	assert.Equal(t, processing+1, testutil.ToFloat64(orderStatusChangesTotal.WithLabelValues("processing")))
+	assert.Equal(t, cancelled+1, testutil.ToFloat64(ordersCancelledTotal.WithLabelValues("CURBSIDE", "too_slow")))
+}
+
+func TestObserveAIRequestCountsTokens(t *testing.T) {
+	ObserveAIRequest("suggest_products", "test-model", AIOutcomeSuccess, 300*time.Millisecond, 120, 80)
+	ObserveAIRequest("suggest_products", "test-model", AIOutcomeError, time.Second, 0, 0)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(aiRequestsTotal.WithLabelValues("suggest_products", "test-model", AIOutcomeSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(aiRequestsTotal.WithLabelValues("suggest_products", "test-model", AIOutcomeError)))
+	assert.Equal(t, float64(120), testutil.ToFloat64(aiTokensTotal.WithLabelValues("suggest_products", "test-model", "prompt")))
+	assert.Equal(t, float64(80), testutil.ToFloat64(aiTokensTotal.WithLabelValues("suggest_products", "test-model", "completion")))
+}
+
+func TestHandlerExposesRegisteredMetrics(t *testing.T) {
+	ObserveHTTPRequest("GET", "/api/v1/orders/:id", "200", 10*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `weel_http_requests_total{method="GET",route="/api/v1/orders/:id",status="200"}`)
+	assert.Contains(t, body, "weel_http_request_duration_seconds_bucket")
+	assert.Contains(t, body, "go_goroutines")
+}
@@ -1,7 +1,10 @@
 package middleware
 import (
+	"context"
 	"net/http"
+	"strconv"
 	"strings"
+	"weel-backend/internal/domain"
 	"weel-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -29,8 +32,62 @@ func AuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		role := claims.Role
+		if role == "" {
+			role = domain.UserRoleCustomer
+		}
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		c.Set("userRole", role)
+		c.Next()
+	}
+}
+func RequireRole(roles ...domain.UserRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("userRole")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+		for _, r := range roles {
+			if role.(domain.UserRole) == r {
+				c.Next()
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		c.Abort()
+	}
+}
+type StoreStaffLookup interface {
+	StaffStoreID(ctx context.Context, userID uint) (*uint, error)
+}
+func RequireStoreAccess(staff StoreStaffLookup, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("userRole")
+		if role == domain.UserRoleAdmin {
+			c.Next()
+			return
+		}
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+		storeID, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store ID"})
+			c.Abort()
+			return
+		}
+		assigned, err := staff.StaffStoreID(c.Request.Context(), userID.(uint))
+		if err != nil || assigned == nil || *assigned != uint(storeID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not assigned to this store"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
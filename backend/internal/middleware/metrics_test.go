@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/metrics", MetricsAuth("s3cret"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	cases := map[string]int{
+		"":               http.StatusUnauthorized,
+		"Bearer wrong":   http.StatusUnauthorized,
+		"s3cret":         http.StatusUnauthorized,
+		"Bearer s3cret":  http.StatusOK,
+		"Bearer s3cret ": http.StatusUnauthorized,
+	}
+	for header, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if header != "" {
+			req.Header.Set(AuthorizationHeader, header)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, "Authorization: %q", header)
+	}
+}
+
+func TestMetricsAuthWithoutToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/metrics", MetricsAuth(""), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set(AuthorizationHeader, "Bearer ")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,57 @@
+package middleware
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"weel-backend/internal/metrics"
+	"weel-backend/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+)
+func RateLimit(limiter *ratelimit.Limiter, name string) gin.HandlerFunc {
+	policy, ok := limiter.Policy(name)
+	if !ok {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		res, err := limiter.Take(ctx, policy, rateLimitKey(c))
+		if err != nil {
+			slog.ErrorContext(ctx, "rate limiter unavailable, allowing request", "policy", policy.Name, "error", err)
+			c.Next()
+			return
+		}
+		c.Header(RateLimitLimitHeader, strconv.Itoa(res.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+		c.Header(RateLimitResetHeader, strconv.Itoa(ceilSeconds(res.Reset)))
+		c.Header(RateLimitPolicyHeader, policy.HeaderValue())
+		if !res.Allowed {
+			metrics.RateLimited(policy.Name)
+			c.Header(RetryAfterHeader, strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded, try again later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+func rateLimitKey(c *gin.Context) string {
+	if userID, exists := c.Get("userID"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
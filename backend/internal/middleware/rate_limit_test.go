@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"weel-backend/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRateLimitedEngine(limiter *ratelimit.Limiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/public", RateLimit(limiter, ratelimit.PolicyLogin), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	engine.GET("/private", func(c *gin.Context) {
+		c.Set("userID", uint(7))
+	}, RateLimit(limiter, ratelimit.PolicyLogin), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return engine
+}
+
+func TestRateLimitHeadersAndRejection(t *testing.T) {
+	policy := ratelimit.Policy{Name: ratelimit.PolicyLogin, Limit: 2, Period: time.Minute, Burst: 2}
+	engine := newRateLimitedEngine(ratelimit.NewLimiter(ratelimit.NewMemoryStore(time.Minute), policy))
+
+	do := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/public", "10.0.0.1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+	assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+	assert.Equal(t, "30", w.Header().Get(RateLimitResetHeader))
+	assert.Equal(t, "2;w=60", w.Header().Get(RateLimitPolicyHeader))
+
+	do("/public", "10.0.0.1")
+	w = do("/public", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+	assert.Equal(t, "30", w.Header().Get(RetryAfterHeader))
+	assert.JSONEq(t, `{"error":"rate limit exceeded, try again later"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, do("/public", "10.0.0.2").Code, "other IPs have their own bucket")
+	assert.Equal(t, http.StatusNoContent, do("/private", "10.0.0.1").Code, "authenticated users are keyed by user ID")
+}
+
+func TestRateLimitWithoutLimiterOrPolicy(t *testing.T) {
+	engine := newRateLimitedEngine(nil)
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public", nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	}
+}
@@ -0,0 +1,343 @@
+package migrator
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+const (
+	DefaultLockKey int64 = 7_771_203_517
+	createTableSQL       = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		checksum char(64) NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`
+)
+var (
+	ErrInvalidFilename  = errors.New("migration files must be named NNNN_name.up.sql or NNNN_name.down.sql")
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrMissingUp        = errors.New("migration has no up file")
+	ErrMissingDown      = errors.New("migration has no down file")
+	ErrChecksumMismatch = errors.New("applied migration has been modified")
+	ErrUnknownMigration = errors.New("applied migration is missing from the migrations directory")
+	ErrInvalidSteps     = errors.New("steps must be a positive number")
+	ErrInvalidName      = errors.New("migration name must contain letters or digits")
+	filenamePattern     = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	nameSanitizer       = regexp.MustCompile(`[^a-z0-9]+`)
+)
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Modified  bool       `json:"modified,omitempty"`
+	Missing   bool       `json:"missing,omitempty"`
+}
+func (s Status) State() string {
+	switch {
+	case s.Missing:
+		return "missing"
+	case s.Modified:
+		return "modified"
+	case s.AppliedAt != nil:
+		return "applied"
+	default:
+		return "pending"
+	}
+}
+type appliedMigration struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+	lockKey    int64
+	logf       func(format string, args ...interface{})
+}
+type Option func(*Migrator)
+func WithLockKey(key int64) Option {
+	return func(m *Migrator) {
+		m.lockKey = key
+	}
+}
+func WithLogger(logf func(format string, args ...interface{})) Option {
+	return func(m *Migrator) {
+		m.logf = logf
+	}
+}
+func New(db *sql.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	m := &Migrator{
+		db:         db,
+		migrations: migrations,
+		lockKey:    DefaultLockKey,
+		logf:       func(string, ...interface{}) {},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := filenamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFilename, entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFilename, entry.Name())
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d (%s and %s)", ErrDuplicateVersion, version, migration.Name, match[2])
+		}
+		target := &migration.Up
+		if match[3] == "down" {
+			target = &migration.Down
+		}
+		if *target != "" {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateVersion, entry.Name())
+		}
+		*target = string(body)
+	}
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingUp, migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			started := time.Now()
+			err := m.inTx(ctx, conn, migration.Up,
+				`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, migration.Checksum)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			m.logf("applied %d_%s in %s", migration.Version, migration.Name, time.Since(started).Round(time.Millisecond))
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, ErrInvalidSteps
+	}
+	byVersion := make(map[int64]*Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		for _, version := range versions {
+			if reverted == steps {
+				break
+			}
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("%w: %d_%s", ErrUnknownMigration, version, done[version].name)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrMissingDown, migration.Version, migration.Name)
+			}
+			err := m.inTx(ctx, conn, migration.Down, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			if err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			m.logf("reverted %d_%s", migration.Version, migration.Name)
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	done, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := done[migration.Version]; ok {
+			appliedAt := record.appliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = record.checksum != migration.Checksum
+			delete(done, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range done {
+		appliedAt := record.appliedAt
+		statuses = append(statuses, Status{Version: record.version, Name: record.name, AppliedAt: &appliedAt, Missing: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+func (m *Migrator) verify(done map[int64]appliedMigration) error {
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		if record, ok := done[migration.Version]; ok && record.checksum != migration.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	for version, record := range done {
+		if !known[version] {
+			return fmt.Errorf("%w: %d_%s", ErrUnknownMigration, version, record.name)
+		}
+	}
+	return nil
+}
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	if _, err := conn.ExecContext(ctx, createTableSQL); err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	done := map[int64]appliedMigration{}
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		done[record.version] = record
+	}
+	return done, rows.Err()
+}
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, script, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, m.lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, m.lockKey)
+	return fn(conn)
+}
+func Create(dir, name string) (string, string, error) {
+	slug := strings.Trim(nameSanitizer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", "", ErrInvalidName
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+	existing, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	next := int64(1)
+	if len(existing) > 0 {
+		next = existing[len(existing)-1].Version + 1
+	}
+	base := fmt.Sprintf("%04d_%s", next, slug)
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+	if err := os.WriteFile(up, []byte("-- "+base+" up\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- "+base+" down\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
@@ -0,0 +1,123 @@
+package migrator_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"weel-backend/internal/migrator"
+	"weel-backend/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestLoad_SortsAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_notes.up.sql":   {Data: []byte("ALTER TABLE orders ADD COLUMN notes text;")},
+		"0002_add_notes.down.sql": {Data: []byte("ALTER TABLE orders DROP COLUMN notes;")},
+		"0001_init.up.sql":        {Data: []byte("CREATE TABLE t (id int);")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+	list, err := migrator.Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(1), list[0].Version)
+	assert.Equal(t, "init", list[0].Name)
+	assert.Empty(t, list[0].Down)
+	assert.Equal(t, "add_notes", list[1].Name)
+	assert.Equal(t, "ALTER TABLE orders DROP COLUMN notes;", list[1].Down)
+	assert.Len(t, list[1].Checksum, 64)
+	assert.NotEqual(t, list[0].Checksum, list[1].Checksum)
+}
+func TestLoad_Errors(t *testing.T) {
+	cases := map[string]struct {
+		fsys fstest.MapFS
+		want error
+	}{
+		"bad name":          {fstest.MapFS{"init.sql": {Data: []byte("x")}}, migrator.ErrInvalidFilename},
+		"uppercase":         {fstest.MapFS{"0001_Init.up.sql": {Data: []byte("x")}}, migrator.ErrInvalidFilename},
+		"duplicate version": {fstest.MapFS{"0001_a.up.sql": {Data: []byte("x")}, "0001_b.up.sql": {Data: []byte("y")}}, migrator.ErrDuplicateVersion},
+		"down only":         {fstest.MapFS{"0001_a.down.sql": {Data: []byte("x")}}, migrator.ErrMissingUp},
+	}
+	for name, tc := range cases {
+		_, err := migrator.Load(tc.fsys)
+		assert.True(t, errors.Is(err, tc.want), "%s: %v", name, err)
+	}
+}
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	list, err := migrator.Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	for i, m := range list {
+		assert.Equal(t, int64(i+1), m.Version, "migrations must be numbered without gaps")
+		assert.NotEmpty(t, m.Down, "%04d_%s needs a down migration", m.Version, m.Name)
+	}
+}
+func TestCreate_NextVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), []byte("SELECT 1;"), 0o644))
+	up, down, err := migrator.Create(dir, "Add Order Notes!")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0008_add_order_notes.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0008_add_order_notes.down.sql"), down)
+	list, err := migrator.Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	_, _, err = migrator.Create(dir, "!!!")
+	assert.Equal(t, migrator.ErrInvalidName, err)
+}
+func TestStatus_State(t *testing.T) {
+	assert.Equal(t, "pending", migrator.Status{}.State())
+	assert.Equal(t, "missing", migrator.Status{Missing: true}.State())
+}
+func testDB(t *testing.T) *sql.DB {
+	dsn := "host=localhost port=5432 user=postgres password=postgres dbname=weel_test sslmode=disable"
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Skip("Skipping test: database not available")
+	}
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return sqlDB
+}
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"0001_widgets.up.sql":       {Data: []byte("CREATE TABLE migrator_test_widgets (id serial PRIMARY KEY);")},
+		"0001_widgets.down.sql":     {Data: []byte("DROP TABLE migrator_test_widgets;")},
+		"0002_widget_name.up.sql":   {Data: []byte("ALTER TABLE migrator_test_widgets ADD COLUMN name text; UPDATE migrator_test_widgets SET name = 'x';")},
+		"0002_widget_name.down.sql": {Data: []byte("ALTER TABLE migrator_test_widgets DROP COLUMN name;")},
+	}
+	_, err := db.Exec("DROP TABLE IF EXISTS migrator_test_widgets; DROP TABLE IF EXISTS schema_migrations")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec("DROP TABLE IF EXISTS migrator_test_widgets; DROP TABLE IF EXISTS schema_migrations") })
+	m, err := migrator.New(db, fsys)
+	require.NoError(t, err)
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	applied, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied)
+	reverted, err := m.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "applied", statuses[0].State())
+	assert.Equal(t, "pending", statuses[1].State())
+	fsys["0001_widgets.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE migrator_test_widgets (id bigserial PRIMARY KEY);")}
+	changed, err := migrator.New(db, fsys)
+	require.NoError(t, err)
+	_, err = changed.Up(ctx)
+	assert.True(t, errors.Is(err, migrator.ErrChecksumMismatch))
+}
@@ -0,0 +1,32 @@
+package address
+import (
+	"weel-backend/internal/handler"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/module"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/router"
+	"weel-backend/internal/service"
+	"gorm.io/gorm"
+)
+type AddressModule struct {
+	addressRepo    repository.AddressRepository
+	addressService service.AddressService
+	addressHandler *handler.AddressHandler
+	jwtService     *service.JWTService
+}
+func NewAddressModule() module.Module {
+	return &AddressModule{}
+}
+func (m *AddressModule) Name() string {
+	return "address"
+}
+func (m *AddressModule) Initialize(db *gorm.DB) error {
+	m.addressRepo = repository.NewAddressRepository(db)
+	m.addressService = service.NewAddressService(m.addressRepo)
+	m.addressHandler = handler.NewAddressHandler(m.addressService)
+	m.jwtService = service.NewJWTService()
+	return nil
+}
+func (m *AddressModule) RegisterRoutes(r *router.Router) {
+	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.addressHandler)
+}
@@ -4,6 +4,7 @@ import (
 	"weel-backend/internal/handler"
 	"weel-backend/internal/middleware"
 	"weel-backend/internal/module"
+	"weel-backend/internal/ratelimit"
 	"weel-backend/internal/repository"
 	"weel-backend/internal/router"
 	"weel-backend/internal/service"
@@ -33,8 +34,8 @@ func (m *AuthModule) Initialize(db *gorm.DB) error {
 }
 func (m *AuthModule) RegisterRoutes(r *router.Router) {
 	v1 := r.GetEngine().Group("/api/v1")
-	v1.POST("/auth/login", m.authHandler.Login)
+	v1.POST("/auth/login", r.RateLimit(ratelimit.PolicyLogin), m.authHandler.Login)
 	protected := v1.Group("")
-	protected.Use(middleware.AuthMiddleware(m.jwtService))
+	protected.Use(middleware.AuthMiddleware(m.jwtService), r.RateLimit(ratelimit.PolicyAPI))
 	protected.GET("/me", m.authHandler.GetMe)
 }
@@ -0,0 +1,33 @@
+package delivery
+import (
+	"weel-backend/internal/handler"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/module"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/router"
+	"weel-backend/internal/service"
+	"gorm.io/gorm"
+)
+type DeliveryModule struct {
+	zoneRepo        repository.DeliveryZoneRepository
+	zoneService     service.DeliveryZoneService
+	deliveryHandler *handler.DeliveryHandler
+	jwtService      *service.JWTService
+}
+func NewDeliveryModule() module.Module {
+	return &DeliveryModule{}
+}
+func (m *DeliveryModule) Name() string {
+	return "delivery"
+}
+func (m *DeliveryModule) Initialize(db *gorm.DB) error {
+	m.zoneRepo = repository.NewDeliveryZoneRepository(db)
+	m.zoneService = service.NewDeliveryZoneService(m.zoneRepo)
+	m.deliveryHandler = handler.NewDeliveryHandler(m.zoneService)
+	m.jwtService = service.NewJWTService()
+	return nil
+}
+func (m *DeliveryModule) RegisterRoutes(r *router.Router) {
+	r.PublicRoutes().GET("/delivery/quote", m.deliveryHandler.GetQuote)
+	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.deliveryHandler)
+}
@@ -25,6 +25,5 @@ func (m *FeatureFlagModule) Initialize(db *gorm.DB) error {
 	return nil
 }
 func (m *FeatureFlagModule) RegisterRoutes(r *router.Router) {
-	v1 := r.GetEngine().Group("/api/v1")
-	m.flagHandler.RegisterRoutes(v1)
+	m.flagHandler.RegisterRoutes(r.PublicRoutes())
 }
@@ -1,5 +1,7 @@
 package module
 import (
+	"context"
+	"weel-backend/internal/health"
 	"gorm.io/gorm"
 	"weel-backend/internal/router"
 )
@@ -8,3 +10,9 @@ type Module interface {
 	RegisterRoutes(router *router.Router)
 	Name() string
 }
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+type HealthChecker interface {
+	HealthChecks() []health.Check
+}
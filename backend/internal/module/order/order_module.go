@@ -2,9 +2,13 @@ package order
 
 import (
 	"weel-backend/config"
+	"weel-backend/internal/events"
+	"weel-backend/internal/guardrail"
 	"weel-backend/internal/handler"
+	"weel-backend/internal/health"
 	"weel-backend/internal/middleware"
 	"weel-backend/internal/module"
+	"weel-backend/internal/ratelimit"
 	"weel-backend/internal/repository"
 	"weel-backend/internal/router"
 	"weel-backend/internal/service"
@@ -13,17 +17,23 @@ import (
 )
 
 type OrderModule struct {
-	orderRepo    repository.OrderRepository
-	orderService service.OrderService
-	orderHandler *handler.OrderHandler
-	jwtService   *service.JWTService
-	aiService    service.AIService
-	cfg          *config.Config
+	orderRepo         repository.OrderRepository
+	orderService      service.OrderService
+	orderHandler      *handler.OrderHandler
+	jwtService        *service.JWTService
+	aiService         service.AIService
+	quotaHandler      *handler.AIQuotaHandler
+	usageHandler      *handler.AIUsageHandler
+	suggestionHandler *handler.AISuggestionHandler
+	promptHandler     *handler.PromptHandler
+	cfg               *config.Config
+	bus               *events.Bus
 }
 
-func NewOrderModule(cfg *config.Config) module.Module {
+func NewOrderModule(cfg *config.Config, bus *events.Bus) module.Module {
 	return &OrderModule{
 		cfg: cfg,
+		bus: bus,
 	}
 }
 func (m *OrderModule) Name() string {
@@ -31,14 +41,63 @@ func (m *OrderModule) Name() string {
 }
 func (m *OrderModule) Initialize(db *gorm.DB) error {
 	m.orderRepo = repository.NewOrderRepository(db)
-	m.aiService = service.NewAIService(m.cfg)
-	m.orderService = service.NewOrderService(m.orderRepo, m.aiService)
+	prices, err := service.ParseAIPriceTable(m.cfg.OpenAI.PriceTable)
+	if err != nil {
+		return err
+	}
+	usageService := service.NewAIUsageService(repository.NewAIUsageRepository(db), prices)
+	promptService := service.NewPromptService(repository.NewPromptTemplateRepository(db))
+	m.aiService = service.NewAIService(m.cfg, service.WithUsageRecorder(usageService), service.WithPromptSource(promptService))
+	suggestionRepo := repository.NewAISuggestionRepository(db)
+	quotaService := service.NewAIQuotaService(repository.NewAIQuotaRepository(db), repository.NewUserRepository(db), m.cfg.OpenAI.SuggestionsDailyLimit)
+	directory := service.NewStoreService(
+		repository.NewStoreRepository(db),
+		repository.NewUserRepository(db),
+		m.orderRepo,
+		repository.NewInventoryRepository(db),
+	)
+	opts := []service.OrderServiceOption{
+		service.WithCancellationCutoff(m.cfg.Orders.CancellationCutoff),
+		service.WithSuggestionQuota(quotaService),
+		service.WithSuggestionSessions(suggestionRepo),
+		service.WithAddressBook(repository.NewAddressRepository(db)),
+		service.WithDeliveryQuoter(service.NewDeliveryZoneService(repository.NewDeliveryZoneRepository(db))),
+		service.WithStoreDirectory(directory),
+		service.WithPickupScheduler(service.NewPickupService(
+			repository.NewPickupRepository(db),
+			repository.NewStoreRepository(db),
+			m.orderRepo,
+		)),
+	}
+	checker, err := guardrail.New(m.cfg.Guardrail)
+	if err != nil {
+		return err
+	}
+	if checker != nil {
+		opts = append(opts, service.WithGuardrail(checker))
+	}
+	if m.bus != nil {
+		opts = append(opts, service.WithEventPublisher(m.bus))
+	}
+	m.orderService = service.NewOrderService(m.orderRepo, m.aiService, opts...)
 	m.orderHandler = handler.NewOrderHandler(m.orderService)
+	m.quotaHandler = handler.NewAIQuotaHandler(quotaService)
+	m.usageHandler = handler.NewAIUsageHandler(usageService)
+	m.suggestionHandler = handler.NewAISuggestionHandler(service.NewAISuggestionService(suggestionRepo, m.orderRepo, directory))
+	m.promptHandler = handler.NewPromptHandler(promptService)
 	m.jwtService = service.NewJWTService()
 	return nil
 }
 func (m *OrderModule) RegisterRoutes(r *router.Router) {
+	auth := middleware.AuthMiddleware(m.jwtService)
 	v1 := r.GetEngine().Group("/api/v1")
-	v1.POST("/orders/suggestions", m.orderHandler.GetAISuggestions)
-	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.orderHandler)
+	v1.POST("/orders/suggestions", auth, r.RateLimit(ratelimit.PolicySuggestions), m.orderHandler.GetAISuggestions)
+	r.RegisterProtectedRoutes(auth, m.orderHandler, m.quotaHandler, m.usageHandler, m.suggestionHandler, m.promptHandler)
+}
+
+func (m *OrderModule) HealthChecks() []health.Check {
+	if !m.cfg.Health.CheckAI || m.cfg.OpenAI.Secret == "" {
+		return nil
+	}
+	return []health.Check{{Name: "ai_provider", Run: m.aiService.Ping}}
 }
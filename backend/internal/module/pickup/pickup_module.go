@@ -0,0 +1,34 @@
+package pickup
+import (
+	"weel-backend/internal/handler"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/module"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/router"
+	"weel-backend/internal/service"
+	"gorm.io/gorm"
+)
+type PickupModule struct {
+	pickupService service.PickupService
+	pickupHandler *handler.PickupHandler
+	jwtService    *service.JWTService
+}
+func NewPickupModule() module.Module {
+	return &PickupModule{}
+}
+func (m *PickupModule) Name() string {
+	return "pickup"
+}
+func (m *PickupModule) Initialize(db *gorm.DB) error {
+	storeRepo := repository.NewStoreRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	m.pickupService = service.NewPickupService(repository.NewPickupRepository(db), storeRepo, orderRepo)
+	staff := service.NewStoreService(storeRepo, repository.NewUserRepository(db), orderRepo, repository.NewInventoryRepository(db))
+	m.pickupHandler = handler.NewPickupHandler(m.pickupService, staff)
+	m.jwtService = service.NewJWTService()
+	return nil
+}
+func (m *PickupModule) RegisterRoutes(r *router.Router) {
+	m.pickupHandler.RegisterPublicRoutes(r.PublicRoutes())
+	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.pickupHandler)
+}
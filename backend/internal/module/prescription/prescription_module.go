@@ -0,0 +1,38 @@
+package prescription
+import (
+	"weel-backend/internal/handler"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/module"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/router"
+	"weel-backend/internal/service"
+	"weel-backend/internal/storage"
+	"gorm.io/gorm"
+)
+type PrescriptionModule struct {
+	prescriptionRepo    repository.PrescriptionRepository
+	prescriptionService service.PrescriptionService
+	prescriptionHandler *handler.PrescriptionHandler
+	jwtService          *service.JWTService
+	store               storage.BlobStore
+}
+func NewPrescriptionModule(store storage.BlobStore) module.Module {
+	return &PrescriptionModule{
+		store: store,
+	}
+}
+func (m *PrescriptionModule) Name() string {
+	return "prescription"
+}
+func (m *PrescriptionModule) Initialize(db *gorm.DB) error {
+	m.prescriptionRepo = repository.NewPrescriptionRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	directory := service.NewStoreService(repository.NewStoreRepository(db), repository.NewUserRepository(db), orderRepo, repository.NewInventoryRepository(db))
+	m.prescriptionService = service.NewPrescriptionService(m.prescriptionRepo, orderRepo, directory, m.store)
+	m.prescriptionHandler = handler.NewPrescriptionHandler(m.prescriptionService)
+	m.jwtService = service.NewJWTService()
+	return nil
+}
+func (m *PrescriptionModule) RegisterRoutes(r *router.Router) {
+	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.prescriptionHandler)
+}
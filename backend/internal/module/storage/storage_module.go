@@ -0,0 +1,32 @@
+package storage
+import (
+	"weel-backend/internal/handler"
+	"weel-backend/internal/module"
+	"weel-backend/internal/router"
+	"weel-backend/internal/storage"
+	"gorm.io/gorm"
+)
+type StorageModule struct {
+	store       storage.BlobStore
+	fileHandler *handler.FileHandler
+}
+func NewStorageModule(store storage.BlobStore) module.Module {
+	return &StorageModule{
+		store: store,
+	}
+}
+func (m *StorageModule) Name() string {
+	return "storage"
+}
+func (m *StorageModule) Initialize(db *gorm.DB) error {
+	if local, ok := m.store.(*storage.LocalStore); ok {
+		m.fileHandler = handler.NewFileHandler(local)
+	}
+	return nil
+}
+func (m *StorageModule) RegisterRoutes(r *router.Router) {
+	if m.fileHandler == nil {
+		return
+	}
+	m.fileHandler.RegisterRoutes(r.PublicRoutes())
+}
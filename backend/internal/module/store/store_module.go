@@ -0,0 +1,36 @@
+package store
+import (
+	"weel-backend/internal/handler"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/module"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/router"
+	"weel-backend/internal/service"
+	"gorm.io/gorm"
+)
+type StoreModule struct {
+	storeService service.StoreService
+	storeHandler *handler.StoreHandler
+	jwtService   *service.JWTService
+}
+func NewStoreModule() module.Module {
+	return &StoreModule{}
+}
+func (m *StoreModule) Name() string {
+	return "store"
+}
+func (m *StoreModule) Initialize(db *gorm.DB) error {
+	m.storeService = service.NewStoreService(
+		repository.NewStoreRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewOrderRepository(db),
+		repository.NewInventoryRepository(db),
+	)
+	m.storeHandler = handler.NewStoreHandler(m.storeService)
+	m.jwtService = service.NewJWTService()
+	return nil
+}
+func (m *StoreModule) RegisterRoutes(r *router.Router) {
+	m.storeHandler.RegisterPublicRoutes(r.PublicRoutes())
+	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.storeHandler)
+}
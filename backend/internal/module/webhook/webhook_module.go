@@ -0,0 +1,60 @@
+package webhook
+import (
+	"context"
+	"weel-backend/config"
+	"weel-backend/internal/events"
+	"weel-backend/internal/handler"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/module"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/router"
+	"weel-backend/internal/service"
+	"gorm.io/gorm"
+)
+type WebhookModule struct {
+	webhookRepo    repository.WebhookRepository
+	webhookService service.WebhookService
+	webhookHandler *handler.WebhookHandler
+	jwtService     *service.JWTService
+	cfg            config.WebhookConfig
+	bus            *events.Bus
+}
+func NewWebhookModule(cfg config.WebhookConfig, bus *events.Bus) module.Module {
+	return &WebhookModule{
+		cfg: cfg,
+		bus: bus,
+	}
+}
+func (m *WebhookModule) Name() string {
+	return "webhook"
+}
+func (m *WebhookModule) Initialize(db *gorm.DB) error {
+	m.webhookRepo = repository.NewWebhookRepository(db)
+	m.webhookService = service.NewWebhookService(m.webhookRepo, m.cfg, nil)
+	m.webhookHandler = handler.NewWebhookHandler(m.webhookService)
+	m.jwtService = service.NewJWTService()
+	if m.bus != nil {
+		m.bus.Subscribe(m.webhookService.HandleEvent)
+	}
+	m.webhookService.Start()
+	return nil
+}
+func (m *WebhookModule) RegisterRoutes(r *router.Router) {
+	r.RegisterProtectedRoutes(middleware.AuthMiddleware(m.jwtService), m.webhookHandler)
+}
+func (m *WebhookModule) Shutdown(ctx context.Context) error {
+	if m.webhookService == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		m.webhookService.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,54 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysetOrder(t *testing.T) {
+	single := Keyset{Keys: []SortKey{{Column: "created_at", Desc: true}}, IDColumn: "id"}
+	assert.Equal(t, "created_at DESC, id DESC", single.order(false))
+	assert.Equal(t, "created_at ASC, id ASC", single.order(true))
+	mixed := Keyset{Keys: []SortKey{{Column: "status"}, {Column: "created_at", Desc: true}}, IDColumn: "id"}
+	assert.Equal(t, "status ASC, created_at DESC, id DESC", mixed.order(false))
+	assert.Equal(t, "status DESC, created_at ASC, id ASC", mixed.order(true))
+	ascLast := Keyset{Keys: []SortKey{{Column: "created_at", Desc: true}, {Column: "last_name"}}, IDColumn: "users.id"}
+	assert.Equal(t, "created_at DESC, last_name ASC, users.id ASC", ascLast.order(false))
+	assert.Equal(t, "created_at ASC, last_name DESC, users.id DESC", ascLast.order(true))
+	idOnly := Keyset{IDColumn: "id"}
+	assert.Equal(t, "id ASC", idOnly.order(false))
+	assert.Equal(t, "id DESC", idOnly.order(true))
+}
+func TestKeysetIDDesc(t *testing.T) {
+	assert.False(t, Keyset{IDColumn: "id"}.idDesc())
+	assert.True(t, Keyset{Keys: []SortKey{{Column: "created_at", Desc: true}}}.idDesc())
+	assert.False(t, Keyset{Keys: []SortKey{{Column: "created_at", Desc: true}, {Column: "status"}}}.idDesc())
+	assert.True(t, Keyset{Keys: []SortKey{{Column: "status"}, {Column: "created_at", Desc: true}}}.idDesc())
+}
+func TestKeysetAfter_BreaksTiesOnID(t *testing.T) {
+	keyset := Keyset{Keys: []SortKey{{Column: "created_at", Desc: true}}, IDColumn: "id"}
+	clause, args := keyset.after(&Cursor{Values: []interface{}{"2024-01-02"}, ID: 7})
+	assert.Equal(t, "((created_at < ?) OR (created_at = ? AND id < ?))", clause)
+	assert.Equal(t, []interface{}{"2024-01-02", "2024-01-02", uint(7)}, args)
+	clause, args = keyset.after(&Cursor{Values: []interface{}{"2024-01-02"}, ID: 7, Backward: true})
+	assert.Equal(t, "((created_at > ?) OR (created_at = ? AND id > ?))", clause)
+	assert.Equal(t, []interface{}{"2024-01-02", "2024-01-02", uint(7)}, args)
+}
+func TestKeysetAfter_MixedDirections(t *testing.T) {
+	keyset := Keyset{Keys: []SortKey{{Column: "status"}, {Column: "created_at", Desc: true}}, IDColumn: "id"}
+	clause, args := keyset.after(&Cursor{Values: []interface{}{"pending", "2024-01-02"}, ID: 9})
+	assert.Equal(t, "((status > ?) OR (status = ? AND created_at < ?) OR (status = ? AND created_at = ? AND id < ?))", clause)
+	assert.Equal(t, []interface{}{"pending", "pending", "2024-01-02", "pending", "2024-01-02", uint(9)}, args)
+	clause, _ = keyset.after(&Cursor{Values: []interface{}{"pending", "2024-01-02"}, ID: 9, Backward: true})
+	assert.Equal(t, "((status < ?) OR (status = ? AND created_at > ?) OR (status = ? AND created_at = ? AND id > ?))", clause)
+}
+func TestKeysetAfter_IDFollowsLastKey(t *testing.T) {
+	keyset := Keyset{Keys: []SortKey{{Column: "created_at", Desc: true}, {Column: "last_name"}}, IDColumn: "id"}
+	clause, _ := keyset.after(&Cursor{Values: []interface{}{"2024-01-02", "Smith"}, ID: 3})
+	assert.Equal(t, "((created_at < ?) OR (created_at = ? AND last_name > ?) OR (created_at = ? AND last_name = ? AND id > ?))", clause)
+	idOnly := Keyset{IDColumn: "id"}
+	clause, args := idOnly.after(&Cursor{ID: 3})
+	assert.Equal(t, "((id > ?))", clause)
+	assert.Equal(t, []interface{}{uint(3)}, args)
+}
@@ -0,0 +1,227 @@
+package pagination
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+type Params struct {
+	Cursor       string `form:"cursor"`
+	Limit        int    `form:"limit"`
+	IncludeTotal bool   `form:"include_total"`
+}
+func (p Params) Normalized() Params {
+	if p.Limit <= 0 {
+		p.Limit = DefaultLimit
+	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+	return p
+}
+type Page struct {
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	HasMore    bool    `json:"has_more"`
+	Limit      int     `json:"limit"`
+	Total      *int64  `json:"total,omitempty"`
+}
+type Cursor struct {
+	Sort     string        `json:"s"`
+	Values   []interface{} `json:"v,omitempty"`
+	ID       uint          `json:"id"`
+	Backward bool          `json:"b,omitempty"`
+}
+func Encode(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+func Decode(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+type ValueKind int
+const (
+	KindString ValueKind = iota
+	KindTime
+	KindNumber
+)
+type SortKey struct {
+	Column string
+	Desc   bool
+	Kind   ValueKind
+}
+type Keyset struct {
+	Keys     []SortKey
+	IDColumn string
+}
+func (k Keyset) Signature() string {
+	parts := make([]string, 0, len(k.Keys))
+	for _, key := range k.Keys {
+		if key.Desc {
+			parts = append(parts, "-"+key.Column)
+		} else {
+			parts = append(parts, key.Column)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+func (k Keyset) values(raw []interface{}) ([]interface{}, error) {
+	if len(raw) != len(k.Keys) {
+		return nil, ErrInvalidCursor
+	}
+	values := make([]interface{}, len(raw))
+	for i, key := range k.Keys {
+		switch key.Kind {
+		case KindTime:
+			s, ok := raw[i].(string)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = t
+		case KindNumber:
+			n, ok := raw[i].(float64)
+			if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = n
+		default:
+			s, ok := raw[i].(string)
+			if !ok {
+				return nil, ErrInvalidCursor
+			}
+			values[i] = s
+		}
+	}
+	return values, nil
+}
+func (k Keyset) idDesc() bool {
+	if len(k.Keys) == 0 {
+		return false
+	}
+	return k.Keys[len(k.Keys)-1].Desc
+}
+func (k Keyset) order(backward bool) string {
+	parts := make([]string, 0, len(k.Keys)+1)
+	for _, key := range k.Keys {
+		parts = append(parts, key.Column+" "+direction(key.Desc != backward))
+	}
+	parts = append(parts, k.IDColumn+" "+direction(k.idDesc() != backward))
+	return strings.Join(parts, ", ")
+}
+func (k Keyset) after(c *Cursor) (string, []interface{}) {
+	columns := make([]SortKey, 0, len(k.Keys)+1)
+	columns = append(columns, k.Keys...)
+	columns = append(columns, SortKey{Column: k.IDColumn, Desc: k.idDesc()})
+	values := make([]interface{}, 0, len(c.Values)+1)
+	values = append(values, c.Values...)
+	values = append(values, c.ID)
+	var clauses []string
+	var args []interface{}
+	for i, col := range columns {
+		var conds []string
+		for j := 0; j < i; j++ {
+			conds = append(conds, columns[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if col.Desc != c.Backward {
+			op = "<"
+		}
+		conds = append(conds, fmt.Sprintf("%s %s ?", col.Column, op))
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(conds, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+func Fetch[T any](query *gorm.DB, keyset Keyset, params Params, key func(*T) ([]interface{}, uint)) ([]*T, *Page, error) {
+	params = params.Normalized()
+	var cursor *Cursor
+	if params.Cursor != "" {
+		c, err := Decode(params.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.Sort != keyset.Signature() {
+			return nil, nil, ErrInvalidCursor
+		}
+		if c.Values, err = keyset.values(c.Values); err != nil {
+			return nil, nil, err
+		}
+		cursor = c
+	}
+	page := &Page{Limit: params.Limit}
+	if params.IncludeTotal {
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
+		page.Total = &total
+	}
+	backward := cursor != nil && cursor.Backward
+	q := query.Session(&gorm.Session{})
+	if cursor != nil {
+		clause, args := keyset.after(cursor)
+		q = q.Where(clause, args...)
+	}
+	var rows []*T
+	if err := q.Order(keyset.order(backward)).Limit(params.Limit + 1).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	more := len(rows) > params.Limit
+	if more {
+		rows = rows[:params.Limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, page, nil
+	}
+	hasNext := more
+	hasPrev := cursor != nil
+	if backward {
+		hasNext = true
+		hasPrev = more
+	}
+	page.HasMore = hasNext
+	if hasNext {
+		values, id := key(rows[len(rows)-1])
+		token := Encode(Cursor{Sort: keyset.Signature(), Values: values, ID: id})
+		page.NextCursor = &token
+	}
+	if hasPrev {
+		values, id := key(rows[0])
+		token := Encode(Cursor{Sort: keyset.Signature(), Values: values, ID: id, Backward: true})
+		page.PrevCursor = &token
+	}
+	return rows, page, nil
+}
+func direction(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
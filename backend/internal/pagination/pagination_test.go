@@ -0,0 +1,196 @@
+package pagination_test
+
+import (
+	"testing"
+	"time"
+	"weel-backend/internal/pagination"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := pagination.Cursor{Sort: "-created_at", Values: []interface{}{"2024-01-02T03:04:05Z"}, ID: 42, Backward: true}
+	decoded, err := pagination.Decode(pagination.Encode(cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, cursor.Sort, decoded.Sort)
+	assert.Equal(t, cursor.Values, decoded.Values)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, decoded.Backward)
+}
+func TestDecode_Invalid(t *testing.T) {
+	_, err := pagination.Decode("not a cursor!")
+	assert.Equal(t, pagination.ErrInvalidCursor, err)
+	_, err = pagination.Decode("bm90IGpzb24")
+	assert.Equal(t, pagination.ErrInvalidCursor, err)
+}
+func TestParamsNormalized(t *testing.T) {
+	assert.Equal(t, pagination.DefaultLimit, pagination.Params{}.Normalized().Limit)
+	assert.Equal(t, pagination.MaxLimit, pagination.Params{Limit: 1000}.Normalized().Limit)
+	assert.Equal(t, 5, pagination.Params{Limit: 5}.Normalized().Limit)
+}
+func TestKeysetSignature(t *testing.T) {
+	keyset := pagination.Keyset{
+		Keys:     []pagination.SortKey{{Column: "created_at", Desc: true}, {Column: "status"}},
+		IDColumn: "id",
+	}
+	assert.Equal(t, "-created_at,status", keyset.Signature())
+}
+
+type item struct {
+	ID        uint
+	CreatedAt int
+}
+
+type fakeDB struct {
+	db      *gorm.DB
+	results [][]*item
+	queries []string
+	vars    [][]interface{}
+}
+
+func newFakeDB(t *testing.T, results ...[]*item) *fakeDB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=pagination_test sslmode=disable"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	f := &fakeDB{db: db, results: results}
+	err = db.Callback().Query().After("gorm:query").Register("test:rows", func(tx *gorm.DB) {
+		f.queries = append(f.queries, tx.Statement.SQL.String())
+		f.vars = append(f.vars, tx.Statement.Vars)
+		require.NotEmpty(t, f.results, "unexpected query: %s", tx.Statement.SQL.String())
+		*tx.Statement.Dest.(*[]*item) = f.results[0]
+		f.results = f.results[1:]
+	})
+	require.NoError(t, err)
+	return f
+}
+func items(ids ...uint) []*item {
+	createdAt := map[uint]int{1: 10, 2: 20, 3: 20, 4: 20, 5: 30}
+	rows := make([]*item, 0, len(ids))
+	for _, id := range ids {
+		rows = append(rows, &item{ID: id, CreatedAt: createdAt[id]})
+	}
+	return rows
+}
+func ids(rows []*item) []uint {
+	out := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.ID)
+	}
+	return out
+}
+func itemKey(i *item) ([]interface{}, uint) {
+	return []interface{}{i.CreatedAt}, i.ID
+}
+func decodeCursor(t *testing.T, token *string) *pagination.Cursor {
+	require.NotNil(t, token)
+	cursor, err := pagination.Decode(*token)
+	require.NoError(t, err)
+	return cursor
+}
+
+var newestFirst = pagination.Keyset{Keys: []pagination.SortKey{{Column: "created_at", Desc: true, Kind: pagination.KindNumber}}, IDColumn: "id"}
+
+func TestFetch_ForwardPagesAcrossTies(t *testing.T) {
+	f := newFakeDB(t, items(5, 4, 3), items(3, 2, 1))
+	rows, page, err := pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Limit: 2}, itemKey)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{5, 4}, ids(rows))
+	assert.True(t, page.HasMore)
+	assert.Nil(t, page.PrevCursor)
+	assert.Contains(t, f.queries[0], "ORDER BY created_at DESC, id DESC LIMIT 3")
+	assert.NotContains(t, f.queries[0], "WHERE")
+	next := decodeCursor(t, page.NextCursor)
+	assert.Equal(t, "-created_at", next.Sort)
+	assert.Equal(t, []interface{}{float64(20)}, next.Values)
+	assert.Equal(t, uint(4), next.ID)
+	assert.False(t, next.Backward)
+	rows, page, err = pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Limit: 2, Cursor: *page.NextCursor}, itemKey)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 2}, ids(rows))
+	assert.Contains(t, f.queries[1], "WHERE ((created_at < $1) OR (created_at = $2 AND id < $3)) ORDER BY created_at DESC, id DESC LIMIT 3")
+	assert.Equal(t, []interface{}{float64(20), float64(20), uint(4)}, f.vars[1])
+	assert.True(t, page.HasMore)
+	assert.Equal(t, uint(2), decodeCursor(t, page.NextCursor).ID)
+	prev := decodeCursor(t, page.PrevCursor)
+	assert.Equal(t, uint(3), prev.ID)
+	assert.Equal(t, []interface{}{float64(20)}, prev.Values)
+	assert.True(t, prev.Backward)
+}
+func TestFetch_LastPageHasNoNextCursor(t *testing.T) {
+	f := newFakeDB(t, items(1))
+	cursor := pagination.Encode(pagination.Cursor{Sort: "-created_at", Values: []interface{}{20}, ID: 2})
+	rows, page, err := pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Limit: 2, Cursor: cursor}, itemKey)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1}, ids(rows))
+	assert.False(t, page.HasMore)
+	assert.Nil(t, page.NextCursor)
+	assert.Equal(t, uint(1), decodeCursor(t, page.PrevCursor).ID)
+}
+func TestFetch_BackwardPageReversesRows(t *testing.T) {
+	f := newFakeDB(t, items(4, 5))
+	cursor := pagination.Encode(pagination.Cursor{Sort: "-created_at", Values: []interface{}{20}, ID: 3, Backward: true})
+	rows, page, err := pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Limit: 2, Cursor: cursor}, itemKey)
+	require.NoError(t, err)
+	assert.Contains(t, f.queries[0], "WHERE ((created_at > $1) OR (created_at = $2 AND id > $3)) ORDER BY created_at ASC, id ASC LIMIT 3")
+	assert.Equal(t, []uint{5, 4}, ids(rows))
+	assert.True(t, page.HasMore)
+	assert.Nil(t, page.PrevCursor)
+	next := decodeCursor(t, page.NextCursor)
+	assert.Equal(t, uint(4), next.ID)
+	assert.False(t, next.Backward)
+}
+func TestFetch_BackwardPageWithMoreBefore(t *testing.T) {
+	f := newFakeDB(t, items(3, 4))
+	cursor := pagination.Encode(pagination.Cursor{Sort: "-created_at", Values: []interface{}{20}, ID: 2, Backward: true})
+	rows, page, err := pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Limit: 1, Cursor: cursor}, itemKey)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, ids(rows))
+	assert.True(t, page.HasMore)
+	assert.Equal(t, uint(3), decodeCursor(t, page.NextCursor).ID)
+	prev := decodeCursor(t, page.PrevCursor)
+	assert.Equal(t, uint(3), prev.ID)
+	assert.True(t, prev.Backward)
+}
+func TestFetch_RejectsMismatchedCursor(t *testing.T) {
+	f := newFakeDB(t)
+	otherSort := pagination.Encode(pagination.Cursor{Sort: "created_at", Values: []interface{}{20}, ID: 3})
+	_, _, err := pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Cursor: otherSort}, itemKey)
+	assert.Equal(t, pagination.ErrInvalidCursor, err)
+	wrongValues := pagination.Encode(pagination.Cursor{Sort: "-created_at", Values: []interface{}{20, "x"}, ID: 3})
+	_, _, err = pagination.Fetch(f.db.Model(&item{}), newestFirst, pagination.Params{Cursor: wrongValues}, itemKey)
+	assert.Equal(t, pagination.ErrInvalidCursor, err)
+	assert.Empty(t, f.queries)
+}
+func TestFetch_RejectsTamperedCursorValues(t *testing.T) {
+	f := newFakeDB(t)
+	keyset := pagination.Keyset{Keys: []pagination.SortKey{
+		{Column: "created_at", Desc: true, Kind: pagination.KindTime},
+		{Column: "status"},
+		{Column: "total", Kind: pagination.KindNumber},
+	}, IDColumn: "id"}
+	cases := map[string][]interface{}{
+		"number for time":   {12, "pending", 10},
+		"unparsable time":   {"yesterday", "pending", 10},
+		"number for string": {"2024-01-02T03:04:05Z", 5, 10},
+		"string for number": {"2024-01-02T03:04:05Z", "pending", "10"},
+		"null value":        {"2024-01-02T03:04:05Z", nil, 10},
+		"too few values":    {"2024-01-02T03:04:05Z", "pending"},
+	}
+	for name, values := range cases {
+		cursor := pagination.Encode(pagination.Cursor{Sort: keyset.Signature(), Values: values, ID: 3})
+		_, _, err := pagination.Fetch(f.db.Model(&item{}), keyset, pagination.Params{Cursor: cursor}, itemKey)
+		assert.Equal(t, pagination.ErrInvalidCursor, err, name)
+	}
+	assert.Empty(t, f.queries)
+}
+func TestFetch_ParsesTimeCursorValues(t *testing.T) {
+	f := newFakeDB(t, items())
+	keyset := pagination.Keyset{Keys: []pagination.SortKey{{Column: "created_at", Desc: true, Kind: pagination.KindTime}}, IDColumn: "id"}
+	cursor := pagination.Encode(pagination.Cursor{Sort: keyset.Signature(), Values: []interface{}{"2024-01-02T03:04:05.5Z"}, ID: 3})
+	_, _, err := pagination.Fetch(f.db.Model(&item{}), keyset, pagination.Params{Cursor: cursor}, itemKey)
+	require.NoError(t, err)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC)
+	assert.Equal(t, []interface{}{at, at, uint(3)}, f.vars[0])
+}
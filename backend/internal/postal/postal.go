@@ -0,0 +1,70 @@
+package postal
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+var (
+	ErrInvalidCountry    = errors.New("country must be an ISO 3166-1 alpha-2 code")
+	ErrInvalidPostalCode = errors.New("invalid postal code for country")
+)
+type format struct {
+	pattern *regexp.Regexp
+	// separator is re-inserted at sepIndex (counted from the end) after stripping whitespace and dashes.
+	separator string
+	sepIndex  int
+}
+var formats = map[string]format{
+	"US": {pattern: regexp.MustCompile(`^\d{5}(-\d{4})?$`), separator: "-", sepIndex: 4},
+	"CA": {pattern: regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] \d[ABCEGHJ-NPRSTV-Z]\d$`), separator: " ", sepIndex: 3},
+	"GB": {pattern: regexp.MustCompile(`^([A-Z]{1,2}\d[A-Z\d]?|GIR) \d[A-Z]{2}$`), separator: " ", sepIndex: 3},
+	"IE": {pattern: regexp.MustCompile(`^([AC-FHKNPRTV-Y]\d{2}|D6W) [0-9AC-FHKNPRTV-Y]{4}$`), separator: " ", sepIndex: 4},
+	"NL": {pattern: regexp.MustCompile(`^[1-9]\d{3} [A-Z]{2}$`), separator: " ", sepIndex: 2},
+	"JP": {pattern: regexp.MustCompile(`^\d{3}-\d{4}$`), separator: "-", sepIndex: 4},
+	"BR": {pattern: regexp.MustCompile(`^\d{5}-\d{3}$`), separator: "-", sepIndex: 3},
+	"DE": {pattern: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {pattern: regexp.MustCompile(`^\d{5}$`)},
+	"ES": {pattern: regexp.MustCompile(`^\d{5}$`)},
+	"IT": {pattern: regexp.MustCompile(`^\d{5}$`)},
+	"PK": {pattern: regexp.MustCompile(`^\d{5}$`)},
+	"AU": {pattern: regexp.MustCompile(`^\d{4}$`)},
+	"IN": {pattern: regexp.MustCompile(`^[1-9]\d{5}$`)},
+}
+var (
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	genericPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{1,9}$`)
+)
+func NormalizeCountry(country string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(country))
+	if !countryPattern.MatchString(c) {
+		return "", ErrInvalidCountry
+	}
+	return c, nil
+}
+func Normalize(country, code string) (string, error) {
+	c, err := NormalizeCountry(country)
+	if err != nil {
+		return "", err
+	}
+	raw := strings.ToUpper(strings.Join(strings.Fields(code), " "))
+	f, ok := formats[c]
+	if !ok {
+		if !genericPattern.MatchString(raw) {
+			return "", ErrInvalidPostalCode
+		}
+		return raw, nil
+	}
+	if f.separator != "" {
+		compact := strings.NewReplacer(" ", "", "-", "").Replace(raw)
+		if len(compact) > f.sepIndex {
+			candidate := compact[:len(compact)-f.sepIndex] + f.separator + compact[len(compact)-f.sepIndex:]
+			if f.pattern.MatchString(candidate) {
+				return candidate, nil
+			}
+		}
+	}
+	if !f.pattern.MatchString(raw) {
+		return "", ErrInvalidPostalCode
+	}
+	return raw, nil
+}
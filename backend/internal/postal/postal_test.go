@@ -0,0 +1,50 @@
+package postal_test
+
+import (
+	"testing"
+	"weel-backend/internal/postal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		country, code, want string
+	}{
+		{"us", "94105", "94105"},
+		{"US", "941051234", "94105-1234"},
+		{"US", "94105-1234", "94105-1234"},
+		{"ca", "k1a0b1", "K1A 0B1"},
+		{"GB", "sw1a1aa", "SW1A 1AA"},
+		{"GB", "M1 1AE", "M1 1AE"},
+		{"NL", "1012ab", "1012 AB"},
+		{"IE", "D02X285", "D02 X285"},
+		{"JP", "1000001", "100-0001"},
+		{"DE", " 10115 ", "10115"},
+		{"PK", "54000", "54000"},
+		{"SE", "114 55", "114 55"},
+	}
+	for _, tc := range cases {
+		got, err := postal.Normalize(tc.country, tc.code)
+		assert.NoError(t, err, tc.country+" "+tc.code)
+		assert.Equal(t, tc.want, got)
+	}
+}
+func TestNormalize_Invalid(t *testing.T) {
+	cases := []struct {
+		country, code string
+	}{
+		{"US", "9410"},
+		{"US", "ABCDE"},
+		{"CA", "D1A 0B1"},
+		{"GB", "12345"},
+		{"DE", "1011"},
+		{"SE", "!"},
+	}
+	for _, tc := range cases {
+		_, err := postal.Normalize(tc.country, tc.code)
+		assert.Equal(t, postal.ErrInvalidPostalCode, err, tc.country+" "+tc.code)
+	}
+	_, err := postal.Normalize("USA", "94105")
+	assert.Equal(t, postal.ErrInvalidCountry, err)
+}
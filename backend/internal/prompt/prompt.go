@@ -0,0 +1,199 @@
+package prompt
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"weel-backend/internal/domain"
+)
+const (
+	SuggestProducts = "suggest_products"
+	MaxTemperature  = 2
+	MaxTokens       = 4096
+)
+var (
+	ErrInvalidTemplate = errors.New("invalid prompt template")
+	ErrUnknownTemplate = errors.New("unknown prompt template")
+)
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+var fileNamePattern = regexp.MustCompile(`^([a-z0-9_]+)\.v([1-9][0-9]*)\.tmpl$`)
+type Data struct {
+	Summary         string
+	DeliveryAddress string
+}
+var defaults = mustLoadDefaults()
+func mustLoadDefaults() map[string][]domain.PromptTemplate {
+	templates, err := loadDefaults(templateFS)
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+func loadDefaults(fsys fs.FS) (map[string][]domain.PromptTemplate, error) {
+	files, err := fs.Glob(fsys, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string][]domain.PromptTemplate)
+	for _, file := range files {
+		m := fileNamePattern.FindStringSubmatch(path.Base(file))
+		if m == nil {
+			return nil, fmt.Errorf("%w: %s must be named <name>.v<version>.tmpl", ErrInvalidTemplate, file)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		version, _ := strconv.Atoi(m[2])
+		t, err := Parse(m[1], version, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		templates[t.Name] = append(templates[t.Name], *t)
+	}
+	for name := range templates {
+		sort.Slice(templates[name], func(i, j int) bool {
+			return templates[name][i].Version < templates[name][j].Version
+		})
+	}
+	return templates, nil
+}
+func Parse(name string, version int, data []byte) (*domain.PromptTemplate, error) {
+	t := &domain.PromptTemplate{Name: name, Version: version, BuiltIn: true}
+	var section *strings.Builder
+	var system, user strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case "--- system":
+			section = &system
+			continue
+		case "--- user":
+			section = &user
+			continue
+		}
+		if section != nil {
+			if section.Len() > 0 {
+				section.WriteByte('\n')
+			}
+			section.WriteString(line)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: expected key: value, got %q", ErrInvalidTemplate, line)
+		}
+		value = strings.TrimSpace(value)
+		var err error
+		switch strings.TrimSpace(key) {
+		case "model":
+			t.Model = value
+		case "temperature":
+			var temperature float64
+			temperature, err = strconv.ParseFloat(value, 32)
+			t.Temperature = float32(temperature)
+		case "max_tokens":
+			t.MaxTokens, err = strconv.Atoi(value)
+		default:
+			return nil, fmt.Errorf("%w: unknown setting %q", ErrInvalidTemplate, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	t.System = strings.TrimRight(system.String(), "\n")
+	t.User = strings.TrimRight(user.String(), "\n")
+	if err := Validate(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+func Validate(t *domain.PromptTemplate) error {
+	switch {
+	case strings.TrimSpace(t.Model) == "":
+		return fmt.Errorf("%w: model is required", ErrInvalidTemplate)
+	case t.Temperature < 0 || t.Temperature > MaxTemperature:
+		return fmt.Errorf("%w: temperature must be between 0 and %d", ErrInvalidTemplate, MaxTemperature)
+	case t.MaxTokens <= 0 || t.MaxTokens > MaxTokens:
+		return fmt.Errorf("%w: max_tokens must be between 1 and %d", ErrInvalidTemplate, MaxTokens)
+	case strings.TrimSpace(t.User) == "":
+		return fmt.Errorf("%w: user prompt is required", ErrInvalidTemplate)
+	}
+	if _, err := Render(t, Data{Summary: "sample", DeliveryAddress: "sample"}); err != nil {
+		return err
+	}
+	return nil
+}
+func Render(t *domain.PromptTemplate, data Data) (*domain.RenderedPrompt, error) {
+	system, err := execute(t.Name+".system", t.System, data)
+	if err != nil {
+		return nil, err
+	}
+	user, err := execute(t.Name+".user", t.User, data)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RenderedPrompt{
+		Name:        t.Name,
+		Version:     t.Version,
+		Model:       t.Model,
+		Temperature: t.Temperature,
+		MaxTokens:   t.MaxTokens,
+		System:      system,
+		User:        user,
+	}, nil
+}
+func execute(name, text string, data Data) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return out.String(), nil
+}
+func Names() []string {
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+func Defaults(name string) []domain.PromptTemplate {
+	return append([]domain.PromptTemplate(nil), defaults[name]...)
+}
+func Default(name string) (*domain.PromptTemplate, error) {
+	versions := defaults[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	t := versions[len(versions)-1]
+	return &t, nil
+}
+func DefaultVersion(name string, version int) (*domain.PromptTemplate, bool) {
+	for _, t := range defaults[name] {
+		if t.Version == version {
+			return &t, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,61 @@
+package prompt_test
+
+import (
+	"strings"
+	"testing"
+	"weel-backend/internal/prompt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	template, err := prompt.Default(prompt.SuggestProducts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, template.Version)
+	assert.True(t, template.BuiltIn)
+	assert.Equal(t, "gpt-4o-mini", template.Model)
+	assert.InDelta(t, 0.7, template.Temperature, 1e-6)
+	assert.Equal(t, 500, template.MaxTokens)
+	assert.Contains(t, template.System, "pharmacy receptionist")
+	_, err = prompt.Default("unknown")
+	assert.ErrorIs(t, err, prompt.ErrUnknownTemplate)
+	assert.Equal(t, []string{prompt.SuggestProducts}, prompt.Names())
+}
+func TestRender(t *testing.T) {
+	template, err := prompt.Default(prompt.SuggestProducts)
+	require.NoError(t, err)
+	rendered, err := prompt.Render(template, prompt.Data{Summary: "Need something for a cough"})
+	require.NoError(t, err)
+	assert.Contains(t, rendered.User, "Customer Request: Need something for a cough\nPlease respond ONLY")
+	assert.NotContains(t, rendered.User, "Delivery Address")
+	rendered, err = prompt.Render(template, prompt.Data{Summary: "Need something for a cough", DeliveryAddress: "1 Main St"})
+	require.NoError(t, err)
+	assert.Contains(t, rendered.User, "Customer Request: Need something for a cough\nDelivery Address: 1 Main St\n")
+	assert.Equal(t, template.Model, rendered.Model)
+	assert.Equal(t, 1, rendered.Version)
+}
+func TestParse(t *testing.T) {
+	template, err := prompt.Parse("triage", 3, []byte("model: gpt-4o\ntemperature: 0\nmax_tokens: 200\n--- system\nBe brief.\n--- user\nSummary: {{.Summary}}\n--- not a section\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "triage", template.Name)
+	assert.Equal(t, 3, template.Version)
+	assert.Equal(t, float32(0), template.Temperature)
+	assert.Equal(t, "Be brief.", template.System)
+	assert.Equal(t, "Summary: {{.Summary}}\n--- not a section", template.User)
+}
+func TestParse_Invalid(t *testing.T) {
+	cases := []string{
+		"temperature: 0.5\nmax_tokens: 200\n--- user\nhi",
+		"model: gpt-4o\ntemperature: 3\nmax_tokens: 200\n--- user\nhi",
+		"model: gpt-4o\ntemperature: 0.5\nmax_tokens: 0\n--- user\nhi",
+		"model: gpt-4o\ntemperature: 0.5\nmax_tokens: 200\n--- system\nhi",
+		"model: gpt-4o\ntemperature: 0.5\nmax_tokens: 200\ntop_p: 1\n--- user\nhi",
+		"model: gpt-4o\ntemperature: 0.5\nmax_tokens: 200\n--- user\n{{.Summary",
+		"model: gpt-4o\ntemperature: 0.5\nmax_tokens: 200\n--- user\n{{.Patient}}",
+	}
+	for _, tc := range cases {
+		_, err := prompt.Parse("triage", 1, []byte(tc))
+		assert.ErrorIs(t, err, prompt.ErrInvalidTemplate, strings.ReplaceAll(tc, "\n", `\n`))
+	}
+}
@@ -0,0 +1,55 @@
+package ratelimit
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	idleTTL   time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+func NewMemoryStore(idleTTL time.Duration) *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		idleTTL: idleTTL,
+		now:     time.Now,
+	}
+}
+func (s *MemoryStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: policy.capacity(), updated: now}
+		s.buckets[key] = b
+	}
+	elapsed := math.Max(0, now.Sub(b.updated).Seconds())
+	b.tokens = math.Min(policy.capacity(), b.tokens+elapsed*policy.ratePerSecond())
+	b.updated = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return newResult(policy, b.tokens, allowed), nil
+}
+func (s *MemoryStore) sweep(now time.Time) {
+	if s.idleTTL <= 0 || now.Sub(s.lastSweep) < s.idleTTL {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) > s.idleTTL {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimit
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"gorm.io/gorm"
+)
+const (
+	takeSQL = `INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (@key, CAST(@capacity AS double precision) - 1, true, now())
+ON CONFLICT (key) DO UPDATE SET
+	tokens = LEAST(CAST(@capacity AS double precision), b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::double precision, 0) * CAST(@rate AS double precision))
+		- CASE WHEN LEAST(CAST(@capacity AS double precision), b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::double precision, 0) * CAST(@rate AS double precision)) >= 1 THEN 1 ELSE 0 END,
+	allowed = LEAST(CAST(@capacity AS double precision), b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::double precision, 0) * CAST(@rate AS double precision)) >= 1,
+	updated_at = now()
+RETURNING tokens, allowed`
+	pruneSQL = `DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => ?)`
+)
+type PostgresStore struct {
+	db        *gorm.DB
+	idleTTL   time.Duration
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+func NewPostgresStore(db *gorm.DB, idleTTL time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, idleTTL: idleTTL}
+}
+func (s *PostgresStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	s.prune(ctx)
+	var row struct {
+		Tokens  float64
+		Allowed bool
+	}
+	err := s.db.WithContext(ctx).Raw(takeSQL, map[string]interface{}{
+		"key":      key,
+		"capacity": policy.capacity(),
+		"rate":     policy.ratePerSecond(),
+	}).Scan(&row).Error
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(policy, row.Tokens, row.Allowed), nil
+}
+func (s *PostgresStore) prune(ctx context.Context) {
+	if s.idleTTL <= 0 {
+		return
+	}
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < s.idleTTL {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	s.mu.Unlock()
+	if err := s.db.WithContext(ctx).Exec(pruneSQL, s.idleTTL.Seconds()).Error; err != nil {
+		slog.WarnContext(ctx, "failed to prune rate limit buckets", "error", err)
+	}
+}
@@ -0,0 +1,146 @@
+package ratelimit
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"weel-backend/config"
+	"gorm.io/gorm"
+)
+const (
+	StoreMemory       = "memory"
+	StorePostgres     = "postgres"
+	PolicyLogin       = "login"
+	PolicySuggestions = "suggestions"
+	PolicyAPI         = "api"
+	PolicyPublic      = "public"
+)
+var ErrInvalidPolicy = errors.New("rate limit policy must look like 10/1m or 10/1m:20 (limit/period[:burst])")
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+func ParsePolicy(name, spec string) (Policy, error) {
+	policy := Policy{Name: name}
+	rest, burst, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+	limit, period, ok := strings.Cut(rest, "/")
+	if !ok {
+		return policy, fmt.Errorf("%w: %s=%q", ErrInvalidPolicy, name, spec)
+	}
+	var err error
+	if policy.Limit, err = strconv.Atoi(limit); err != nil || policy.Limit <= 0 {
+		return policy, fmt.Errorf("%w: %s=%q", ErrInvalidPolicy, name, spec)
+	}
+	if policy.Period, err = time.ParseDuration(period); err != nil || policy.Period <= 0 {
+		return policy, fmt.Errorf("%w: %s=%q", ErrInvalidPolicy, name, spec)
+	}
+	policy.Burst = policy.Limit
+	if hasBurst {
+		if policy.Burst, err = strconv.Atoi(burst); err != nil || policy.Burst <= 0 {
+			return policy, fmt.Errorf("%w: %s=%q", ErrInvalidPolicy, name, spec)
+		}
+	}
+	return policy, nil
+}
+func (p Policy) ratePerSecond() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+func (p Policy) capacity() float64 {
+	return float64(p.Burst)
+}
+func (p Policy) FillTime() time.Duration {
+	return time.Duration(p.capacity() / p.ratePerSecond() * float64(time.Second))
+}
+func (p Policy) HeaderValue() string {
+	value := fmt.Sprintf("%d;w=%d", p.Limit, int(math.Ceil(p.Period.Seconds())))
+	if p.Burst != p.Limit {
+		value += fmt.Sprintf(";burst=%d", p.Burst)
+	}
+	return value
+}
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+func newResult(p Policy, tokens float64, allowed bool) Result {
+	rate := p.ratePerSecond()
+	tokens = math.Max(0, tokens)
+	res := Result{
+		Allowed:   allowed,
+		Limit:     p.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((p.capacity() - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return res
+}
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+}
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+func NewLimiter(store Store, policies ...Policy) *Limiter {
+	l := &Limiter{store: store, policies: make(map[string]Policy, len(policies))}
+	for _, p := range policies {
+		l.policies[p.Name] = p
+	}
+	return l
+}
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	if l == nil {
+		return Policy{}, false
+	}
+	p, ok := l.policies[name]
+	return p, ok
+}
+func (l *Limiter) Take(ctx context.Context, policy Policy, key string) (Result, error) {
+	return l.store.Take(ctx, policy.Name+":"+key, policy)
+}
+func New(cfg config.RateLimitConfig, db *gorm.DB) (*Limiter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	specs := map[string]string{
+		PolicyLogin:       cfg.Login,
+		PolicySuggestions: cfg.Suggestions,
+		PolicyAPI:         cfg.API,
+		PolicyPublic:      cfg.Public,
+	}
+	policies := make([]Policy, 0, len(specs))
+	var longest time.Duration
+	for name, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		p, err := ParsePolicy(name, spec)
+		if err != nil {
+			return nil, err
+		}
+		if p.FillTime() > longest {
+			longest = p.FillTime()
+		}
+		policies = append(policies, p)
+	}
+	var store Store
+	switch cfg.Store {
+	case "", StoreMemory:
+		store = NewMemoryStore(longest)
+	case StorePostgres:
+		store = NewPostgresStore(db, longest)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.Store)
+	}
+	return NewLimiter(store, policies...), nil
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"weel-backend/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("login", "5/1m")
+	require.NoError(t, err)
+	assert.Equal(t, Policy{Name: "login", Limit: 5, Period: time.Minute, Burst: 5}, p)
+	assert.Equal(t, "5;w=60", p.HeaderValue())
+
+	p, err = ParsePolicy("api", " 300/1m:50 ")
+	require.NoError(t, err)
+	assert.Equal(t, 50, p.Burst)
+	assert.Equal(t, "300;w=60;burst=50", p.HeaderValue())
+
+	for _, spec := range []string{"", "5", "0/1m", "5/0s", "5/soon", "5/1m:0", "x/1m"} {
+		_, err := ParsePolicy("bad", spec)
+		assert.ErrorIs(t, err, ErrInvalidPolicy, spec)
+	}
+}
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(time.Hour)
+	store.now = func() time.Time { return now }
+	policy := Policy{Name: "login", Limit: 3, Period: 3 * time.Second, Burst: 3}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, "ip:1.2.3.4", policy)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := store.Take(ctx, "ip:1.2.3.4", policy)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.Reset)
+
+	other, err := store.Take(ctx, "ip:5.6.7.8", policy)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "buckets are per key")
+
+	now = now.Add(time.Second)
+	res, err = store.Take(ctx, "ip:1.2.3.4", policy)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "one token refills per second")
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestMemoryStoreSweepsIdleBuckets(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore(time.Minute)
+	store.now = func() time.Time { return now }
+	policy := Policy{Name: "api", Limit: 10, Period: time.Minute, Burst: 10}
+
+	_, err := store.Take(context.Background(), "user:1", policy)
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, err = store.Take(context.Background(), "user:2", policy)
+	require.NoError(t, err)
+
+	assert.NotContains(t, store.buckets, "user:1")
+	assert.Contains(t, store.buckets, "user:2")
+}
+
+func TestNew(t *testing.T) {
+	limiter, err := New(config.RateLimitConfig{Enabled: false}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+	_, ok := limiter.Policy(PolicyLogin)
+	assert.False(t, ok)
+
+	limiter, err = New(config.RateLimitConfig{Enabled: true, Store: StoreMemory, Login: "5/1m", Suggestions: ""}, nil)
+	require.NoError(t, err)
+	_, ok = limiter.Policy(PolicyLogin)
+	assert.True(t, ok)
+	_, ok = limiter.Policy(PolicySuggestions)
+	assert.False(t, ok, "empty spec disables the policy")
+
+	limiter, err = New(config.RateLimitConfig{Enabled: true, Public: "60/1m"}, nil)
+	require.NoError(t, err)
+	public, ok := limiter.Policy(PolicyPublic)
+	assert.True(t, ok)
+	assert.Equal(t, 60, public.Limit)
+
+	_, err = New(config.RateLimitConfig{Enabled: true, Login: "lots"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidPolicy)
+
+	_, err = New(config.RateLimitConfig{Enabled: true, Store: "redis"}, nil)
+	assert.Error(t, err)
+}
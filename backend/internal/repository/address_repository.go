@@ -0,0 +1,63 @@
+package repository
+import (
+	"context"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type AddressRepository interface {
+	Create(ctx context.Context, address *domain.Address) error
+	GetByID(ctx context.Context, id uint) (*domain.Address, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*domain.Address, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	Update(ctx context.Context, address *domain.Address) error
+	Delete(ctx context.Context, id uint) error
+	SetDefault(ctx context.Context, userID, addressID uint) error
+}
+type addressRepository struct {
+	db *gorm.DB
+}
+func NewAddressRepository(db *gorm.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+func (r *addressRepository) Create(ctx context.Context, address *domain.Address) error {
+	return r.db.WithContext(ctx).Create(address).Error
+}
+func (r *addressRepository) GetByID(ctx context.Context, id uint) (*domain.Address, error) {
+	var address domain.Address
+	err := r.db.WithContext(ctx).First(&address, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+func (r *addressRepository) ListByUserID(ctx context.Context, userID uint) ([]*domain.Address, error) {
+	var addresses []*domain.Address
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("is_default DESC").
+		Order("created_at DESC").
+		Find(&addresses).Error
+	return addresses, err
+}
+func (r *addressRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Address{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+func (r *addressRepository) Update(ctx context.Context, address *domain.Address) error {
+	return r.db.WithContext(ctx).Save(address).Error
+}
+func (r *addressRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Address{}, id).Error
+}
+func (r *addressRepository) SetDefault(ctx context.Context, userID, addressID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Address{}).
+			Where("user_id = ? AND id <> ?", userID, addressID).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Address{}).
+			Where("user_id = ? AND id = ?", userID, addressID).
+			Update("is_default", true).Error
+	})
+}
@@ -0,0 +1,65 @@
+package repository
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+type AIQuotaRepository interface {
+	GetLimit(ctx context.Context, userID uint) (*domain.AISuggestionQuota, error)
+	SaveLimit(ctx context.Context, quota *domain.AISuggestionQuota) error
+	DeleteLimit(ctx context.Context, userID uint) error
+	GetUsage(ctx context.Context, userID uint, day time.Time) (int, error)
+	IncrementUsage(ctx context.Context, userID uint, day time.Time, limit int) (int, bool, error)
+	DecrementUsage(ctx context.Context, userID uint, day time.Time) error
+}
+type aiQuotaRepository struct {
+	db *gorm.DB
+}
+func NewAIQuotaRepository(db *gorm.DB) AIQuotaRepository {
+	return &aiQuotaRepository{db: db}
+}
+func (r *aiQuotaRepository) GetLimit(ctx context.Context, userID uint) (*domain.AISuggestionQuota, error) {
+	var quotas []*domain.AISuggestionQuota
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Limit(1).Find(&quotas).Error
+	if err != nil || len(quotas) == 0 {
+		return nil, err
+	}
+	return quotas[0], nil
+}
+func (r *aiQuotaRepository) SaveLimit(ctx context.Context, quota *domain.AISuggestionQuota) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"daily_limit", "updated_at"}),
+	}).Create(quota).Error
+}
+func (r *aiQuotaRepository) DeleteLimit(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.AISuggestionQuota{}).Error
+}
+func (r *aiQuotaRepository) GetUsage(ctx context.Context, userID uint, day time.Time) (int, error) {
+	var usage domain.AISuggestionUsage
+	err := r.db.WithContext(ctx).Where("user_id = ? AND day = ?", userID, day).Limit(1).Find(&usage).Error
+	return usage.Count, err
+}
+func (r *aiQuotaRepository) IncrementUsage(ctx context.Context, userID uint, day time.Time, limit int) (int, bool, error) {
+	var counts []int
+	err := r.db.WithContext(ctx).Raw(`INSERT INTO ai_suggestion_usage (user_id, day, count, updated_at)
+VALUES (?, ?, 1, now())
+ON CONFLICT (user_id, day) DO UPDATE SET count = ai_suggestion_usage.count + 1, updated_at = now()
+WHERE ai_suggestion_usage.count < ?
+RETURNING count`, userID, day, limit).Scan(&counts).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if len(counts) == 0 {
+		used, err := r.GetUsage(ctx, userID, day)
+		return used, false, err
+	}
+	return counts[0], true, nil
+}
+func (r *aiQuotaRepository) DecrementUsage(ctx context.Context, userID uint, day time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.AISuggestionUsage{}).
+		Where("user_id = ? AND day = ? AND count > 0", userID, day).
+		Updates(map[string]interface{}{"count": gorm.Expr("count - 1"), "updated_at": time.Now()}).Error
+}
@@ -0,0 +1,55 @@
+package repository
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type AISuggestionRepository interface {
+	Create(ctx context.Context, session *domain.AISuggestionSession) error
+	GetByID(ctx context.Context, id uint) (*domain.AISuggestionSession, error)
+	LinkOrder(ctx context.Context, id, orderID uint, accepted int) (bool, error)
+	Stats(ctx context.Context, from, to time.Time) (*domain.AISuggestionStats, error)
+}
+type aiSuggestionRepository struct {
+	db *gorm.DB
+}
+func NewAISuggestionRepository(db *gorm.DB) AISuggestionRepository {
+	return &aiSuggestionRepository{db: db}
+}
+func (r *aiSuggestionRepository) Create(ctx context.Context, session *domain.AISuggestionSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+func (r *aiSuggestionRepository) GetByID(ctx context.Context, id uint) (*domain.AISuggestionSession, error) {
+	var session domain.AISuggestionSession
+	err := r.db.WithContext(ctx).First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+func (r *aiSuggestionRepository) LinkOrder(ctx context.Context, id, orderID uint, accepted int) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.AISuggestionSession{}).
+		Where("id = ? AND order_id IS NULL", id).
+		Updates(map[string]interface{}{"order_id": orderID, "accepted_count": accepted, "updated_at": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+func (r *aiSuggestionRepository) Stats(ctx context.Context, from, to time.Time) (*domain.AISuggestionStats, error) {
+	var stats domain.AISuggestionStats
+	err := r.db.WithContext(ctx).Model(&domain.AISuggestionSession{}).Select(`count(*) AS sessions,
+		count(order_id) AS converted_sessions,
+		coalesce(sum(jsonb_array_length(products)), 0) AS suggested_products,
+		coalesce(sum(accepted_count), 0) AS accepted_products`).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	if stats.Sessions > 0 {
+		stats.ConversionRate = float64(stats.ConvertedSessions) / float64(stats.Sessions)
+	}
+	if stats.SuggestedProducts > 0 {
+		stats.AcceptanceRate = float64(stats.AcceptedProducts) / float64(stats.SuggestedProducts)
+	}
+	return &stats, nil
+}
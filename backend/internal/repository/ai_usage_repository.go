@@ -0,0 +1,49 @@
+package repository
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type AIUsageFilters struct {
+	From   *time.Time
+	To     *time.Time
+	UserID *uint
+}
+type AIUsageRepository interface {
+	Create(ctx context.Context, usage *domain.AIUsage) error
+	Summarize(ctx context.Context, filters AIUsageFilters) ([]*domain.AIUsageSummary, error)
+}
+type aiUsageRepository struct {
+	db *gorm.DB
+}
+func NewAIUsageRepository(db *gorm.DB) AIUsageRepository {
+	return &aiUsageRepository{db: db}
+}
+func (r *aiUsageRepository) Create(ctx context.Context, usage *domain.AIUsage) error {
+	return r.db.WithContext(ctx).Create(usage).Error
+}
+func (r *aiUsageRepository) Summarize(ctx context.Context, filters AIUsageFilters) ([]*domain.AIUsageSummary, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AIUsage{}).Select(`date_trunc('day', created_at AT TIME ZONE 'UTC') AS day,
+		user_id,
+		outcome,
+		count(*) AS requests,
+		coalesce(sum(prompt_tokens), 0) AS prompt_tokens,
+		coalesce(sum(completion_tokens), 0) AS completion_tokens,
+		coalesce(sum(estimated_cost_usd), 0) AS estimated_cost_usd,
+		coalesce(avg(latency_ms), 0) AS avg_latency_ms`)
+	if filters.From != nil {
+		query = query.Where("created_at >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("created_at < ?", *filters.To)
+	}
+	if filters.UserID != nil {
+		query = query.Where("user_id = ?", *filters.UserID)
+	}
+	var summaries []*domain.AIUsageSummary
+	err := query.Group("day, user_id, outcome").
+		Order("day DESC, user_id ASC NULLS LAST, outcome ASC").
+		Scan(&summaries).Error
+	return summaries, err
+}
@@ -0,0 +1,47 @@
+package repository
+import (
+	"context"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type DeliveryZoneRepository interface {
+	Create(ctx context.Context, zone *domain.DeliveryZone) error
+	GetByID(ctx context.Context, id uint) (*domain.DeliveryZone, error)
+	List(ctx context.Context) ([]*domain.DeliveryZone, error)
+	ListActive(ctx context.Context) ([]*domain.DeliveryZone, error)
+	Update(ctx context.Context, zone *domain.DeliveryZone) error
+	Delete(ctx context.Context, id uint) error
+}
+type deliveryZoneRepository struct {
+	db *gorm.DB
+}
+func NewDeliveryZoneRepository(db *gorm.DB) DeliveryZoneRepository {
+	return &deliveryZoneRepository{db: db}
+}
+func (r *deliveryZoneRepository) Create(ctx context.Context, zone *domain.DeliveryZone) error {
+	return r.db.WithContext(ctx).Create(zone).Error
+}
+func (r *deliveryZoneRepository) GetByID(ctx context.Context, id uint) (*domain.DeliveryZone, error) {
+	var zone domain.DeliveryZone
+	err := r.db.WithContext(ctx).First(&zone, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+func (r *deliveryZoneRepository) List(ctx context.Context) ([]*domain.DeliveryZone, error) {
+	var zones []*domain.DeliveryZone
+	err := r.db.WithContext(ctx).Order("priority DESC").Order("name ASC").Find(&zones).Error
+	return zones, err
+}
+func (r *deliveryZoneRepository) ListActive(ctx context.Context) ([]*domain.DeliveryZone, error) {
+	var zones []*domain.DeliveryZone
+	err := r.db.WithContext(ctx).Where("active = ?", true).Order("priority DESC").Order("id ASC").Find(&zones).Error
+	return zones, err
+}
+func (r *deliveryZoneRepository) Update(ctx context.Context, zone *domain.DeliveryZone) error {
+	return r.db.WithContext(ctx).Save(zone).Error
+}
+func (r *deliveryZoneRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.DeliveryZone{}, id).Error
+}
@@ -1,12 +1,13 @@
 package repository
 import (
+	"context"
 	"weel-backend/internal/domain"
 	"gorm.io/gorm"
 )
 type FeatureFlagRepository interface {
-	GetAll() ([]*domain.FeatureFlag, error)
-	GetByName(name string) (*domain.FeatureFlag, error)
-	Update(flag *domain.FeatureFlag) error
+	GetAll(ctx context.Context) ([]*domain.FeatureFlag, error)
+	GetByName(ctx context.Context, name string) (*domain.FeatureFlag, error)
+	Update(ctx context.Context, flag *domain.FeatureFlag) error
 }
 type featureFlagRepository struct {
 	db *gorm.DB
@@ -14,19 +15,19 @@ type featureFlagRepository struct {
 func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
 	return &featureFlagRepository{db: db}
 }
-func (r *featureFlagRepository) GetAll() ([]*domain.FeatureFlag, error) {
+func (r *featureFlagRepository) GetAll(ctx context.Context) ([]*domain.FeatureFlag, error) {
 	var flags []*domain.FeatureFlag
-	err := r.db.Order("name ASC").Find(&flags).Error
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&flags).Error
 	return flags, err
 }
-func (r *featureFlagRepository) GetByName(name string) (*domain.FeatureFlag, error) {
+func (r *featureFlagRepository) GetByName(ctx context.Context, name string) (*domain.FeatureFlag, error) {
 	var flag domain.FeatureFlag
-	err := r.db.Where("name = ?", name).First(&flag).Error
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&flag).Error
 	if err != nil {
 		return nil, err
 	}
 	return &flag, nil
 }
-func (r *featureFlagRepository) Update(flag *domain.FeatureFlag) error {
-	return r.db.Save(flag).Error
+func (r *featureFlagRepository) Update(ctx context.Context, flag *domain.FeatureFlag) error {
+	return r.db.WithContext(ctx).Save(flag).Error
 }
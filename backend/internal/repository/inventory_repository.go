@@ -0,0 +1,43 @@
+package repository
+import (
+	"context"
+	"strings"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+type InventoryRepository interface {
+	ListByStore(ctx context.Context, storeID uint, search string, limit int) ([]*domain.InventoryItem, error)
+	GetBySKU(ctx context.Context, storeID uint, sku string) (*domain.InventoryItem, error)
+	Upsert(ctx context.Context, item *domain.InventoryItem) error
+}
+type inventoryRepository struct {
+	db *gorm.DB
+}
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+func (r *inventoryRepository) ListByStore(ctx context.Context, storeID uint, search string, limit int) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	query := r.db.WithContext(ctx).Where("store_id = ?", storeID)
+	if search = strings.TrimSpace(search); search != "" {
+		like := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(sku) LIKE ?", like, like)
+	}
+	err := query.Order("name ASC").Limit(limit).Find(&items).Error
+	return items, err
+}
+func (r *inventoryRepository) GetBySKU(ctx context.Context, storeID uint, sku string) (*domain.InventoryItem, error) {
+	var item domain.InventoryItem
+	err := r.db.WithContext(ctx).Where("store_id = ? AND sku = ?", storeID, sku).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+func (r *inventoryRepository) Upsert(ctx context.Context, item *domain.InventoryItem) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "store_id"}, {Name: "sku"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "quantity", "price", "updated_at", "deleted_at"}),
+	}).Create(item).Error
+}
@@ -1,23 +1,60 @@
 package repository
 import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/pagination"
 	"gorm.io/gorm"
 )
 type OrderRepository interface {
-	Create(order *domain.Order) error
-	GetByID(id uint) (*domain.Order, error)
-	GetByUserID(userID uint, limit, offset int) ([]*domain.Order, error)
-	GetByUserIDWithFilters(userID uint, filters OrderFilters) ([]*domain.Order, error)
-	Update(order *domain.Order) error
-	Delete(id uint) error
+	Create(ctx context.Context, order *domain.Order) error
+	GetByID(ctx context.Context, id uint) (*domain.Order, error)
+	GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*domain.Order, error)
+	GetByUserIDWithFilters(ctx context.Context, userID uint, filters OrderFilters) ([]*domain.Order, *pagination.Page, error)
+	ListArrivedAtStore(ctx context.Context, storeID uint) ([]*domain.Order, error)
+	ListByStore(ctx context.Context, storeID uint, statuses []domain.OrderStatus, limit int) ([]*domain.Order, error)
+	Update(ctx context.Context, order *domain.Order) error
+	Delete(ctx context.Context, id uint) error
 }
 type OrderFilters struct {
-	Status             *string
-	DeliveryPreference *string
-	SortBy             string
-	SortOrder          string
-	Limit              int
-	Offset             int
+	Statuses            []string
+	DeliveryPreferences []string
+	CreatedAfter        *time.Time
+	CreatedBefore       *time.Time
+	PostalCodePrefix    string
+	Search              string
+	Sort                []SortField
+	Cursor              string
+	Limit               int
+	IncludeTotal        bool
+}
+type SortField struct {
+	Field string
+	Desc  bool
+}
+type orderSortColumn struct {
+	column string
+	kind   pagination.ValueKind
+	value  func(o *domain.Order) interface{}
+}
+var ErrUnsupportedSortField = errors.New("unsupported sort field")
+var orderSortColumns = map[string]orderSortColumn{
+	"created_at":          {column: "created_at", kind: pagination.KindTime, value: func(o *domain.Order) interface{} { return o.CreatedAt }},
+	"updated_at":          {column: "updated_at", kind: pagination.KindTime, value: func(o *domain.Order) interface{} { return o.UpdatedAt }},
+	"status":              {column: "status", value: func(o *domain.Order) interface{} { return o.Status }},
+	"delivery_preference": {column: "delivery_preference", value: func(o *domain.Order) interface{} { return o.DeliveryPreference }},
+	"total":               {column: "total", kind: pagination.KindNumber, value: func(o *domain.Order) interface{} { return o.Total }},
+}
+func OrderSortFields() []string {
+	fields := make([]string, 0, len(orderSortColumns))
+	for field := range orderSortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
 }
 type orderRepository struct {
 	db *gorm.DB
@@ -25,60 +62,98 @@ type orderRepository struct {
 func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
-func (r *orderRepository) Create(order *domain.Order) error {
-	return r.db.Create(order).Error
+func (r *orderRepository) Create(ctx context.Context, order *domain.Order) error {
+	return r.db.WithContext(ctx).Create(order).Error
 }
-func (r *orderRepository) GetByID(id uint) (*domain.Order, error) {
+func (r *orderRepository) GetByID(ctx context.Context, id uint) (*domain.Order, error) {
 	var order domain.Order
-	err := r.db.Preload("User").First(&order, id).Error
+	err := r.db.WithContext(ctx).Preload("User").First(&order, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
-func (r *orderRepository) GetByUserID(userID uint, limit, offset int) ([]*domain.Order, error) {
+func (r *orderRepository) GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*domain.Order, error) {
 	var orders []*domain.Order
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
 		Limit(limit).
 		Offset(offset).
 		Order("created_at DESC").
 		Find(&orders).Error
 	return orders, err
 }
-func (r *orderRepository) GetByUserIDWithFilters(userID uint, filters OrderFilters) ([]*domain.Order, error) {
-	var orders []*domain.Order
-	query := r.db.Where("user_id = ?", userID)
-	if filters.Status != nil && *filters.Status != "" {
-		query = query.Where("status = ?", *filters.Status)
+func (r *orderRepository) GetByUserIDWithFilters(ctx context.Context, userID uint, filters OrderFilters) ([]*domain.Order, *pagination.Page, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Order{}).Where("user_id = ?", userID)
+	if len(filters.Statuses) > 0 {
+		query = query.Where("status IN ?", filters.Statuses)
+	}
+	if len(filters.DeliveryPreferences) > 0 {
+		query = query.Where("delivery_preference IN ?", filters.DeliveryPreferences)
+	}
+	if filters.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filters.CreatedAfter)
+	}
+	if filters.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filters.CreatedBefore)
 	}
-	if filters.DeliveryPreference != nil && *filters.DeliveryPreference != "" {
-		query = query.Where("delivery_preference = ?", *filters.DeliveryPreference)
+	if filters.PostalCodePrefix != "" {
+		query = query.Where("postal_code ILIKE ? ESCAPE '\\'", likePrefixPattern(filters.PostalCodePrefix))
 	}
-	sortBy := filters.SortBy
-	if sortBy == "" {
-		sortBy = "created_at"
+	if filters.Search != "" {
+		query = query.Where("search_vector @@ websearch_to_tsquery('english', ?)", filters.Search)
 	}
-	sortOrder := filters.SortOrder
-	if sortOrder == "" {
-		sortOrder = "DESC"
-	} else if sortOrder == "asc" {
-		sortOrder = "ASC"
-	} else {
-		sortOrder = "DESC"
+	sortFields := filters.Sort
+	if len(sortFields) == 0 {
+		sortFields = []SortField{{Field: "created_at", Desc: true}}
 	}
-	query = query.Order(sortBy + " " + sortOrder)
-	if filters.Limit > 0 {
-		query = query.Limit(filters.Limit)
+	keyset := pagination.Keyset{IDColumn: "id"}
+	values := make([]func(o *domain.Order) interface{}, 0, len(sortFields))
+	for _, f := range sortFields {
+		col, ok := orderSortColumns[f.Field]
+		if !ok {
+			return nil, nil, ErrUnsupportedSortField
+		}
+		keyset.Keys = append(keyset.Keys, pagination.SortKey{Column: col.column, Desc: f.Desc, Kind: col.kind})
+		values = append(values, col.value)
 	}
-	if filters.Offset > 0 {
-		query = query.Offset(filters.Offset)
+	params := pagination.Params{
+		Cursor:       filters.Cursor,
+		Limit:        filters.Limit,
+		IncludeTotal: filters.IncludeTotal,
 	}
-	err := query.Find(&orders).Error
+	return pagination.Fetch(query, keyset, params, func(o *domain.Order) ([]interface{}, uint) {
+		key := make([]interface{}, len(values))
+		for i, value := range values {
+			key[i] = value(o)
+		}
+		return key, o.ID
+	})
+}
+func (r *orderRepository) ListArrivedAtStore(ctx context.Context, storeID uint) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.db.WithContext(ctx).Preload("User").
+		Where("store_id = ? AND customer_arrived_at IS NOT NULL", storeID).
+		Where("status IN ?", []domain.OrderStatus{domain.OrderStatusPending, domain.OrderStatusProcessing}).
+		Order("customer_arrived_at ASC").
+		Find(&orders).Error
 	return orders, err
 }
-func (r *orderRepository) Update(order *domain.Order) error {
-	return r.db.Save(order).Error
+func (r *orderRepository) ListByStore(ctx context.Context, storeID uint, statuses []domain.OrderStatus, limit int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.db.WithContext(ctx).Preload("User").
+		Where("store_id = ? AND status IN ?", storeID, statuses).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+func (r *orderRepository) Update(ctx context.Context, order *domain.Order) error {
+	return r.db.WithContext(ctx).Save(order).Error
+}
+func (r *orderRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Order{}, id).Error
 }
-func (r *orderRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Order{}, id).Error
+func likePrefixPattern(prefix string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(prefix) + "%"
 }
@@ -0,0 +1,72 @@
+package repository
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+type PickupRepository interface {
+	GetConfig(ctx context.Context, storeID uint) (*domain.PickupConfig, error)
+	SaveConfig(ctx context.Context, config *domain.PickupConfig) error
+	ListSlots(ctx context.Context, storeID uint, from, to time.Time) ([]*domain.PickupSlot, error)
+	EnsureSlot(ctx context.Context, slot *domain.PickupSlot) (*domain.PickupSlot, error)
+	Reserve(ctx context.Context, slotID uint) (bool, error)
+	Release(ctx context.Context, slotID uint) error
+}
+type pickupRepository struct {
+	db *gorm.DB
+}
+func NewPickupRepository(db *gorm.DB) PickupRepository {
+	return &pickupRepository{db: db}
+}
+func (r *pickupRepository) GetConfig(ctx context.Context, storeID uint) (*domain.PickupConfig, error) {
+	var config domain.PickupConfig
+	err := r.db.WithContext(ctx).Where("store_id = ?", storeID).First(&config).Error
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+func (r *pickupRepository) SaveConfig(ctx context.Context, config *domain.PickupConfig) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "store_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hours", "slot_minutes", "capacity", "lead_time_minutes", "horizon_days", "updated_at"}),
+	}).Create(config).Error
+}
+func (r *pickupRepository) ListSlots(ctx context.Context, storeID uint, from, to time.Time) ([]*domain.PickupSlot, error) {
+	var slots []*domain.PickupSlot
+	err := r.db.WithContext(ctx).Where("store_id = ? AND starts_at >= ? AND starts_at < ?", storeID, from, to).
+		Order("starts_at ASC").
+		Find(&slots).Error
+	return slots, err
+}
+func (r *pickupRepository) EnsureSlot(ctx context.Context, slot *domain.PickupSlot) (*domain.PickupSlot, error) {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "store_id"}, {Name: "starts_at"}},
+		DoNothing: true,
+	}).Create(slot).Error
+	if err != nil {
+		return nil, err
+	}
+	var existing domain.PickupSlot
+	err = r.db.WithContext(ctx).Where("store_id = ? AND starts_at = ?", slot.StoreID, slot.StartsAt).First(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+func (r *pickupRepository) Reserve(ctx context.Context, slotID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PickupSlot{}).
+		Where("id = ? AND reserved < capacity", slotID).
+		UpdateColumn("reserved", gorm.Expr("reserved + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+func (r *pickupRepository) Release(ctx context.Context, slotID uint) error {
+	return r.db.WithContext(ctx).Model(&domain.PickupSlot{}).
+		Where("id = ? AND reserved > 0", slotID).
+		UpdateColumn("reserved", gorm.Expr("reserved - 1")).Error
+}
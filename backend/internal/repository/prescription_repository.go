@@ -0,0 +1,53 @@
+package repository
+import (
+	"context"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type PrescriptionRepository interface {
+	Create(ctx context.Context, prescription *domain.Prescription) error
+	GetByID(ctx context.Context, id uint) (*domain.Prescription, error)
+	ListByOrderID(ctx context.Context, orderID uint) ([]*domain.Prescription, error)
+	ListByStatus(ctx context.Context, status domain.PrescriptionStatus, storeID *uint, limit int) ([]*domain.Prescription, error)
+	Update(ctx context.Context, prescription *domain.Prescription) error
+}
+type prescriptionRepository struct {
+	db *gorm.DB
+}
+func NewPrescriptionRepository(db *gorm.DB) PrescriptionRepository {
+	return &prescriptionRepository{db: db}
+}
+func (r *prescriptionRepository) Create(ctx context.Context, prescription *domain.Prescription) error {
+	return r.db.WithContext(ctx).Create(prescription).Error
+}
+func (r *prescriptionRepository) GetByID(ctx context.Context, id uint) (*domain.Prescription, error) {
+	var prescription domain.Prescription
+	err := r.db.WithContext(ctx).Preload("ReviewedBy").First(&prescription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &prescription, nil
+}
+func (r *prescriptionRepository) ListByOrderID(ctx context.Context, orderID uint) ([]*domain.Prescription, error) {
+	var prescriptions []*domain.Prescription
+	err := r.db.WithContext(ctx).Preload("ReviewedBy").
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&prescriptions).Error
+	return prescriptions, err
+}
+func (r *prescriptionRepository) ListByStatus(ctx context.Context, status domain.PrescriptionStatus, storeID *uint, limit int) ([]*domain.Prescription, error) {
+	var prescriptions []*domain.Prescription
+	query := r.db.WithContext(ctx).Where("prescriptions.status = ?", status)
+	if storeID != nil {
+		query = query.Joins("JOIN orders ON orders.id = prescriptions.order_id").
+			Where("orders.store_id = ?", *storeID)
+	}
+	err := query.Order("prescriptions.created_at ASC").
+		Limit(limit).
+		Find(&prescriptions).Error
+	return prescriptions, err
+}
+func (r *prescriptionRepository) Update(ctx context.Context, prescription *domain.Prescription) error {
+	return r.db.WithContext(ctx).Omit("ReviewedBy").Save(prescription).Error
+}
@@ -0,0 +1,77 @@
+package repository
+import (
+	"context"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type PromptTemplateRepository interface {
+	List(ctx context.Context, name string) ([]domain.PromptTemplate, error)
+	GetVersion(ctx context.Context, name string, version int) (*domain.PromptTemplate, error)
+	GetActive(ctx context.Context, name string) (*domain.PromptTemplate, error)
+	Create(ctx context.Context, template *domain.PromptTemplate, minVersion int) error
+	Activate(ctx context.Context, name string, version int) (bool, error)
+	Deactivate(ctx context.Context, name string) error
+}
+type promptTemplateRepository struct {
+	db *gorm.DB
+}
+func NewPromptTemplateRepository(db *gorm.DB) PromptTemplateRepository {
+	return &promptTemplateRepository{db: db}
+}
+func (r *promptTemplateRepository) List(ctx context.Context, name string) ([]domain.PromptTemplate, error) {
+	var templates []domain.PromptTemplate
+	err := r.db.WithContext(ctx).Where("name = ?", name).Order("version ASC").Find(&templates).Error
+	return templates, err
+}
+func (r *promptTemplateRepository) GetVersion(ctx context.Context, name string, version int) (*domain.PromptTemplate, error) {
+	var templates []*domain.PromptTemplate
+	err := r.db.WithContext(ctx).Where("name = ? AND version = ?", name, version).Limit(1).Find(&templates).Error
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+	return templates[0], nil
+}
+func (r *promptTemplateRepository) GetActive(ctx context.Context, name string) (*domain.PromptTemplate, error) {
+	var templates []*domain.PromptTemplate
+	err := r.db.WithContext(ctx).Where("name = ? AND active", name).Order("version DESC").Limit(1).Find(&templates).Error
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+	return templates[0], nil
+}
+func (r *promptTemplateRepository) Create(ctx context.Context, template *domain.PromptTemplate, minVersion int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&domain.PromptTemplate{}).Where("name = ?", template.Name).
+			Select("coalesce(max(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		if latest < minVersion {
+			latest = minVersion
+		}
+		template.Version = latest + 1
+		if template.Active {
+			if err := tx.Model(&domain.PromptTemplate{}).Where("name = ? AND active", template.Name).
+				Update("active", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(template).Error
+	})
+}
+func (r *promptTemplateRepository) Activate(ctx context.Context, name string, version int) (bool, error) {
+	found := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PromptTemplate{}).Where("name = ? AND active AND version <> ?", name, version).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&domain.PromptTemplate{}).Where("name = ? AND version = ?", name, version).Update("active", true)
+		found = result.RowsAffected > 0
+		return result.Error
+	})
+	return found, err
+}
+func (r *promptTemplateRepository) Deactivate(ctx context.Context, name string) error {
+	return r.db.WithContext(ctx).Model(&domain.PromptTemplate{}).Where("name = ? AND active", name).Update("active", false).Error
+}
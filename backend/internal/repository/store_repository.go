@@ -0,0 +1,58 @@
+package repository
+import (
+	"context"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type StoreRepository interface {
+	Create(ctx context.Context, store *domain.Store) error
+	GetByID(ctx context.Context, id uint) (*domain.Store, error)
+	GetDefault(ctx context.Context) (*domain.Store, error)
+	List(ctx context.Context) ([]*domain.Store, error)
+	ListAll(ctx context.Context) ([]*domain.Store, error)
+	Update(ctx context.Context, store *domain.Store) error
+	ListStaff(ctx context.Context, storeID uint) ([]*domain.User, error)
+}
+type storeRepository struct {
+	db *gorm.DB
+}
+func NewStoreRepository(db *gorm.DB) StoreRepository {
+	return &storeRepository{db: db}
+}
+func (r *storeRepository) Create(ctx context.Context, store *domain.Store) error {
+	return r.db.WithContext(ctx).Create(store).Error
+}
+func (r *storeRepository) GetByID(ctx context.Context, id uint) (*domain.Store, error) {
+	var store domain.Store
+	err := r.db.WithContext(ctx).First(&store, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+func (r *storeRepository) GetDefault(ctx context.Context) (*domain.Store, error) {
+	var store domain.Store
+	err := r.db.WithContext(ctx).Where("active = ?", true).Order("id ASC").First(&store).Error
+	if err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+func (r *storeRepository) List(ctx context.Context) ([]*domain.Store, error) {
+	var stores []*domain.Store
+	err := r.db.WithContext(ctx).Where("active = ?", true).Order("name ASC").Find(&stores).Error
+	return stores, err
+}
+func (r *storeRepository) ListAll(ctx context.Context) ([]*domain.Store, error) {
+	var stores []*domain.Store
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&stores).Error
+	return stores, err
+}
+func (r *storeRepository) Update(ctx context.Context, store *domain.Store) error {
+	return r.db.WithContext(ctx).Save(store).Error
+}
+func (r *storeRepository) ListStaff(ctx context.Context, storeID uint) ([]*domain.User, error) {
+	var users []*domain.User
+	err := r.db.WithContext(ctx).Where("store_id = ?", storeID).Order("last_name ASC, first_name ASC").Find(&users).Error
+	return users, err
+}
@@ -1,16 +1,19 @@
 package repository
 import (
+	"context"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/pagination"
 	"gorm.io/gorm"
 )
 type UserRepository interface {
-	Create(user *domain.User) error
-	GetByID(id uint) (*domain.User, error)
-	GetByEmail(email string) (*domain.User, error)
-	Update(user *domain.User) error
-	Delete(id uint) error
-	List(limit, offset int) ([]*domain.User, error)
-	Count() (int64, error)
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id uint) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	Update(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, limit, offset int) ([]*domain.User, error)
+	ListPage(ctx context.Context, params pagination.Params) ([]*domain.User, *pagination.Page, error)
+	Count(ctx context.Context) (int64, error)
 }
 type userRepository struct {
 	db *gorm.DB
@@ -18,38 +21,44 @@ type userRepository struct {
 func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
-func (r *userRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
-func (r *userRepository) GetByID(id uint) (*domain.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	err := r.db.First(&user, id).Error
+	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
-func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
-func (r *userRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
 }
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.User{}, id).Error
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.User{}, id).Error
 }
-func (r *userRepository) List(limit, offset int) ([]*domain.User, error) {
+func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
 	var users []*domain.User
-	err := r.db.Limit(limit).Offset(offset).Find(&users).Error
+	err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&users).Error
 	return users, err
 }
-func (r *userRepository) Count() (int64, error) {
+func (r *userRepository) ListPage(ctx context.Context, params pagination.Params) ([]*domain.User, *pagination.Page, error) {
+	keyset := pagination.Keyset{IDColumn: "id"}
+	return pagination.Fetch(r.db.WithContext(ctx).Model(&domain.User{}), keyset, params, func(u *domain.User) ([]interface{}, uint) {
+		return nil, u.ID
+	})
+}
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&domain.User{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Count(&count).Error
 	return count, err
 }
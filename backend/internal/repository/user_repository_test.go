@@ -1,5 +1,6 @@
 package repository_test
 import (
+	"context"
 	"fmt"
 	"testing"
 	"weel-backend/internal/domain"
@@ -40,7 +41,7 @@ func (suite *UserRepositoryTestSuite) TestCreateUser() {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	err := suite.userRepo.Create(user)
+	err := suite.userRepo.Create(context.Background(), user)
 	assert.NoError(suite.T(), err)
 	assert.NotZero(suite.T(), user.ID)
 }
@@ -50,8 +51,8 @@ func (suite *UserRepositoryTestSuite) TestGetByID() {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	suite.userRepo.Create(user)
-	found, err := suite.userRepo.GetByID(user.ID)
+	suite.userRepo.Create(context.Background(), user)
+	found, err := suite.userRepo.GetByID(context.Background(), user.ID)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), user.Email, found.Email)
 }
@@ -61,8 +62,8 @@ func (suite *UserRepositoryTestSuite) TestGetByEmail() {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	suite.userRepo.Create(user)
-	found, err := suite.userRepo.GetByEmail("test@example.com")
+	suite.userRepo.Create(context.Background(), user)
+	found, err := suite.userRepo.GetByEmail(context.Background(), "test@example.com")
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), user.ID, found.ID)
 }
@@ -72,11 +73,11 @@ func (suite *UserRepositoryTestSuite) TestUpdateUser() {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	suite.userRepo.Create(user)
+	suite.userRepo.Create(context.Background(), user)
 	user.FirstName = "Updated"
-	err := suite.userRepo.Update(user)
+	err := suite.userRepo.Update(context.Background(), user)
 	assert.NoError(suite.T(), err)
-	updated, _ := suite.userRepo.GetByID(user.ID)
+	updated, _ := suite.userRepo.GetByID(context.Background(), user.ID)
 	assert.Equal(suite.T(), "Updated", updated.FirstName)
 }
 func (suite *UserRepositoryTestSuite) TestDeleteUser() {
@@ -85,10 +86,10 @@ func (suite *UserRepositoryTestSuite) TestDeleteUser() {
 		FirstName: "Test",
 		LastName:  "User",
 	}
-	suite.userRepo.Create(user)
-	err := suite.userRepo.Delete(user.ID)
+	suite.userRepo.Create(context.Background(), user)
+	err := suite.userRepo.Delete(context.Background(), user.ID)
 	assert.NoError(suite.T(), err)
-	_, err = suite.userRepo.GetByID(user.ID)
+	_, err = suite.userRepo.GetByID(context.Background(), user.ID)
 	assert.Error(suite.T(), err)
 }
 func (suite *UserRepositoryTestSuite) TestListUsers() {
@@ -98,9 +99,9 @@ func (suite *UserRepositoryTestSuite) TestListUsers() {
 			FirstName: "Test",
 			LastName:  "User",
 		}
-		suite.userRepo.Create(user)
+		suite.userRepo.Create(context.Background(), user)
 	}
-	users, err := suite.userRepo.List(10, 0)
+	users, err := suite.userRepo.List(context.Background(), 10, 0)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), users, 5)
 }
@@ -0,0 +1,106 @@
+package repository
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetSubscriptionByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error)
+	ListSubscriptionsByUserID(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error)
+	ListActiveSubscriptionsByUserID(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id uint) error
+	CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id uint) (*domain.WebhookDelivery, error)
+	ListDeliveriesBySubscriptionID(ctx context.Context, subscriptionID uint, limit int) ([]*domain.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error)
+	RequeueDelivery(ctx context.Context, id uint, now time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	CreateAttempt(ctx context.Context, attempt *domain.WebhookDeliveryAttempt) error
+}
+type webhookRepository struct {
+	db *gorm.DB
+}
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+func (r *webhookRepository) GetSubscriptionByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	err := r.db.WithContext(ctx).First(&sub, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+func (r *webhookRepository) ListSubscriptionsByUserID(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error) {
+	var subs []*domain.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+func (r *webhookRepository) ListActiveSubscriptionsByUserID(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error) {
+	var subs []*domain.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ? AND active = ?", userID, true).Find(&subs).Error
+	return subs, err
+}
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Save(sub).Error
+}
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.WebhookSubscription{}, id).Error
+}
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := r.db.WithContext(ctx).Preload("Attempts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("attempt ASC")
+	}).First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+func (r *webhookRepository) ListDeliveriesBySubscriptionID(ctx context.Context, subscriptionID uint, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	err := r.db.WithContext(ctx).Raw(`UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), domain.WebhookDeliveryStatusPending, now, limit,
+	).Scan(&deliveries).Error
+	return deliveries, err
+}
+func (r *webhookRepository) RequeueDelivery(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Where("id = ? AND (status <> ? OR next_attempt_at IS NULL OR next_attempt_at <= ?)", id, domain.WebhookDeliveryStatusPending, now).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookDeliveryStatusPending,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Omit("Attempts").Save(delivery).Error
+}
+func (r *webhookRepository) CreateAttempt(ctx context.Context, attempt *domain.WebhookDeliveryAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
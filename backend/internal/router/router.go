@@ -1,7 +1,9 @@
 package router
 import (
-	"net/http"
+	"weel-backend/internal/health"
+	"weel-backend/internal/metrics"
 	"weel-backend/internal/middleware"
+	"weel-backend/internal/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 type RouteHandler interface {
@@ -10,9 +12,12 @@ type RouteHandler interface {
 type Router struct {
 	engine         *gin.Engine
 	authMiddleware gin.HandlerFunc
+	limiter        *ratelimit.Limiter
 }
 func NewRouter() *Router {
-	engine := gin.Default()
+	engine := gin.New()
+	engine.Use(middleware.RequestIDMiddleware(), middleware.TracingMiddleware(), middleware.RequestLogger(), gin.Recovery())
+	engine.Use(middleware.MetricsMiddleware())
 	engine.Use(middleware.CORSMiddleware())
 	return &Router{
 		engine: engine,
@@ -21,24 +26,30 @@ func NewRouter() *Router {
 func (r *Router) SetAuthMiddleware(middleware gin.HandlerFunc) {
 	r.authMiddleware = middleware
 }
+func (r *Router) SetRateLimiter(limiter *ratelimit.Limiter) {
+	r.limiter = limiter
+}
+func (r *Router) RateLimit(policy string) gin.HandlerFunc {
+	return middleware.RateLimit(r.limiter, policy)
+}
 func (r *Router) RegisterRoutes(handlers ...RouteHandler) {
-	r.engine.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
-	v1 := r.engine.Group("/api/v1")
+	v1 := r.PublicRoutes()
 	{
 		for _, handler := range handlers {
 			handler.RegisterRoutes(v1)
 		}
 	}
 }
+func (r *Router) PublicRoutes() *gin.RouterGroup {
+	return r.engine.Group("/api/v1", r.RateLimit(ratelimit.PolicyPublic))
+}
 func (r *Router) RegisterProtectedRoutes(middleware gin.HandlerFunc, handlers ...RouteHandler) {
 	if middleware == nil {
 		return
 	}
 	v1 := r.engine.Group("/api/v1")
 	protected := v1.Group("")
-	protected.Use(middleware)
+	protected.Use(middleware, r.RateLimit(ratelimit.PolicyAPI))
 	{
 		for _, handler := range handlers {
 			handler.RegisterRoutes(protected)
@@ -51,3 +62,11 @@ func (r *Router) GetEngine() *gin.Engine {
 func (r *Router) GetRouter() *Router {
 	return r
 }
+func (r *Router) RegisterMetricsRoute(token string) {
+	r.engine.GET("/metrics", middleware.MetricsAuth(token), gin.WrapH(metrics.Handler()))
+}
+func (r *Router) RegisterHealthRoutes(registry *health.Registry) {
+	r.engine.GET("/health", health.Liveness)
+	r.engine.GET("/livez", health.Liveness)
+	r.engine.GET("/readyz", registry.Readiness)
+}
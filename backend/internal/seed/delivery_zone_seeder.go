@@ -0,0 +1,46 @@
+package seed
+import (
+	"log/slog"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type DeliveryZoneSeeder struct{}
+func NewDeliveryZoneSeeder() Seeder {
+	return &DeliveryZoneSeeder{}
+}
+func (s *DeliveryZoneSeeder) Name() string {
+	return "DeliveryZoneSeeder"
+}
+func (s *DeliveryZoneSeeder) Seed(db *gorm.DB) error {
+	var count int64
+	db.Model(&domain.DeliveryZone{}).Count(&count)
+	if count > 0 {
+		slog.Info("delivery zones already exist, skipping seed")
+		return nil
+	}
+	zones := []*domain.DeliveryZone{
+		{
+			Name:           "Local",
+			Country:        "US",
+			PostalPrefixes: domain.StringList{"0", "1", "2", "3", "4"},
+			Fee:            4.99,
+			MinOrderAmount: 15,
+			Active:         true,
+		},
+		{
+			Name:           "Extended",
+			Country:        "US",
+			PostalPrefixes: domain.StringList{"5", "6", "7"},
+			Fee:            9.99,
+			MinOrderAmount: 35,
+			Active:         true,
+		},
+	}
+	for _, zone := range zones {
+		if err := db.Create(zone).Error; err != nil {
+			return err
+		}
+		slog.Info("created delivery zone", "name", zone.Name, "fee", zone.Fee)
+	}
+	return nil
+}
@@ -1,6 +1,6 @@
 package seed
 import (
-	"log"
+	"log/slog"
 	"weel-backend/internal/domain"
 	"gorm.io/gorm"
 )
@@ -15,7 +15,7 @@ func (s *FeatureFlagSeeder) Seed(db *gorm.DB) error {
 	var count int64
 	db.Model(&domain.FeatureFlag{}).Count(&count)
 	if count > 0 {
-		log.Println("Feature flags already exist, skipping seed")
+		slog.Info("feature flags already exist, skipping seed")
 		return nil
 	}
 	flags := []*domain.FeatureFlag{
@@ -44,7 +44,7 @@ func (s *FeatureFlagSeeder) Seed(db *gorm.DB) error {
 		if err := db.Create(flag).Error; err != nil {
 			return err
 		}
-		log.Printf("✅ Created feature flag: %s (enabled: %v)", flag.Name, flag.Enabled)
+		slog.Info("created feature flag", "name", flag.Name, "enabled", flag.Enabled)
 	}
 	return nil
 }
@@ -1,7 +1,7 @@
 package seed
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"weel-backend/internal/domain"
 	"github.com/brianvoe/gofakeit/v6"
 	"gorm.io/gorm"
@@ -17,7 +17,7 @@ func (s *OrderSeeder) Seed(db *gorm.DB) error {
 	var count int64
 	db.Model(&domain.Order{}).Count(&count)
 	if count > 0 {
-		log.Println("Orders already exist, skipping seed")
+		slog.Info("orders already exist, skipping seed")
 		return nil
 	}
 	var users []domain.User
@@ -25,9 +25,14 @@ func (s *OrderSeeder) Seed(db *gorm.DB) error {
 		return err
 	}
 	if len(users) == 0 {
-		log.Println("No users found, skipping order seed")
+		slog.Info("no users found, skipping order seed")
 		return nil
 	}
+	var storeID *uint
+	var store domain.Store
+	if err := db.Where("active = ?", true).Order("id ASC").First(&store).Error; err == nil {
+		storeID = &store.ID
+	}
 	ordersCreated := 0
 	for _, user := range users {
 		numOrders := gofakeit.IntRange(1, 3)
@@ -73,6 +78,7 @@ func (s *OrderSeeder) Seed(db *gorm.DB) error {
 				domain.OrderStatusCancelled,
 			}
 			var aiProducts *string
+			total := 0.0
 			if gofakeit.Bool() {
 				numProducts := gofakeit.IntRange(2, 5)
 				products := make([]domain.AISuggestedProduct, numProducts)
@@ -84,6 +90,7 @@ func (s *OrderSeeder) Seed(db *gorm.DB) error {
 						Reason:   gofakeit.Sentence(gofakeit.IntRange(5, 15)),
 					}
 				}
+				total = domain.ProductsTotal(products)
 				productsJSON, _ := json.Marshal(products)
 				jsonStr := string(productsJSON)
 				aiProducts = &jsonStr
@@ -95,7 +102,9 @@ func (s *OrderSeeder) Seed(db *gorm.DB) error {
 				DeliveryAddress:     deliveryAddress,
 				PostalCode:          postalCode,
 				AISuggestedProducts: aiProducts,
+				Total:               total,
 				Status:              statuses[gofakeit.IntRange(0, len(statuses)-1)],
+				StoreID:             storeID,
 			}
 			if err := db.Create(order).Error; err != nil {
 				return err
@@ -103,6 +112,6 @@ func (s *OrderSeeder) Seed(db *gorm.DB) error {
 			ordersCreated++
 		}
 	}
-	log.Printf("✅ Created %d orders", ordersCreated)
+	slog.Info("created orders", "count", ordersCreated)
 	return nil
 }
@@ -1,22 +1,54 @@
 package seed
 import (
-	"log"
+	"log/slog"
 	"gorm.io/gorm"
 )
 func Reset(db *gorm.DB) error {
+	if err := db.Exec("DELETE FROM webhook_delivery_attempts").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM webhook_deliveries").Error; err != nil {
+		return err
+	}
+	slog.Info("deleted all webhook deliveries")
+	if err := db.Exec("DELETE FROM prescriptions").Error; err != nil {
+		return err
+	}
+	slog.Info("deleted all prescriptions")
 	if err := db.Exec("DELETE FROM orders").Error; err != nil {
 		return err
 	}
-	log.Println("✅ Deleted all orders")
+	slog.Info("deleted all orders")
+	if err := db.Exec("DELETE FROM pickup_slots").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM pickup_configs").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM inventory_items").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("DELETE FROM stores").Error; err != nil {
+		return err
+	}
+	slog.Info("deleted all stores and pickup slots")
+	if err := db.Exec("DELETE FROM addresses").Error; err != nil {
+		return err
+	}
+	slog.Info("deleted all addresses")
+	if err := db.Exec("DELETE FROM delivery_zones").Error; err != nil {
+		return err
+	}
+	slog.Info("deleted all delivery zones")
 	if err := db.Exec("DELETE FROM users").Error; err != nil {
 		return err
 	}
-	log.Println("✅ Deleted all users")
+	slog.Info("deleted all users")
 	if err := db.Exec("ALTER SEQUENCE users_id_seq RESTART WITH 1").Error; err != nil {
-		log.Printf("Warning: Failed to reset users sequence: %v", err)
+		slog.Warn("failed to reset users sequence", "error", err)
 	}
 	if err := db.Exec("ALTER SEQUENCE orders_id_seq RESTART WITH 1").Error; err != nil {
-		log.Printf("Warning: Failed to reset orders sequence: %v", err)
+		slog.Warn("failed to reset orders sequence", "error", err)
 	}
 	return nil
 }
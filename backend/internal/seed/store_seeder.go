@@ -0,0 +1,86 @@
+package seed
+import (
+	"log/slog"
+	"time"
+	"weel-backend/internal/domain"
+	"gorm.io/gorm"
+)
+type StoreSeeder struct{}
+func NewStoreSeeder() Seeder {
+	return &StoreSeeder{}
+}
+func (s *StoreSeeder) Name() string {
+	return "StoreSeeder"
+}
+func (s *StoreSeeder) Seed(db *gorm.DB) error {
+	var count int64
+	db.Model(&domain.Store{}).Count(&count)
+	if count > 0 {
+		slog.Info("stores already exist, skipping seed")
+		return nil
+	}
+	var hours domain.OpeningHours
+	for day := time.Monday; day <= time.Friday; day++ {
+		hours = append(hours, domain.DailyHours{Weekday: day, Open: "09:00", Close: "19:00"})
+	}
+	hours = append(hours, domain.DailyHours{Weekday: time.Saturday, Open: "10:00", Close: "16:00"})
+	store := &domain.Store{
+		Name:  "Weel Midtown",
+		Phone: "+1 212 555 0100",
+		Address: &domain.AddressSnapshot{PostalAddress: domain.PostalAddress{
+			Line1:      "350 5th Ave",
+			City:       "New York",
+			Region:     "NY",
+			PostalCode: "10118",
+			Country:    "US",
+		}},
+		Timezone:            "America/New_York",
+		Hours:               hours,
+		DeliveryPreferences: domain.StringList{string(domain.DeliveryPreferenceInStore), string(domain.DeliveryPreferenceDelivery), string(domain.DeliveryPreferenceCurbside)},
+		Active:              true,
+	}
+	if err := db.Create(store).Error; err != nil {
+		return err
+	}
+	branch := &domain.Store{
+		Name:  "Weel Brooklyn Heights",
+		Phone: "+1 718 555 0142",
+		Address: &domain.AddressSnapshot{PostalAddress: domain.PostalAddress{
+			Line1:      "142 Montague St",
+			City:       "Brooklyn",
+			Region:     "NY",
+			PostalCode: "11201",
+			Country:    "US",
+		}},
+		Timezone:            "America/New_York",
+		Hours:               hours,
+		DeliveryPreferences: domain.StringList{string(domain.DeliveryPreferenceInStore), string(domain.DeliveryPreferenceDelivery)},
+		Active:              true,
+	}
+	if err := db.Create(branch).Error; err != nil {
+		return err
+	}
+	slog.Info("created store", "name", branch.Name)
+	inventory := []*domain.InventoryItem{
+		{StoreID: store.ID, SKU: "IBU-200-24", Name: "Ibuprofen 200mg (24 tablets)", Quantity: 40, Price: 6.49},
+		{StoreID: store.ID, SKU: "LOR-10-30", Name: "Loratadine 10mg (30 tablets)", Quantity: 25, Price: 12.99},
+		{StoreID: branch.ID, SKU: "IBU-200-24", Name: "Ibuprofen 200mg (24 tablets)", Quantity: 12, Price: 6.49},
+		{StoreID: branch.ID, SKU: "VITD-1000-90", Name: "Vitamin D3 1000 IU (90 softgels)", Quantity: 30, Price: 9.99},
+	}
+	if err := db.Create(&inventory).Error; err != nil {
+		return err
+	}
+	config := &domain.PickupConfig{
+		StoreID:         store.ID,
+		Hours:           hours,
+		SlotMinutes:     30,
+		Capacity:        4,
+		LeadTimeMinutes: 60,
+		HorizonDays:     7,
+	}
+	if err := db.Create(config).Error; err != nil {
+		return err
+	}
+	slog.Info("created store", "name", store.Name, "pickup_slot_minutes", config.SlotMinutes)
+	return nil
+}
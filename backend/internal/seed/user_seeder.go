@@ -1,6 +1,6 @@
 package seed
 import (
-	"log"
+	"log/slog"
 	"weel-backend/internal/domain"
 	"weel-backend/internal/service"
 	"github.com/brianvoe/gofakeit/v6"
@@ -17,7 +17,7 @@ func (s *UserSeeder) Seed(db *gorm.DB) error {
 	var count int64
 	db.Model(&domain.User{}).Count(&count)
 	if count > 0 {
-		log.Println("Users already exist, skipping seed")
+		slog.Info("users already exist, skipping seed")
 		return nil
 	}
 	adminPassword, _ := service.HashPassword("password123")
@@ -26,22 +26,40 @@ func (s *UserSeeder) Seed(db *gorm.DB) error {
 		Password:  adminPassword,
 		FirstName: "Admin",
 		LastName:  "User",
+		Role:      domain.UserRoleAdmin,
 	}
 	if err := db.Create(admin).Error; err != nil {
 		return err
 	}
-	log.Printf("✅ Created admin user: %s", admin.Email)
+	slog.Info("created admin user", "email", admin.Email)
+	pharmacistPassword, _ := service.HashPassword("password123")
+	pharmacist := &domain.User{
+		Email:     "pharmacist@example.com",
+		Password:  pharmacistPassword,
+		FirstName: "Pharmacy",
+		LastName:  "Staff",
+		Role:      domain.UserRolePharmacist,
+	}
+	var store domain.Store
+	if err := db.Order("id ASC").First(&store).Error; err == nil {
+		pharmacist.StoreID = &store.ID
+	}
+	if err := db.Create(pharmacist).Error; err != nil {
+		return err
+	}
+	slog.Info("created pharmacist user", "email", pharmacist.Email)
 	testPassword, _ := service.HashPassword("password123")
 	testUser := &domain.User{
 		Email:     "user@example.com",
 		Password:  testPassword,
 		FirstName: "Test",
 		LastName:  "User",
+		Role:      domain.UserRoleCustomer,
 	}
 	if err := db.Create(testUser).Error; err != nil {
 		return err
 	}
-	log.Printf("✅ Created test user: %s", testUser.Email)
+	slog.Info("created test user", "email", testUser.Email)
 	fakeUsers := make([]*domain.User, 10)
 	for i := 0; i < 10; i++ {
 		password, _ := service.HashPassword("password123")
@@ -50,11 +68,12 @@ func (s *UserSeeder) Seed(db *gorm.DB) error {
 			Password:  password,
 			FirstName: gofakeit.FirstName(),
 			LastName:  gofakeit.LastName(),
+			Role:      domain.UserRoleCustomer,
 		}
 	}
 	if err := db.CreateInBatches(fakeUsers, 10).Error; err != nil {
 		return err
 	}
-	log.Printf("✅ Created %d fake users", len(fakeUsers))
+	slog.Info("created fake users", "count", len(fakeUsers))
 	return nil
 }
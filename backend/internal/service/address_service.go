@@ -0,0 +1,138 @@
+package service
+import (
+	"context"
+	"strings"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/postal"
+	"weel-backend/internal/repository"
+)
+type AddressInput struct {
+	Line1      string   `json:"line1" binding:"required,max=255"`
+	Line2      string   `json:"line2" binding:"max=255"`
+	City       string   `json:"city" binding:"required,max=100"`
+	Region     string   `json:"region" binding:"max=100"`
+	PostalCode string   `json:"postal_code" binding:"required,max=20"`
+	Country    string   `json:"country" binding:"required,len=2"`
+	Latitude   *float64 `json:"latitude,omitempty" binding:"omitempty,min=-90,max=90"`
+	Longitude  *float64 `json:"longitude,omitempty" binding:"omitempty,min=-180,max=180"`
+}
+type AddressRequest struct {
+	Label string `json:"label" binding:"max=50"`
+	AddressInput
+	IsDefault bool `json:"is_default"`
+}
+type AddressService interface {
+	ListAddresses(ctx context.Context, userID uint) ([]*domain.Address, error)
+	GetAddress(ctx context.Context, userID, addressID uint) (*domain.Address, error)
+	CreateAddress(ctx context.Context, userID uint, req *AddressRequest) (*domain.Address, error)
+	UpdateAddress(ctx context.Context, userID, addressID uint, req *AddressRequest) (*domain.Address, error)
+	DeleteAddress(ctx context.Context, userID, addressID uint) error
+	SetDefaultAddress(ctx context.Context, userID, addressID uint) (*domain.Address, error)
+}
+type addressService struct {
+	addressRepo repository.AddressRepository
+}
+func NewAddressService(addressRepo repository.AddressRepository) AddressService {
+	return &addressService{addressRepo: addressRepo}
+}
+func (s *addressService) ListAddresses(ctx context.Context, userID uint) ([]*domain.Address, error) {
+	return s.addressRepo.ListByUserID(ctx, userID)
+}
+func (s *addressService) GetAddress(ctx context.Context, userID, addressID uint) (*domain.Address, error) {
+	address, err := s.addressRepo.GetByID(ctx, addressID)
+	if err != nil || address.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+func (s *addressService) CreateAddress(ctx context.Context, userID uint, req *AddressRequest) (*domain.Address, error) {
+	normalized, err := req.AddressInput.normalize()
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.addressRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	address := &domain.Address{
+		UserID:        userID,
+		Label:         strings.TrimSpace(req.Label),
+		PostalAddress: *normalized,
+		IsDefault:     req.IsDefault || count == 0,
+	}
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+	if address.IsDefault && count > 0 {
+		if err := s.addressRepo.SetDefault(ctx, userID, address.ID); err != nil {
+			return nil, err
+		}
+	}
+	return address, nil
+}
+func (s *addressService) UpdateAddress(ctx context.Context, userID, addressID uint, req *AddressRequest) (*domain.Address, error) {
+	address, err := s.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := req.AddressInput.normalize()
+	if err != nil {
+		return nil, err
+	}
+	address.Label = strings.TrimSpace(req.Label)
+	address.PostalAddress = *normalized
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		return nil, err
+	}
+	if req.IsDefault && !address.IsDefault {
+		if err := s.addressRepo.SetDefault(ctx, userID, address.ID); err != nil {
+			return nil, err
+		}
+		address.IsDefault = true
+	}
+	return address, nil
+}
+func (s *addressService) DeleteAddress(ctx context.Context, userID, addressID uint) error {
+	if _, err := s.GetAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+	return s.addressRepo.Delete(ctx, addressID)
+}
+func (s *addressService) SetDefaultAddress(ctx context.Context, userID, addressID uint) (*domain.Address, error) {
+	address, err := s.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addressRepo.SetDefault(ctx, userID, addressID); err != nil {
+		return nil, err
+	}
+	address.IsDefault = true
+	return address, nil
+}
+func (in *AddressInput) normalize() (*domain.PostalAddress, error) {
+	address := &domain.PostalAddress{
+		Line1:  strings.TrimSpace(in.Line1),
+		Line2:  strings.TrimSpace(in.Line2),
+		City:   strings.TrimSpace(in.City),
+		Region: strings.TrimSpace(in.Region),
+	}
+	if address.Line1 == "" || address.City == "" || strings.TrimSpace(in.PostalCode) == "" {
+		return nil, ErrInvalidAddress
+	}
+	if (in.Latitude == nil) != (in.Longitude == nil) {
+		return nil, ErrInvalidAddress
+	}
+	address.Latitude = in.Latitude
+	address.Longitude = in.Longitude
+	country, err := postal.NormalizeCountry(in.Country)
+	if err != nil {
+		return nil, ErrInvalidCountry
+	}
+	code, err := postal.Normalize(country, in.PostalCode)
+	if err != nil {
+		return nil, ErrInvalidPostalCode
+	}
+	address.Country = country
+	address.PostalCode = code
+	return address, nil
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAddressRepository struct {
+	mock.Mock
+}
+
+func (m *MockAddressRepository) Create(ctx context.Context, address *domain.Address) error {
+	args := m.Called(address)
+	return args.Error(0)
+}
+func (m *MockAddressRepository) GetByID(ctx context.Context, id uint) (*domain.Address, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Address), args.Error(1)
+}
+func (m *MockAddressRepository) ListByUserID(ctx context.Context, userID uint) ([]*domain.Address, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*domain.Address), args.Error(1)
+}
+func (m *MockAddressRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockAddressRepository) Update(ctx context.Context, address *domain.Address) error {
+	args := m.Called(address)
+	return args.Error(0)
+}
+func (m *MockAddressRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockAddressRepository) SetDefault(ctx context.Context, userID, addressID uint) error {
+	args := m.Called(userID, addressID)
+	return args.Error(0)
+}
+
+type AddressServiceTestSuite struct {
+	suite.Suite
+	addressService service.AddressService
+	mockRepo       *MockAddressRepository
+}
+
+func (suite *AddressServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockAddressRepository)
+	suite.addressService = service.NewAddressService(suite.mockRepo)
+}
+func validAddressRequest() *service.AddressRequest {
+	return &service.AddressRequest{
+		Label: "Home",
+		AddressInput: service.AddressInput{
+			Line1:      "1 Yonge St",
+			City:       "Toronto",
+			Region:     "ON",
+			PostalCode: "m5e1w7",
+			Country:    "ca",
+		},
+	}
+}
+func (suite *AddressServiceTestSuite) TestCreateAddress_NormalizesAndDefaultsFirst() {
+	suite.mockRepo.On("CountByUserID", uint(1)).Return(int64(0), nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Address")).Return(nil)
+	address, err := suite.addressService.CreateAddress(context.Background(), 1, validAddressRequest())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "M5E 1W7", address.PostalCode)
+	assert.Equal(suite.T(), "CA", address.Country)
+	assert.True(suite.T(), address.IsDefault)
+	suite.mockRepo.AssertNotCalled(suite.T(), "SetDefault", mock.Anything, mock.Anything)
+}
+func (suite *AddressServiceTestSuite) TestCreateAddress_DefaultClearsOthers() {
+	req := validAddressRequest()
+	req.IsDefault = true
+	suite.mockRepo.On("CountByUserID", uint(1)).Return(int64(2), nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Address")).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.Address).ID = 9
+	}).Return(nil)
+	suite.mockRepo.On("SetDefault", uint(1), uint(9)).Return(nil)
+	address, err := suite.addressService.CreateAddress(context.Background(), 1, req)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), address.IsDefault)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *AddressServiceTestSuite) TestCreateAddress_InvalidPostalCode() {
+	req := validAddressRequest()
+	req.PostalCode = "12345"
+	address, err := suite.addressService.CreateAddress(context.Background(), 1, req)
+	assert.Nil(suite.T(), address)
+	assert.Equal(suite.T(), service.ErrInvalidPostalCode, err)
+}
+func (suite *AddressServiceTestSuite) TestCreateAddress_InvalidCountry() {
+	req := validAddressRequest()
+	req.Country = "C1"
+	address, err := suite.addressService.CreateAddress(context.Background(), 1, req)
+	assert.Nil(suite.T(), address)
+	assert.Equal(suite.T(), service.ErrInvalidCountry, err)
+}
+func (suite *AddressServiceTestSuite) TestGetAddress_OtherUser() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.Address{ID: 3, UserID: 2}, nil)
+	address, err := suite.addressService.GetAddress(context.Background(), 1, 3)
+	assert.Nil(suite.T(), address)
+	assert.Equal(suite.T(), service.ErrAddressNotFound, err)
+}
+func (suite *AddressServiceTestSuite) TestDeleteAddress_NotFound() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(nil, errors.New("record not found"))
+	err := suite.addressService.DeleteAddress(context.Background(), 1, 3)
+	assert.Equal(suite.T(), service.ErrAddressNotFound, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+func TestAddressServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AddressServiceTestSuite))
+}
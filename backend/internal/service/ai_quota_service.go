@@ -0,0 +1,117 @@
+package service
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+)
+const DefaultAISuggestionsDailyLimit = 20
+type AIQuotaRequest struct {
+	DailyLimit *int `json:"daily_limit" binding:"required,min=0,max=10000"`
+}
+type AIQuotaService interface {
+	Consume(ctx context.Context, userID uint) (*domain.AIQuotaStatus, error)
+	Refund(ctx context.Context, userID uint) error
+	GetStatus(ctx context.Context, userID uint) (*domain.AIQuotaStatus, error)
+	SetLimit(ctx context.Context, userID uint, req *AIQuotaRequest) (*domain.AIQuotaStatus, error)
+	ResetLimit(ctx context.Context, userID uint) (*domain.AIQuotaStatus, error)
+}
+type aiQuotaService struct {
+	quotaRepo    repository.AIQuotaRepository
+	userRepo     repository.UserRepository
+	defaultLimit int
+	now          func() time.Time
+}
+func NewAIQuotaService(quotaRepo repository.AIQuotaRepository, userRepo repository.UserRepository, defaultLimit int) AIQuotaService {
+	if defaultLimit < 0 {
+		defaultLimit = DefaultAISuggestionsDailyLimit
+	}
+	return &aiQuotaService{
+		quotaRepo:    quotaRepo,
+		userRepo:     userRepo,
+		defaultLimit: defaultLimit,
+		now:          time.Now,
+	}
+}
+func (s *aiQuotaService) Consume(ctx context.Context, userID uint) (*domain.AIQuotaStatus, error) {
+	limit, custom, err := s.limit(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	day := s.today()
+	used, ok := limit, false
+	if limit > 0 {
+		used, ok, err = s.quotaRepo.IncrementUsage(ctx, userID, day, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	status := s.status(limit, used, custom, day)
+	if !ok {
+		return status, ErrAIQuotaExceeded
+	}
+	return status, nil
+}
+func (s *aiQuotaService) Refund(ctx context.Context, userID uint) error {
+	return s.quotaRepo.DecrementUsage(ctx, userID, s.today())
+}
+func (s *aiQuotaService) GetStatus(ctx context.Context, userID uint) (*domain.AIQuotaStatus, error) {
+	limit, custom, err := s.limit(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	day := s.today()
+	used, err := s.quotaRepo.GetUsage(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(limit, used, custom, day), nil
+}
+func (s *aiQuotaService) SetLimit(ctx context.Context, userID uint, req *AIQuotaRequest) (*domain.AIQuotaStatus, error) {
+	if req.DailyLimit == nil || *req.DailyLimit < 0 {
+		return nil, ErrInvalidInput
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := s.quotaRepo.SaveLimit(ctx, &domain.AISuggestionQuota{UserID: userID, DailyLimit: *req.DailyLimit}); err != nil {
+		return nil, err
+	}
+	return s.GetStatus(ctx, userID)
+}
+func (s *aiQuotaService) ResetLimit(ctx context.Context, userID uint) (*domain.AIQuotaStatus, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := s.quotaRepo.DeleteLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.GetStatus(ctx, userID)
+}
+func (s *aiQuotaService) limit(ctx context.Context, userID uint) (int, bool, error) {
+	quota, err := s.quotaRepo.GetLimit(ctx, userID)
+	if err != nil {
+		return 0, false, err
+	}
+	if quota == nil {
+		return s.defaultLimit, false, nil
+	}
+	return quota.DailyLimit, true, nil
+}
+func (s *aiQuotaService) today() time.Time {
+	now := s.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+func (s *aiQuotaService) status(limit, used int, custom bool, day time.Time) *domain.AIQuotaStatus {
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &domain.AIQuotaStatus{
+		Limit:     limit,
+		Used:      used,
+		Remaining: remaining,
+		Custom:    custom,
+		ResetsAt:  day.AddDate(0, 0, 1),
+	}
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAIQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *MockAIQuotaRepository) GetLimit(ctx context.Context, userID uint) (*domain.AISuggestionQuota, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AISuggestionQuota), args.Error(1)
+}
+func (m *MockAIQuotaRepository) SaveLimit(ctx context.Context, quota *domain.AISuggestionQuota) error {
+	args := m.Called(quota)
+	return args.Error(0)
+}
+func (m *MockAIQuotaRepository) DeleteLimit(ctx context.Context, userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+func (m *MockAIQuotaRepository) GetUsage(ctx context.Context, userID uint, day time.Time) (int, error) {
+	args := m.Called(userID, day)
+	return args.Int(0), args.Error(1)
+}
+func (m *MockAIQuotaRepository) IncrementUsage(ctx context.Context, userID uint, day time.Time, limit int) (int, bool, error) {
+	args := m.Called(userID, day, limit)
+	return args.Int(0), args.Bool(1), args.Error(2)
+}
+func (m *MockAIQuotaRepository) DecrementUsage(ctx context.Context, userID uint, day time.Time) error {
+	args := m.Called(userID, day)
+	return args.Error(0)
+}
+
+type AIQuotaServiceTestSuite struct {
+	suite.Suite
+	quotaService service.AIQuotaService
+	mockRepo     *MockAIQuotaRepository
+	mockUserRepo *MockUserRepository
+}
+
+func (suite *AIQuotaServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockAIQuotaRepository)
+	suite.mockUserRepo = new(MockUserRepository)
+	suite.quotaService = service.NewAIQuotaService(suite.mockRepo, suite.mockUserRepo, 5)
+}
+func (suite *AIQuotaServiceTestSuite) TestConsume_UsesDefaultLimit() {
+	suite.mockRepo.On("GetLimit", uint(1)).Return(nil, nil)
+	suite.mockRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 5).Return(2, true, nil)
+	status, err := suite.quotaService.Consume(context.Background(), 1)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 5, status.Limit)
+	assert.Equal(suite.T(), 2, status.Used)
+	assert.Equal(suite.T(), 3, status.Remaining)
+	assert.False(suite.T(), status.Custom)
+	assert.True(suite.T(), status.ResetsAt.After(time.Now()))
+	assert.Equal(suite.T(), 0, status.ResetsAt.Hour())
+}
+func (suite *AIQuotaServiceTestSuite) TestConsume_UsesUserOverride() {
+	suite.mockRepo.On("GetLimit", uint(1)).Return(&domain.AISuggestionQuota{UserID: 1, DailyLimit: 50}, nil)
+	suite.mockRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 50).Return(1, true, nil)
+	status, err := suite.quotaService.Consume(context.Background(), 1)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 50, status.Limit)
+	assert.Equal(suite.T(), 49, status.Remaining)
+	assert.True(suite.T(), status.Custom)
+}
+func (suite *AIQuotaServiceTestSuite) TestConsume_Exceeded() {
+	suite.mockRepo.On("GetLimit", uint(1)).Return(nil, nil)
+	suite.mockRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 5).Return(5, false, nil)
+	status, err := suite.quotaService.Consume(context.Background(), 1)
+	assert.Equal(suite.T(), service.ErrAIQuotaExceeded, err)
+	assert.Equal(suite.T(), 0, status.Remaining)
+}
+func (suite *AIQuotaServiceTestSuite) TestConsume_ZeroLimitBlocksWithoutCounting() {
+	suite.mockRepo.On("GetLimit", uint(1)).Return(&domain.AISuggestionQuota{UserID: 1, DailyLimit: 0}, nil)
+	_, err := suite.quotaService.Consume(context.Background(), 1)
+	assert.Equal(suite.T(), service.ErrAIQuotaExceeded, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "IncrementUsage", mock.Anything, mock.Anything, mock.Anything)
+}
+func (suite *AIQuotaServiceTestSuite) TestSetLimit_UnknownUser() {
+	limit := 10
+	suite.mockUserRepo.On("GetByID", uint(9)).Return(nil, assert.AnError)
+	_, err := suite.quotaService.SetLimit(context.Background(), 9, &service.AIQuotaRequest{DailyLimit: &limit})
+	assert.Equal(suite.T(), service.ErrUserNotFound, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "SaveLimit", mock.Anything)
+}
+func (suite *AIQuotaServiceTestSuite) TestSetLimit_Success() {
+	limit := 10
+	suite.mockUserRepo.On("GetByID", uint(1)).Return(&domain.User{ID: 1}, nil)
+	suite.mockRepo.On("SaveLimit", &domain.AISuggestionQuota{UserID: 1, DailyLimit: 10}).Return(nil)
+	suite.mockRepo.On("GetLimit", uint(1)).Return(&domain.AISuggestionQuota{UserID: 1, DailyLimit: 10}, nil)
+	suite.mockRepo.On("GetUsage", uint(1), mock.AnythingOfType("time.Time")).Return(4, nil)
+	status, err := suite.quotaService.SetLimit(context.Background(), 1, &service.AIQuotaRequest{DailyLimit: &limit})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 10, status.Limit)
+	assert.Equal(suite.T(), 6, status.Remaining)
+}
+func TestAIQuotaServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AIQuotaServiceTestSuite))
+}
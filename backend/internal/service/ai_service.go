@@ -3,99 +3,223 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
+	"net/http"
 	"strings"
+	"time"
 	"weel-backend/config"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/metrics"
+	"weel-backend/internal/prompt"
+	"weel-backend/internal/tracing"
 	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
+const aiSuggestionOperation = "suggest_products"
+type ProductSuggestions struct {
+	Products      []domain.AISuggestedProduct
+	Model         string
+	PromptName    string
+	PromptVersion int
+	RawOutput     string
+}
 type AIService interface {
-	SuggestProducts(summary string, address *string) ([]domain.AISuggestedProduct, error)
+	SuggestProducts(ctx context.Context, userID uint, summary string, address *string) (*ProductSuggestions, error)
+	Ping(ctx context.Context) error
 }
 type aiService struct {
-	client *openai.Client
+	client     *openai.Client
+	recorder   AIUsageRecorder
+	prompts    PromptSource
+	baseURL    string
+	httpClient *http.Client
+}
+type AIServiceOption func(*aiService)
+func WithUsageRecorder(recorder AIUsageRecorder) AIServiceOption {
+	return func(s *aiService) {
+		s.recorder = recorder
+	}
+}
+func WithPromptSource(prompts PromptSource) AIServiceOption {
+	return func(s *aiService) {
+		s.prompts = prompts
+	}
+}
+func WithOpenAIBaseURL(baseURL string) AIServiceOption {
+	return func(s *aiService) {
+		s.baseURL = baseURL
+	}
+}
+func WithHTTPClient(client *http.Client) AIServiceOption {
+	return func(s *aiService) {
+		s.httpClient = client
+	}
 }
-func NewAIService(cfg *config.Config) AIService {
+func NewAIService(cfg *config.Config, opts ...AIServiceOption) AIService {
+	s := &aiService{baseURL: cfg.OpenAI.BaseURL}
+	for _, opt := range opts {
+		opt(s)
+	}
 	if cfg.OpenAI.Secret == "" {
-		log.Println("⚠️  Warning: OPEN_AI_SECRET not set, AI service will return empty suggestions")
-		log.Println("   To enable AI suggestions, add OPEN_AI_SECRET=sk-... to your .env file")
-		return &aiService{client: nil}
+		slog.Warn("OPEN_AI_SECRET not set, AI suggestions will be empty", "component", "ai")
+		return s
+	}
+	clientConfig := openai.DefaultConfig(cfg.OpenAI.Secret)
+	if s.baseURL != "" {
+		clientConfig.BaseURL = strings.TrimRight(s.baseURL, "/")
 	}
-	log.Println("✅ OpenAI service initialized successfully")
-	return &aiService{
-		client: openai.NewClient(cfg.OpenAI.Secret),
+	if s.httpClient != nil {
+		clientConfig.HTTPClient = s.httpClient
 	}
+	slog.Info("openai client initialized", "component", "ai", "base_url", clientConfig.BaseURL)
+	s.client = openai.NewClientWithConfig(clientConfig)
+	return s
 }
-func (s *aiService) SuggestProducts(summary string, address *string) ([]domain.AISuggestedProduct, error) {
+func (s *aiService) SuggestProducts(ctx context.Context, userID uint, summary string, address *string) (*ProductSuggestions, error) {
+	template, err := s.promptTemplate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	model := template.Model
+	result := &ProductSuggestions{
+		Products:      []domain.AISuggestedProduct{},
+		Model:         model,
+		PromptName:    template.Name,
+		PromptVersion: template.Version,
+	}
 	if s.client == nil {
-		log.Println("⚠️  AI client not initialized, returning empty suggestions")
-		return []domain.AISuggestedProduct{}, nil
-	}
-	log.Printf("🤖 Getting AI suggestions from OpenAI for: %s", summary)
-	addressContext := ""
-	if address != nil && *address != "" {
-		addressContext = fmt.Sprintf("\nDelivery Address: %s", *address)
-	}
-	prompt := fmt.Sprintf(`You are a professional pharmacy receptionist. Your role is to help customers with medicines and health-related products only. 
-Based on the customer's request below, suggest appropriate medicines and health products. Consider:
-1. Any specific medicines mentioned in the request
-2. Diseases or symptoms mentioned
-3. Location/address context (if provided) - consider local availability and common health needs in that area
-4. Only suggest medicines, supplements, medical supplies, and health-related products
-5. Do NOT suggest non-medical items like groceries, electronics, etc.
-Customer Request: %s%s
-Please respond ONLY with a valid JSON array of suggested products in this exact format:
-[
-  {
-    "name": "Product Name",
-    "quantity": 1,
-    "price": 0.00,
-    "reason": "Brief explanation why this product is suggested"
-  }
-]
-Important:
-- Return ONLY the JSON array, no other text
-- Include 2-5 relevant products
-- Use realistic prices (in USD)
-- Be specific with product names (use actual medicine names if mentioned)
-- If no medicines or health-related items are mentioned, return an empty array: []`, summary, addressContext)
+		slog.WarnContext(ctx, "ai client not configured, returning empty suggestions", "component", "ai")
+		return result, nil
+	}
+	slog.DebugContext(ctx, "requesting ai suggestions", "component", "ai", "model", model, "prompt", template.Name, "prompt_version", template.Version, "summary_length", len(summary))
+	data := prompt.Data{Summary: summary}
+	if address != nil {
+		data.DeliveryAddress = *address
+	}
+	rendered, err := prompt.Render(template, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prompt: %w", err)
+	}
+	messages := make([]openai.ChatCompletionMessage, 0, 2)
+	if rendered.System != "" {
+		messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: rendered.System})
+	}
+	messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: rendered.User})
 	req := openai.ChatCompletionRequest{
-		Model: "gpt-4o-mini",
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: "You are a professional pharmacy receptionist. You only handle medicines and health-related products. Respond with valid JSON arrays only.",
-			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			},
-		},
-		Temperature: 0.7,
-		MaxTokens:   500,
-	}
-	ctx := context.Background()
+		Model:       rendered.Model,
+		Messages:    messages,
+		Temperature: rendered.Temperature,
+		MaxTokens:   rendered.MaxTokens,
+	}
+	if req.Temperature == 0 {
+		// go-openai omits a zero temperature, which the API would treat as its default of 1.
+		req.Temperature = math.SmallestNonzeroFloat32
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "chat "+model,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.GenAiSystemOpenai,
+			attribute.String("gen_ai.operation.name", "chat"),
+			semconv.GenAiRequestModel(req.Model),
+			semconv.GenAiRequestMaxTokens(req.MaxTokens),
+			semconv.GenAiRequestTemperature(float64(req.Temperature)),
+			attribute.String("weel.ai.prompt", template.Name),
+			attribute.Int("weel.ai.prompt_version", template.Version),
+		),
+	)
+	defer span.End()
+	started := time.Now()
 	resp, err := s.client.CreateChatCompletion(ctx, req)
+	elapsed := time.Since(started)
 	if err != nil {
-		log.Printf("❌ Error calling OpenAI API: %v", err)
-		return []domain.AISuggestedProduct{}, fmt.Errorf("failed to get AI suggestions: %w", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "openai request failed")
+		s.observe(ctx, userID, model, metrics.AIOutcomeError, elapsed, openai.Usage{})
+		slog.ErrorContext(ctx, "openai request failed", "component", "ai", "model", model, "elapsed_ms", elapsed.Milliseconds(), "error", err)
+		return nil, fmt.Errorf("failed to get AI suggestions: %w", err)
+	}
+	finishReasons := make([]string, 0, len(resp.Choices))
+	for _, choice := range resp.Choices {
+		finishReasons = append(finishReasons, string(choice.FinishReason))
+	}
+	span.SetAttributes(
+		semconv.GenAiResponseID(resp.ID),
+		semconv.GenAiResponseModel(resp.Model),
+		semconv.GenAiResponseFinishReasons(finishReasons...),
+		semconv.GenAiUsagePromptTokens(resp.Usage.PromptTokens),
+		semconv.GenAiUsageCompletionTokens(resp.Usage.CompletionTokens),
+	)
+	outcome := metrics.AIOutcomeSuccess
+	defer func() {
+		s.observe(ctx, userID, model, outcome, elapsed, resp.Usage)
+	}()
+	if resp.Model != "" {
+		result.Model = resp.Model
 	}
 	if len(resp.Choices) == 0 {
-		log.Println("⚠️  OpenAI returned no choices")
-		return []domain.AISuggestedProduct{}, nil
+		slog.WarnContext(ctx, "openai returned no choices", "component", "ai", "model", model)
+		return result, nil
 	}
-	log.Printf("✅ OpenAI responded with %d choice(s)", len(resp.Choices))
-	content := strings.TrimSpace(resp.Choices[0].Message.Content)
+	result.RawOutput = resp.Choices[0].Message.Content
+	products, err := ParseSuggestedProducts(result.RawOutput)
+	if err != nil {
+		outcome = metrics.AIOutcomeParseError
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to parse ai response")
+		slog.ErrorContext(ctx, "failed to parse ai response", "component", "ai", "model", model, "error", err, "content_length", len(result.RawOutput))
+		return result, err
+	}
+	span.SetAttributes(attribute.Int("weel.ai.suggested_products", len(products)))
+	slog.InfoContext(ctx, "ai suggestions generated", "component", "ai", "model", model,
+		"products", len(products), "elapsed_ms", elapsed.Milliseconds(),
+		"prompt_tokens", resp.Usage.PromptTokens, "completion_tokens", resp.Usage.CompletionTokens)
+	result.Products = append(result.Products, products...)
+	return result, nil
+}
+func ParseSuggestedProducts(raw string) ([]domain.AISuggestedProduct, error) {
+	content := strings.TrimSpace(raw)
 	content = strings.TrimPrefix(content, "```json")
 	content = strings.TrimPrefix(content, "```")
 	content = strings.TrimSuffix(content, "```")
 	content = strings.TrimSpace(content)
 	var products []domain.AISuggestedProduct
 	if err := json.Unmarshal([]byte(content), &products); err != nil {
-		log.Printf("❌ Error parsing AI response: %v", err)
-		log.Printf("   Raw content: %s", content)
-		return []domain.AISuggestedProduct{}, fmt.Errorf("failed to parse AI response: %w", err)
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
 	}
-	log.Printf("✅ AI suggested %d products", len(products))
 	return products, nil
 }
+func (s *aiService) promptTemplate(ctx context.Context) (*domain.PromptTemplate, error) {
+	if s.prompts != nil {
+		return s.prompts.Active(ctx, prompt.SuggestProducts)
+	}
+	return prompt.Default(prompt.SuggestProducts)
+}
+func (s *aiService) observe(ctx context.Context, userID uint, model, outcome string, elapsed time.Duration, usage openai.Usage) {
+	metrics.ObserveAIRequest(aiSuggestionOperation, model, outcome, elapsed, usage.PromptTokens, usage.CompletionTokens)
+	if s.recorder == nil {
+		return
+	}
+	record := &domain.AIUsage{
+		Operation:        aiSuggestionOperation,
+		Model:            model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		LatencyMs:        elapsed.Milliseconds(),
+		Outcome:          outcome,
+	}
+	if userID != 0 {
+		record.UserID = &userID
+	}
+	s.recorder.Record(ctx, record)
+}
+func (s *aiService) Ping(ctx context.Context) error {
+	if s.client == nil {
+		return ErrAINotConfigured
+	}
+	_, err := s.client.ListModels(ctx)
+	return err
+}
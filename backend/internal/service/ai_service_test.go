@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"weel-backend/config"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/httpfixture"
+	"weel-backend/internal/metrics"
+	"weel-backend/internal/service"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type captureUsageRecorder struct {
+	mu      sync.Mutex
+	records []*domain.AIUsage
+}
+
+func (r *captureUsageRecorder) Record(ctx context.Context, usage *domain.AIUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, usage)
+}
+
+// AIServiceTestSuite replays OpenAI responses from testdata/openai. Run with
+// RECORD_FIXTURES=1 and OPEN_AI_SECRET set to re-record them against the real API.
+type AIServiceTestSuite struct {
+	suite.Suite
+	recorder *captureUsageRecorder
+}
+
+func (suite *AIServiceTestSuite) SetupTest() {
+	suite.recorder = &captureUsageRecorder{}
+}
+func (suite *AIServiceTestSuite) newAIService(fixture string) service.AIService {
+	mode := httpfixture.ModeFromEnv()
+	secret := "test-key"
+	if mode == httpfixture.ModeRecord {
+		secret = os.Getenv("OPEN_AI_SECRET")
+		if secret == "" {
+			suite.T().Skip("OPEN_AI_SECRET is required to record fixtures")
+		}
+	}
+	transport, err := httpfixture.New(filepath.Join("testdata", "openai", fixture+".json"), mode)
+	suite.Require().NoError(err)
+	t := suite.T()
+	t.Cleanup(func() {
+		assert.NoError(t, transport.Save())
+		if mode == httpfixture.ModeReplay {
+			assert.Zero(t, transport.Unused(), "fixture %s has unused interactions", fixture)
+		}
+	})
+	cfg := &config.Config{OpenAI: config.OpenAIConfig{Secret: secret}}
+	return service.NewAIService(cfg,
+		service.WithHTTPClient(&http.Client{Transport: transport}),
+		service.WithUsageRecorder(suite.recorder),
+	)
+}
+func (suite *AIServiceTestSuite) lastUsage() *domain.AIUsage {
+	suite.Require().NotEmpty(suite.recorder.records)
+	return suite.recorder.records[len(suite.recorder.records)-1]
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_Success() {
+	ai := suite.newAIService("suggest_products_success")
+	result, err := ai.SuggestProducts(context.Background(), 7, "I have a bad headache and a mild fever since yesterday", nil)
+	suite.Require().NoError(err)
+	suite.Len(result.Products, 2)
+	suite.Equal("Tylenol Extra Strength 500mg", result.Products[0].Name)
+	suite.Equal(9.99, result.Products[0].Price)
+	suite.Equal("gpt-4o-mini-2024-07-18", result.Model)
+	suite.Equal("suggest_products", result.PromptName)
+	suite.Equal(1, result.PromptVersion)
+	suite.NotEmpty(result.RawOutput)
+	usage := suite.lastUsage()
+	suite.Equal(metrics.AIOutcomeSuccess, usage.Outcome)
+	suite.Equal(312, usage.PromptTokens)
+	suite.Equal(96, usage.CompletionTokens)
+	suite.Equal(uint(7), *usage.UserID)
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_StripsCodeFences() {
+	ai := suite.newAIService("suggest_products_fenced")
+	address := "1200 Ocean Dr, Miami, FL 33139"
+	result, err := ai.SuggestProducts(context.Background(), 7, "Headache and fever, need something fast", &address)
+	suite.Require().NoError(err)
+	suite.Len(result.Products, 2)
+	suite.Contains(result.RawOutput, "```json")
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_InvalidJSON() {
+	ai := suite.newAIService("suggest_products_invalid_json")
+	result, err := ai.SuggestProducts(context.Background(), 7, "Can you recommend something for me?", nil)
+	suite.Error(err)
+	suite.Require().NotNil(result)
+	suite.Empty(result.Products)
+	suite.Contains(result.RawOutput, "I'm sorry")
+	suite.Equal(metrics.AIOutcomeParseError, suite.lastUsage().Outcome)
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_NoChoices() {
+	ai := suite.newAIService("suggest_products_no_choices")
+	result, err := ai.SuggestProducts(context.Background(), 7, "Need something for allergies", nil)
+	suite.Require().NoError(err)
+	suite.Empty(result.Products)
+	suite.Empty(result.RawOutput)
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_RateLimited() {
+	ai := suite.newAIService("suggest_products_rate_limited")
+	result, err := ai.SuggestProducts(context.Background(), 7, "Sore throat and a runny nose", nil)
+	suite.Nil(result)
+	var apiErr *openai.APIError
+	suite.Require().True(errors.As(err, &apiErr))
+	suite.Equal(http.StatusTooManyRequests, apiErr.HTTPStatusCode)
+	suite.Equal(metrics.AIOutcomeError, suite.lastUsage().Outcome)
+	suite.Zero(suite.lastUsage().PromptTokens)
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_ServerError() {
+	ai := suite.newAIService("suggest_products_server_error")
+	_, err := ai.SuggestProducts(context.Background(), 7, "Back pain after lifting boxes", nil)
+	var apiErr *openai.APIError
+	suite.Require().True(errors.As(err, &apiErr))
+	suite.Equal(http.StatusInternalServerError, apiErr.HTTPStatusCode)
+}
+func (suite *AIServiceTestSuite) TestSuggestProducts_PromptChangeIsDetected() {
+	if httpfixture.ModeFromEnv() == httpfixture.ModeRecord {
+		suite.T().Skip("only meaningful when replaying")
+	}
+	transport, err := httpfixture.New(filepath.Join("testdata", "openai", "suggest_products_success.json"), httpfixture.ModeReplay)
+	suite.Require().NoError(err)
+	cfg := &config.Config{OpenAI: config.OpenAIConfig{Secret: "test-key"}}
+	ai := service.NewAIService(cfg, service.WithHTTPClient(&http.Client{Transport: transport}))
+	_, err = ai.SuggestProducts(context.Background(), 7, "A different summary than the one recorded", nil)
+	suite.ErrorIs(err, httpfixture.ErrNoInteraction)
+}
+func (suite *AIServiceTestSuite) TestPing() {
+	suite.NoError(suite.newAIService("ping").Ping(context.Background()))
+	unconfigured := service.NewAIService(&config.Config{})
+	suite.Equal(service.ErrAINotConfigured, unconfigured.Ping(context.Background()))
+}
+func TestParseSuggestedProducts(t *testing.T) {
+	for _, raw := range []string{
+		`[{"name":"Ibuprofen","quantity":1,"price":5}]`,
+		"```json\n[{\"name\":\"Ibuprofen\",\"quantity\":1,\"price\":5}]\n```",
+		"```\n[{\"name\":\"Ibuprofen\",\"quantity\":1,\"price\":5}]\n```",
+		"  \n[{\"name\":\"Ibuprofen\",\"quantity\":1,\"price\":5}]  \n",
+	} {
+		products, err := service.ParseSuggestedProducts(raw)
+		assert.NoError(t, err, raw)
+		assert.Equal(t, []domain.AISuggestedProduct{{Name: "Ibuprofen", Quantity: 1, Price: 5}}, products)
+	}
+	_, err := service.ParseSuggestedProducts(`{"name":"Ibuprofen"}`)
+	assert.Error(t, err)
+}
+func TestAIServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AIServiceTestSuite))
+}
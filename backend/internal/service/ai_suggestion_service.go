@@ -0,0 +1,84 @@
+package service
+import (
+	"context"
+	"errors"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+	"gorm.io/gorm"
+)
+type AISuggestionStatsQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+type AISuggestionStatsReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	*domain.AISuggestionStats
+}
+type AISuggestionService interface {
+	GetSession(ctx context.Context, id, userID uint, role domain.UserRole) (*domain.AISuggestionSession, error)
+	Stats(ctx context.Context, query *AISuggestionStatsQuery) (*AISuggestionStatsReport, error)
+}
+type aiSuggestionService struct {
+	suggestionRepo repository.AISuggestionRepository
+	orderRepo      repository.OrderRepository
+	directory      StoreDirectory
+	now            func() time.Time
+}
+func NewAISuggestionService(suggestionRepo repository.AISuggestionRepository, orderRepo repository.OrderRepository, directory StoreDirectory) AISuggestionService {
+	return &aiSuggestionService{suggestionRepo: suggestionRepo, orderRepo: orderRepo, directory: directory, now: time.Now}
+}
+func (s *aiSuggestionService) GetSession(ctx context.Context, id, userID uint, role domain.UserRole) (*domain.AISuggestionSession, error) {
+	session, err := s.suggestionRepo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSuggestionSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID == userID {
+		return session, nil
+	}
+	if !role.IsStaff() {
+		return nil, ErrSuggestionSessionNotFound
+	}
+	if err := s.authorizeStaff(ctx, session, userID, role); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+func (s *aiSuggestionService) authorizeStaff(ctx context.Context, session *domain.AISuggestionSession, userID uint, role domain.UserRole) error {
+	if role == domain.UserRoleAdmin {
+		return nil
+	}
+	storeID, err := s.directory.StaffStoreID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if storeID == nil || session.OrderID == nil {
+		return ErrUnauthorizedAccess
+	}
+	order, err := s.orderRepo.GetByID(ctx, *session.OrderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUnauthorizedAccess
+	}
+	if err != nil {
+		return err
+	}
+	if order.StoreID == nil || *order.StoreID != *storeID {
+		return ErrUnauthorizedAccess
+	}
+	return nil
+}
+func (s *aiSuggestionService) Stats(ctx context.Context, query *AISuggestionStatsQuery) (*AISuggestionStatsReport, error) {
+	from, to, err := parseReportRange(query.From, query.To, s.now())
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.suggestionRepo.Stats(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &AISuggestionStatsReport{From: from, To: to, AISuggestionStats: stats}, nil
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AISuggestionServiceTestSuite struct {
+	suite.Suite
+	suggestionService service.AISuggestionService
+	mockRepo          *MockAISuggestionRepository
+	mockOrderRepo     *MockOrderRepository
+	directory         *stubStoreDirectory
+}
+
+func (suite *AISuggestionServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockAISuggestionRepository)
+	suite.mockOrderRepo = new(MockOrderRepository)
+	suite.directory = &stubStoreDirectory{staffStore: map[uint]uint{5: 1, 6: 2}}
+	suite.suggestionService = service.NewAISuggestionService(suite.mockRepo, suite.mockOrderRepo, suite.directory)
+}
+func (suite *AISuggestionServiceTestSuite) linkedSession(storeID uint) *domain.AISuggestionSession {
+	orderID := uint(9)
+	suite.mockOrderRepo.On("GetByID", orderID).Return(&domain.Order{ID: orderID, UserID: 1, StoreID: &storeID}, nil)
+	return &domain.AISuggestionSession{ID: 3, UserID: 1, OrderID: &orderID}
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_Owner() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.AISuggestionSession{ID: 3, UserID: 1}, nil)
+	session, err := suite.suggestionService.GetSession(context.Background(), 3, 1, domain.UserRoleCustomer)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(3), session.ID)
+	suite.mockOrderRepo.AssertNotCalled(suite.T(), "GetByID", uint(9))
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_OtherCustomer() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.AISuggestionSession{ID: 3, UserID: 1}, nil)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 2, domain.UserRoleCustomer)
+	assert.Equal(suite.T(), service.ErrSuggestionSessionNotFound, err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_AdminSeesAnySession() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.AISuggestionSession{ID: 3, UserID: 1}, nil)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 7, domain.UserRoleAdmin)
+	assert.NoError(suite.T(), err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_PharmacistInSameStore() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(suite.linkedSession(1), nil)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 5, domain.UserRolePharmacist)
+	assert.NoError(suite.T(), err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_PharmacistInOtherStore() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(suite.linkedSession(1), nil)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 6, domain.UserRolePharmacist)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_PharmacistUnlinkedSession() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.AISuggestionSession{ID: 3, UserID: 1}, nil)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 5, domain.UserRolePharmacist)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_PharmacistWithoutStore() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(suite.linkedSession(1), nil)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 8, domain.UserRolePharmacist)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_NotFound() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 1, domain.UserRoleCustomer)
+	assert.Equal(suite.T(), service.ErrSuggestionSessionNotFound, err)
+}
+func (suite *AISuggestionServiceTestSuite) TestGetSession_PassesDatabaseErrors() {
+	dbErr := errors.New("connection refused")
+	suite.mockRepo.On("GetByID", uint(3)).Return(nil, dbErr)
+	_, err := suite.suggestionService.GetSession(context.Background(), 3, 1, domain.UserRoleCustomer)
+	assert.Equal(suite.T(), dbErr, err)
+}
+
+func TestAISuggestionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AISuggestionServiceTestSuite))
+}
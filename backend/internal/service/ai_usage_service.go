@@ -0,0 +1,151 @@
+package service
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+)
+const defaultAIUsageReportDays = 30
+type AIPrice struct {
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+}
+type AIPriceTable map[string]AIPrice
+func ParseAIPriceTable(spec string) (AIPriceTable, error) {
+	table := AIPriceTable{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, prices, ok := strings.Cut(entry, "=")
+		input, output, ok2 := strings.Cut(prices, ":")
+		if !ok || !ok2 || strings.TrimSpace(model) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAIPriceTable, entry)
+		}
+		in, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil || in < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAIPriceTable, entry)
+		}
+		out, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if err != nil || out < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAIPriceTable, entry)
+		}
+		table[strings.TrimSpace(model)] = AIPrice{InputPerMillion: in, OutputPerMillion: out}
+	}
+	return table, nil
+}
+func (t AIPriceTable) Cost(model string, promptTokens, completionTokens int) (float64, bool) {
+	price, ok := t[model]
+	if !ok {
+		longest := 0
+		for name, p := range t {
+			if len(name) > longest && strings.HasPrefix(model, name) {
+				price, ok, longest = p, true, len(name)
+			}
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	return (float64(promptTokens)*price.InputPerMillion + float64(completionTokens)*price.OutputPerMillion) / 1e6, true
+}
+type AIUsageRecorder interface {
+	Record(ctx context.Context, usage *domain.AIUsage)
+}
+type AIUsageReportQuery struct {
+	From   string `form:"from"`
+	To     string `form:"to"`
+	UserID *uint  `form:"user_id"`
+}
+type AIUsageTotals struct {
+	Requests         int64            `json:"requests"`
+	PromptTokens     int64            `json:"prompt_tokens"`
+	CompletionTokens int64            `json:"completion_tokens"`
+	EstimatedCostUSD float64          `json:"estimated_cost_usd"`
+	ByOutcome        map[string]int64 `json:"by_outcome"`
+}
+type AIUsageReport struct {
+	From   time.Time                `json:"from"`
+	To     time.Time                `json:"to"`
+	Data   []*domain.AIUsageSummary `json:"data"`
+	Count  int                      `json:"count"`
+	Totals AIUsageTotals            `json:"totals"`
+}
+type AIUsageService interface {
+	AIUsageRecorder
+	Report(ctx context.Context, query *AIUsageReportQuery) (*AIUsageReport, error)
+}
+type aiUsageService struct {
+	usageRepo repository.AIUsageRepository
+	prices    AIPriceTable
+	now       func() time.Time
+}
+func NewAIUsageService(usageRepo repository.AIUsageRepository, prices AIPriceTable) AIUsageService {
+	return &aiUsageService{usageRepo: usageRepo, prices: prices, now: time.Now}
+}
+func (s *aiUsageService) Record(ctx context.Context, usage *domain.AIUsage) {
+	cost, ok := s.prices.Cost(usage.Model, usage.PromptTokens, usage.CompletionTokens)
+	if !ok && usage.PromptTokens+usage.CompletionTokens > 0 {
+		slog.WarnContext(ctx, "no price configured for ai model, recording zero cost", "component", "ai", "model", usage.Model)
+	}
+	usage.EstimatedCostUSD = cost
+	if err := s.usageRepo.Create(context.WithoutCancel(ctx), usage); err != nil {
+		slog.ErrorContext(ctx, "failed to record ai usage", "component", "ai", "model", usage.Model, "outcome", usage.Outcome, "error", err)
+	}
+}
+func (s *aiUsageService) Report(ctx context.Context, query *AIUsageReportQuery) (*AIUsageReport, error) {
+	from, to, err := parseReportRange(query.From, query.To, s.now())
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.usageRepo.Summarize(ctx, repository.AIUsageFilters{From: &from, To: &to, UserID: query.UserID})
+	if err != nil {
+		return nil, err
+	}
+	report := &AIUsageReport{
+		From:   from,
+		To:     to,
+		Data:   summaries,
+		Count:  len(summaries),
+		Totals: AIUsageTotals{ByOutcome: map[string]int64{}},
+	}
+	for _, row := range summaries {
+		report.Totals.Requests += row.Requests
+		report.Totals.PromptTokens += row.PromptTokens
+		report.Totals.CompletionTokens += row.CompletionTokens
+		report.Totals.EstimatedCostUSD += row.EstimatedCostUSD
+		report.Totals.ByOutcome[row.Outcome] += row.Requests
+	}
+	return report, nil
+}
+func parseReportRange(rawFrom, rawTo string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	if rawTo != "" {
+		t, err := parseFilterTime(rawTo)
+		if err != nil {
+			return time.Time{}, time.Time{}, &InvalidFilterError{Param: "to", Value: rawTo, Reason: "expected RFC 3339 timestamp or YYYY-MM-DD date"}
+		}
+		if len(rawTo) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -defaultAIUsageReportDays)
+	if rawFrom != "" {
+		t, err := parseFilterTime(rawFrom)
+		if err != nil {
+			return time.Time{}, time.Time{}, &InvalidFilterError{Param: "from", Value: rawFrom, Reason: "expected RFC 3339 timestamp or YYYY-MM-DD date"}
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, &InvalidFilterError{Param: "from", Value: rawFrom, Reason: "must be earlier than to"}
+	}
+	return from, to, nil
+}
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAIUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockAIUsageRepository) Create(ctx context.Context, usage *domain.AIUsage) error {
+	args := m.Called(usage)
+	return args.Error(0)
+}
+func (m *MockAIUsageRepository) Summarize(ctx context.Context, filters repository.AIUsageFilters) ([]*domain.AIUsageSummary, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AIUsageSummary), args.Error(1)
+}
+
+type AIUsageServiceTestSuite struct {
+	suite.Suite
+	usageService service.AIUsageService
+	mockRepo     *MockAIUsageRepository
+}
+
+func (suite *AIUsageServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockAIUsageRepository)
+	prices, err := service.ParseAIPriceTable("gpt-4o-mini=0.15:0.60, gpt-4o=2.50:10")
+	suite.Require().NoError(err)
+	suite.usageService = service.NewAIUsageService(suite.mockRepo, prices)
+}
+func (suite *AIUsageServiceTestSuite) TestParseAIPriceTable_Invalid() {
+	for _, spec := range []string{"gpt-4o", "gpt-4o=1", "=1:2", "gpt-4o=x:1", "gpt-4o=1:-2"} {
+		_, err := service.ParseAIPriceTable(spec)
+		assert.ErrorIs(suite.T(), err, service.ErrInvalidAIPriceTable, spec)
+	}
+}
+func (suite *AIUsageServiceTestSuite) TestPriceTable_CostMatchesLongestPrefix() {
+	prices, _ := service.ParseAIPriceTable("gpt-4o=2.50:10,gpt-4o-mini=0.15:0.60")
+	cost, ok := prices.Cost("gpt-4o-mini-2024-07-18", 1_000_000, 1_000_000)
+	assert.True(suite.T(), ok)
+	assert.InDelta(suite.T(), 0.75, cost, 1e-9)
+	_, ok = prices.Cost("claude", 10, 10)
+	assert.False(suite.T(), ok)
+}
+func (suite *AIUsageServiceTestSuite) TestRecord_EstimatesCost() {
+	userID := uint(3)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.AIUsage")).Return(nil)
+	suite.usageService.Record(context.Background(), &domain.AIUsage{
+		UserID:           &userID,
+		Model:            "gpt-4o-mini",
+		PromptTokens:     2000,
+		CompletionTokens: 500,
+		Outcome:          "success",
+	})
+	recorded := suite.mockRepo.Calls[0].Arguments.Get(0).(*domain.AIUsage)
+	assert.InDelta(suite.T(), 0.0006, recorded.EstimatedCostUSD, 1e-9)
+}
+func (suite *AIUsageServiceTestSuite) TestRecord_SwallowsErrors() {
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.AIUsage")).Return(errors.New("db down"))
+	assert.NotPanics(suite.T(), func() {
+		suite.usageService.Record(context.Background(), &domain.AIUsage{Model: "gpt-4o", Outcome: "error"})
+	})
+}
+func (suite *AIUsageServiceTestSuite) TestReport_AggregatesTotals() {
+	suite.mockRepo.On("Summarize", mock.MatchedBy(func(f repository.AIUsageFilters) bool {
+		return f.From.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) && f.To.Equal(time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC))
+	})).Return([]*domain.AIUsageSummary{
+		{Outcome: "success", Requests: 4, PromptTokens: 800, CompletionTokens: 200, EstimatedCostUSD: 0.25},
+		{Outcome: "error", Requests: 1},
+		{Outcome: "success", Requests: 2, PromptTokens: 100, CompletionTokens: 50, EstimatedCostUSD: 0.05},
+	}, nil)
+	report, err := suite.usageService.Report(context.Background(), &service.AIUsageReportQuery{From: "2026-10-01", To: "2026-10-02"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, report.Count)
+	assert.Equal(suite.T(), int64(7), report.Totals.Requests)
+	assert.Equal(suite.T(), int64(900), report.Totals.PromptTokens)
+	assert.InDelta(suite.T(), 0.30, report.Totals.EstimatedCostUSD, 1e-9)
+	assert.Equal(suite.T(), map[string]int64{"success": 6, "error": 1}, report.Totals.ByOutcome)
+}
+func (suite *AIUsageServiceTestSuite) TestReport_InvalidRange() {
+	_, err := suite.usageService.Report(context.Background(), &service.AIUsageReportQuery{From: "2026-10-05", To: "2026-10-01"})
+	var filterErr *service.InvalidFilterError
+	assert.ErrorAs(suite.T(), err, &filterErr)
+	_, err = suite.usageService.Report(context.Background(), &service.AIUsageReportQuery{From: "yesterday"})
+	assert.ErrorAs(suite.T(), err, &filterErr)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Summarize", mock.Anything)
+}
+func TestAIUsageServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AIUsageServiceTestSuite))
+}
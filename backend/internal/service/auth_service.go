@@ -1,13 +1,14 @@
 package service
 import (
+	"context"
 	"time"
 	"weel-backend/internal/domain"
 	"weel-backend/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 type AuthService interface {
-	Login(email, password string) (*LoginResponse, error)
-	GetCurrentUser(userID uint) (*domain.User, error)
+	Login(ctx context.Context, email, password string) (*LoginResponse, error)
+	GetCurrentUser(ctx context.Context, userID uint) (*domain.User, error)
 }
 type LoginResponse struct {
 	Token string       `json:"token"`
@@ -23,8 +24,8 @@ func NewAuthService(userRepo repository.UserRepository, jwtService *JWTService)
 		jwtService: jwtService,
 	}
 }
-func (s *authService) Login(email, password string) (*LoginResponse, error) {
-	user, err := s.userRepo.GetByEmail(email)
+func (s *authService) Login(ctx context.Context, email, password string) (*LoginResponse, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, ErrInvalidCredentials
 	}
@@ -33,10 +34,10 @@ func (s *authService) Login(email, password string) (*LoginResponse, error) {
 	}
 	now := time.Now()
 	user.LastLogin = &now
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
-	token, err := s.jwtService.GenerateToken(user.ID, user.Email)
+	token, err := s.jwtService.GenerateToken(user.ID, user.Email, user.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -45,8 +46,8 @@ func (s *authService) Login(email, password string) (*LoginResponse, error) {
 		User:  user,
 	}, nil
 }
-func (s *authService) GetCurrentUser(userID uint) (*domain.User, error) {
-	user, err := s.userRepo.GetByID(userID)
+func (s *authService) GetCurrentUser(ctx context.Context, userID uint) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
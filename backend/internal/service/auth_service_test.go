@@ -1,7 +1,9 @@
 package service_test
 import (
+	"context"
 	"testing"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/pagination"
 	"weel-backend/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -10,40 +12,47 @@ import (
 type MockUserRepositoryForAuth struct {
 	mock.Mock
 }
-func (m *MockUserRepositoryForAuth) Create(user *domain.User) error {
+func (m *MockUserRepositoryForAuth) Create(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
-func (m *MockUserRepositoryForAuth) GetByID(id uint) (*domain.User, error) {
+func (m *MockUserRepositoryForAuth) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
-func (m *MockUserRepositoryForAuth) GetByEmail(email string) (*domain.User, error) {
+func (m *MockUserRepositoryForAuth) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
-func (m *MockUserRepositoryForAuth) Update(user *domain.User) error {
+func (m *MockUserRepositoryForAuth) Update(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
-func (m *MockUserRepositoryForAuth) Delete(id uint) error {
+func (m *MockUserRepositoryForAuth) Delete(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
-func (m *MockUserRepositoryForAuth) List(limit, offset int) ([]*domain.User, error) {
+func (m *MockUserRepositoryForAuth) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
 	args := m.Called(limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.User), args.Error(1)
 }
-func (m *MockUserRepositoryForAuth) Count() (int64, error) {
+func (m *MockUserRepositoryForAuth) ListPage(ctx context.Context, params pagination.Params) ([]*domain.User, *pagination.Page, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*domain.User), args.Get(1).(*pagination.Page), args.Error(2)
+}
+func (m *MockUserRepositoryForAuth) Count(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -69,7 +78,7 @@ func (suite *AuthServiceTestSuite) TestLogin_Success() {
 	}
 	suite.mockRepo.On("GetByEmail", email).Return(user, nil)
 	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.User")).Return(nil)
-	response, err := suite.authService.Login(email, password)
+	response, err := suite.authService.Login(context.Background(), email, password)
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), response)
 	assert.NotEmpty(suite.T(), response.Token)
@@ -86,7 +95,7 @@ func (suite *AuthServiceTestSuite) TestLogin_InvalidCredentials() {
 		Password: hashedPassword,
 	}
 	suite.mockRepo.On("GetByEmail", email).Return(user, nil)
-	response, err := suite.authService.Login(email, password)
+	response, err := suite.authService.Login(context.Background(), email, password)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), response)
 	assert.Equal(suite.T(), service.ErrInvalidCredentials, err)
@@ -99,7 +108,7 @@ func (suite *AuthServiceTestSuite) TestGetCurrentUser_Success() {
 		Email: "test@example.com",
 	}
 	suite.mockRepo.On("GetByID", userID).Return(user, nil)
-	result, err := suite.authService.GetCurrentUser(userID)
+	result, err := suite.authService.GetCurrentUser(context.Background(), userID)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), user.ID, result.ID)
 	suite.mockRepo.AssertExpectations(suite.T())
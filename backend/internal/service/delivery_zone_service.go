@@ -0,0 +1,177 @@
+package service
+import (
+	"context"
+	"strings"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/postal"
+	"weel-backend/internal/repository"
+)
+type DeliveryQuoteRequest struct {
+	PostalCode string   `form:"postal_code" binding:"required"`
+	Country    string   `form:"country"`
+	Latitude   *float64 `form:"lat" binding:"omitempty,min=-90,max=90"`
+	Longitude  *float64 `form:"lng" binding:"omitempty,min=-180,max=180"`
+	Subtotal   *float64 `form:"subtotal" binding:"omitempty,min=0"`
+}
+type DeliveryQuote struct {
+	Deliverable    bool    `json:"deliverable"`
+	PostalCode     string  `json:"postal_code"`
+	ZoneID         *uint   `json:"zone_id,omitempty"`
+	ZoneName       string  `json:"zone_name,omitempty"`
+	Fee            float64 `json:"fee"`
+	MinOrderAmount float64 `json:"min_order_amount"`
+	MeetsMinimum   *bool   `json:"meets_minimum,omitempty"`
+}
+type DeliveryZoneRequest struct {
+	Name           string       `json:"name" binding:"required,max=100"`
+	Country        string       `json:"country" binding:"omitempty,len=2"`
+	PostalCodes    []string     `json:"postal_codes"`
+	PostalPrefixes []string     `json:"postal_prefixes"`
+	Polygon        [][2]float64 `json:"polygon"`
+	Fee            float64      `json:"fee" binding:"min=0"`
+	MinOrderAmount float64      `json:"min_order_amount" binding:"min=0"`
+	Priority       int          `json:"priority"`
+	Active         *bool        `json:"active,omitempty"`
+}
+type DeliveryQuoter interface {
+	Quote(ctx context.Context, req *DeliveryQuoteRequest) (*DeliveryQuote, error)
+}
+type DeliveryZoneService interface {
+	DeliveryQuoter
+	ListZones(ctx context.Context) ([]*domain.DeliveryZone, error)
+	GetZone(ctx context.Context, id uint) (*domain.DeliveryZone, error)
+	CreateZone(ctx context.Context, req *DeliveryZoneRequest) (*domain.DeliveryZone, error)
+	UpdateZone(ctx context.Context, id uint, req *DeliveryZoneRequest) (*domain.DeliveryZone, error)
+	DeleteZone(ctx context.Context, id uint) error
+}
+type deliveryZoneService struct {
+	zoneRepo repository.DeliveryZoneRepository
+}
+func NewDeliveryZoneService(zoneRepo repository.DeliveryZoneRepository) DeliveryZoneService {
+	return &deliveryZoneService{zoneRepo: zoneRepo}
+}
+func (s *deliveryZoneService) Quote(ctx context.Context, req *DeliveryQuoteRequest) (*DeliveryQuote, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.PostalCode))
+	country := ""
+	if req.Country != "" {
+		c, err := postal.NormalizeCountry(req.Country)
+		if err != nil {
+			return nil, ErrInvalidCountry
+		}
+		normalized, err := postal.Normalize(c, req.PostalCode)
+		if err != nil {
+			return nil, ErrInvalidPostalCode
+		}
+		country, code = c, normalized
+	}
+	if code == "" {
+		return nil, ErrPostalCodeRequired
+	}
+	zones, err := s.zoneRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quote := &DeliveryQuote{PostalCode: code}
+	var best *domain.DeliveryZone
+	bestScore := 0
+	for _, zone := range zones {
+		score, ok := zone.Match(country, code, req.Latitude, req.Longitude)
+		if !ok {
+			continue
+		}
+		if best == nil || zone.Priority > best.Priority || (zone.Priority == best.Priority && score > bestScore) {
+			best, bestScore = zone, score
+		}
+	}
+	if best == nil {
+		return quote, nil
+	}
+	quote.Deliverable = true
+	quote.ZoneID = &best.ID
+	quote.ZoneName = best.Name
+	quote.Fee = best.Fee
+	quote.MinOrderAmount = best.MinOrderAmount
+	if req.Subtotal != nil {
+		meets := *req.Subtotal >= best.MinOrderAmount
+		quote.MeetsMinimum = &meets
+	}
+	return quote, nil
+}
+func (s *deliveryZoneService) ListZones(ctx context.Context) ([]*domain.DeliveryZone, error) {
+	return s.zoneRepo.List(ctx)
+}
+func (s *deliveryZoneService) GetZone(ctx context.Context, id uint) (*domain.DeliveryZone, error) {
+	zone, err := s.zoneRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrDeliveryZoneNotFound
+	}
+	return zone, nil
+}
+func (s *deliveryZoneService) CreateZone(ctx context.Context, req *DeliveryZoneRequest) (*domain.DeliveryZone, error) {
+	zone := &domain.DeliveryZone{Active: true}
+	if err := applyDeliveryZoneRequest(zone, req); err != nil {
+		return nil, err
+	}
+	if err := s.zoneRepo.Create(ctx, zone); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+func (s *deliveryZoneService) UpdateZone(ctx context.Context, id uint, req *DeliveryZoneRequest) (*domain.DeliveryZone, error) {
+	zone, err := s.GetZone(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyDeliveryZoneRequest(zone, req); err != nil {
+		return nil, err
+	}
+	if err := s.zoneRepo.Update(ctx, zone); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+func (s *deliveryZoneService) DeleteZone(ctx context.Context, id uint) error {
+	if _, err := s.GetZone(ctx, id); err != nil {
+		return err
+	}
+	return s.zoneRepo.Delete(ctx, id)
+}
+func applyDeliveryZoneRequest(zone *domain.DeliveryZone, req *DeliveryZoneRequest) error {
+	codes := cleanPostalList(req.PostalCodes)
+	prefixes := cleanPostalList(req.PostalPrefixes)
+	if len(req.Polygon) > 0 && len(req.Polygon) < 3 {
+		return ErrInvalidDeliveryZone
+	}
+	if len(codes) == 0 && len(prefixes) == 0 && len(req.Polygon) == 0 {
+		return ErrInvalidDeliveryZone
+	}
+	if req.Country != "" {
+		country, err := postal.NormalizeCountry(req.Country)
+		if err != nil {
+			return ErrInvalidCountry
+		}
+		zone.Country = country
+	} else {
+		zone.Country = ""
+	}
+	zone.Name = strings.TrimSpace(req.Name)
+	zone.PostalCodes = codes
+	zone.PostalPrefixes = prefixes
+	zone.Polygon = domain.GeoPolygon(req.Polygon)
+	zone.Fee = req.Fee
+	zone.MinOrderAmount = req.MinOrderAmount
+	zone.Priority = req.Priority
+	if req.Active != nil {
+		zone.Active = *req.Active
+	}
+	return nil
+}
+func cleanPostalList(values []string) domain.StringList {
+	list := domain.StringList{}
+	for _, v := range values {
+		if c := domain.CompactPostalCode(v); c != "" && !list.Contains(c) {
+			list = append(list, c)
+		}
+	}
+	return list
+}
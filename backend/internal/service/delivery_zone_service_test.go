@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockDeliveryZoneRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryZoneRepository) Create(ctx context.Context, zone *domain.DeliveryZone) error {
+	args := m.Called(zone)
+	return args.Error(0)
+}
+func (m *MockDeliveryZoneRepository) GetByID(ctx context.Context, id uint) (*domain.DeliveryZone, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DeliveryZone), args.Error(1)
+}
+func (m *MockDeliveryZoneRepository) List(ctx context.Context) ([]*domain.DeliveryZone, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.DeliveryZone), args.Error(1)
+}
+func (m *MockDeliveryZoneRepository) ListActive(ctx context.Context) ([]*domain.DeliveryZone, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.DeliveryZone), args.Error(1)
+}
+func (m *MockDeliveryZoneRepository) Update(ctx context.Context, zone *domain.DeliveryZone) error {
+	args := m.Called(zone)
+	return args.Error(0)
+}
+func (m *MockDeliveryZoneRepository) Delete(ctx context.Context, id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type DeliveryZoneServiceTestSuite struct {
+	suite.Suite
+	zoneService service.DeliveryZoneService
+	mockRepo    *MockDeliveryZoneRepository
+}
+
+func testDeliveryZones() []*domain.DeliveryZone {
+	return []*domain.DeliveryZone{
+		{ID: 1, Name: "Manhattan", Country: "US", PostalPrefixes: domain.StringList{"100"}, Fee: 4.99, MinOrderAmount: 20},
+		{ID: 2, Name: "Midtown", Country: "US", PostalPrefixes: domain.StringList{"1001"}, Fee: 2.99},
+		{ID: 3, Name: "Flagship block", PostalCodes: domain.StringList{"10018"}, Fee: 0},
+		{ID: 4, Name: "Downtown Toronto", Country: "CA", Polygon: domain.GeoPolygon{{-79.40, 43.63}, {-79.36, 43.63}, {-79.36, 43.67}, {-79.40, 43.67}}, Fee: 6},
+	}
+}
+func (suite *DeliveryZoneServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockDeliveryZoneRepository)
+	suite.zoneService = service.NewDeliveryZoneService(suite.mockRepo)
+	suite.mockRepo.On("ListActive").Return(testDeliveryZones(), nil).Maybe()
+}
+func (suite *DeliveryZoneServiceTestSuite) TestQuote_LongestPrefixWins() {
+	quote, err := suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "10016", Country: "us"})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), quote.Deliverable)
+	assert.Equal(suite.T(), "Midtown", quote.ZoneName)
+	assert.Equal(suite.T(), 2.99, quote.Fee)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestQuote_ExactCodeWins() {
+	quote, err := suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "10018"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(3), *quote.ZoneID)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestQuote_MinimumOrder() {
+	subtotal := 12.5
+	quote, err := suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "10001", Subtotal: &subtotal})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Manhattan", quote.ZoneName)
+	assert.False(suite.T(), *quote.MeetsMinimum)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestQuote_Polygon() {
+	lat, lng := 43.65, -79.38
+	quote, err := suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "M5H 2N2", Country: "CA", Latitude: &lat, Longitude: &lng})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), quote.Deliverable)
+	assert.Equal(suite.T(), "Downtown Toronto", quote.ZoneName)
+	lat = 45.0
+	quote, err = suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "M5H 2N2", Country: "CA", Latitude: &lat, Longitude: &lng})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), quote.Deliverable)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestQuote_OutOfZone() {
+	quote, err := suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "94105", Country: "US"})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), quote.Deliverable)
+	assert.Nil(suite.T(), quote.ZoneID)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestQuote_CountryMismatch() {
+	quote, err := suite.zoneService.Quote(context.Background(), &service.DeliveryQuoteRequest{PostalCode: "10016", Country: "DE"})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), quote.Deliverable)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestCreateZone_RequiresArea() {
+	zone, err := suite.zoneService.CreateZone(context.Background(), &service.DeliveryZoneRequest{Name: "Empty", Polygon: [][2]float64{{0, 0}, {1, 1}}})
+	assert.Nil(suite.T(), zone)
+	assert.Equal(suite.T(), service.ErrInvalidDeliveryZone, err)
+}
+func (suite *DeliveryZoneServiceTestSuite) TestCreateZone_NormalizesCodes() {
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.DeliveryZone")).Return(nil)
+	zone, err := suite.zoneService.CreateZone(context.Background(), &service.DeliveryZoneRequest{
+		Name:           "Central London",
+		Country:        "gb",
+		PostalPrefixes: []string{"ec1", "EC1", " wc2 "},
+		Fee:            3,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "GB", zone.Country)
+	assert.Equal(suite.T(), domain.StringList{"EC1", "WC2"}, zone.PostalPrefixes)
+	assert.True(suite.T(), zone.Active)
+}
+func TestDeliveryZoneServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(DeliveryZoneServiceTestSuite))
+}
@@ -1,11 +1,60 @@
 package service
 import "errors"
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrEmailExists        = errors.New("email already exists")
-	ErrInvalidInput       = errors.New("invalid input")
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrOrderNotFound      = errors.New("order not found")
-	ErrInvalidOrderStatus = errors.New("invalid order status")
-	ErrUnauthorizedAccess = errors.New("unauthorized to access this order")
+	ErrUserNotFound                   = errors.New("user not found")
+	ErrEmailExists                    = errors.New("email already exists")
+	ErrInvalidInput                   = errors.New("invalid input")
+	ErrInvalidCredentials             = errors.New("invalid email or password")
+	ErrOrderNotFound                  = errors.New("order not found")
+	ErrInvalidOrderStatus             = errors.New("invalid order status")
+	ErrUnauthorizedAccess             = errors.New("unauthorized to access this order")
+	ErrInvalidCursor                  = errors.New("invalid pagination cursor")
+	ErrInvalidCancelReason            = errors.New("invalid cancellation reason code")
+	ErrOrderAlreadyCancelled          = errors.New("order is already cancelled")
+	ErrOrderNotCancellable            = errors.New("completed orders cannot be cancelled")
+	ErrCancellationWindowOver         = errors.New("cancellation window has closed for this order")
+	ErrWebhookNotFound                = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound        = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryInProgress      = errors.New("webhook delivery is already queued or being sent")
+	ErrInvalidWebhookURL              = errors.New("webhook url must be an absolute https url on a public host")
+	ErrWebhookAddressBlocked          = errors.New("webhook host resolves to a private, loopback or link-local address")
+	ErrInvalidWebhookEvent            = errors.New("unsupported webhook event type")
+	ErrPrescriptionNotApproved        = errors.New("order requires an approved prescription before processing")
+	ErrProductNotAllowed              = errors.New("order contains a product that cannot be sold")
+	ErrStatusChangeNotAllowed         = errors.New("only pharmacy staff can change an order's status other than cancelling it")
+	ErrPrescriptionNotFound           = errors.New("prescription not found")
+	ErrPrescriptionTooLarge           = errors.New("prescription file exceeds the maximum allowed size")
+	ErrUnsupportedFileType            = errors.New("prescription must be a JPEG, PNG, WebP or PDF file")
+	ErrPrescriptionNotRequired        = errors.New("order does not require a prescription")
+	ErrPrescriptionReviewed           = errors.New("prescription has already been reviewed")
+	ErrInvalidReviewDecision          = errors.New("review decision must be approved or rejected")
+	ErrAddressNotFound                = errors.New("address not found")
+	ErrInvalidAddress                 = errors.New("address requires line1, city, postal_code and country, and latitude and longitude together")
+	ErrInvalidCountry                 = errors.New("country must be an ISO 3166-1 alpha-2 code")
+	ErrInvalidPostalCode              = errors.New("invalid postal code for country")
+	ErrDeliveryZoneNotFound           = errors.New("delivery zone not found")
+	ErrInvalidDeliveryZone            = errors.New("delivery zone needs postal codes, postal prefixes or a polygon of at least 3 points")
+	ErrPostalCodeRequired             = errors.New("a postal code is required for delivery orders")
+	ErrOutOfDeliveryZone              = errors.New("we do not deliver to this postal code")
+	ErrBelowMinimumOrder              = errors.New("order total is below the minimum for delivery to this area")
+	ErrStoreNotFound                  = errors.New("store not found")
+	ErrPickupNotConfigured            = errors.New("pickup is not available at this store")
+	ErrInvalidPickupConfig            = errors.New("pickup hours must use HH:MM with close after open and weekday 0-6")
+	ErrInvalidPickupSlot              = errors.New("requested pickup slot is not offered by this store")
+	ErrPickupSlotFull                 = errors.New("requested pickup slot is fully booked")
+	ErrPickupStoreRequired            = errors.New("store_id is required when choosing a pickup slot")
+	ErrCheckInNotAllowed              = errors.New("only open curbside orders can check in")
+	ErrAlreadyCheckedIn               = errors.New("customer has already checked in for this order")
+	ErrInvalidTimezone                = errors.New("timezone must be an IANA zone such as America/New_York")
+	ErrInvalidOpeningHours            = errors.New("hours must use HH:MM with close after open and weekday 0-6")
+	ErrDeliveryPreferenceNotSupported = errors.New("this store does not offer the requested delivery preference")
+	ErrNotStaff                       = errors.New("only pharmacists and admins can be assigned to a store")
+	ErrAINotConfigured                = errors.New("ai provider is not configured")
+	ErrAIQuotaExceeded                = errors.New("daily AI suggestion quota exceeded")
+	ErrSuggestionSessionNotFound      = errors.New("ai suggestion session not found")
+	ErrSuggestionSessionUsed          = errors.New("ai suggestion session is already linked to an order")
+	ErrInvalidAIPriceTable            = errors.New("ai price table entries must look like model=input:output (USD per million tokens)")
+	ErrPromptTemplateNotFound         = errors.New("prompt template not found")
+	ErrPromptTemplateRetired          = errors.New("only the latest built-in prompt version can be activated")
+	ErrInvalidSKU                     = errors.New("sku must be 1-64 characters")
 )
@@ -2,9 +2,10 @@ package service
 
 import (
 	"errors"
-	"log"
+	"log/slog"
 	"time"
 	"weel-backend/config"
+	"weel-backend/internal/domain"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -15,8 +16,9 @@ var (
 )
 
 type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID uint            `json:"user_id"`
+	Email  string          `json:"email"`
+	Role   domain.UserRole `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 type JWTService struct {
@@ -27,7 +29,7 @@ type JWTService struct {
 func NewJWTService() *JWTService {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Println("Warning: Failed to load config, using default JWT secret")
+		slog.Warn("failed to load config, using default JWT secret", "error", err)
 		return &JWTService{
 			secretKey: []byte("default-secret-change-in-production"),
 			expiresIn: 24 * time.Hour,
@@ -35,7 +37,7 @@ func NewJWTService() *JWTService {
 	}
 	secret := cfg.JWT.Secret
 	if secret == "" {
-		log.Println("Warning: JWT_SECRET not set, using default")
+		slog.Warn("JWT_SECRET not set, using default")
 		secret = "default-secret-change-in-production"
 	}
 	return &JWTService{
@@ -43,10 +45,11 @@ func NewJWTService() *JWTService {
 		expiresIn: 24 * time.Hour,
 	}
 }
-func (s *JWTService) GenerateToken(userID uint, email string) (string, error) {
+func (s *JWTService) GenerateToken(userID uint, email string, role domain.UserRole) (string, error) {
 	claims := JWTClaims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -1,59 +1,252 @@
 package service
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/events"
+	"weel-backend/internal/guardrail"
+	"weel-backend/internal/pagination"
 	"weel-backend/internal/repository"
+	"weel-backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 type OrderService interface {
-	GetAISuggestions(req *GetAISuggestionsRequest) ([]domain.AISuggestedProduct, error)
-	CreateOrder(userID uint, req *CreateOrderRequest) (*domain.Order, error)
-	GetOrders(userID uint, filters *GetOrdersFilters) ([]*domain.Order, error)
-	GetOrderByID(orderID, userID uint) (*domain.Order, error)
-	UpdateOrder(orderID, userID uint, req *UpdateOrderRequest) (*domain.Order, error)
+	GetAISuggestions(ctx context.Context, userID uint, req *GetAISuggestionsRequest) (*AISuggestionsResult, error)
+	CreateOrder(ctx context.Context, userID uint, req *CreateOrderRequest) (*domain.Order, error)
+	GetOrders(ctx context.Context, userID uint, filters *GetOrdersFilters) ([]*domain.Order, *pagination.Page, error)
+	GetOrderByID(ctx context.Context, orderID, userID uint) (*domain.Order, error)
+	UpdateOrder(ctx context.Context, orderID, userID uint, role domain.UserRole, req *UpdateOrderRequest) (*domain.Order, error)
+	CancelOrder(ctx context.Context, orderID, userID uint, req *CancelOrderRequest) (*domain.Order, error)
+	CheckIn(ctx context.Context, orderID, userID uint, req *CheckInRequest) (*domain.Order, error)
 }
 type GetAISuggestionsRequest struct {
 	Summary         string  `json:"summary" binding:"required,min=10"`
 	DeliveryAddress *string `json:"delivery_address,omitempty"`
 }
+type AISuggestionsResult struct {
+	SessionID      *uint                       `json:"session_id,omitempty"`
+	Suggestions    []domain.AISuggestedProduct `json:"suggestions"`
+	Count          int                         `json:"count"`
+	Quota          *domain.AIQuotaStatus       `json:"quota,omitempty"`
+	Warnings       []domain.SuggestionWarning  `json:"warnings"`
+	RequiresReview bool                        `json:"requires_review"`
+}
+type SuggestionGuardrail interface {
+	Check(summary string, products []domain.AISuggestedProduct) guardrail.Result
+}
 type CreateOrderRequest struct {
-	Summary            string                       `json:"summary" binding:"required,min=10"`
-	DeliveryPreference domain.DeliveryPreference    `json:"delivery_preference" binding:"required,oneof=IN_STORE DELIVERY CURBSIDE"`
-	DeliveryAddress    *string                      `json:"delivery_address,omitempty"`
-	PostalCode         *string                      `json:"postal_code,omitempty"`
-	SelectedProducts   *[]domain.AISuggestedProduct `json:"selected_products,omitempty"`
+	Summary              string                       `json:"summary" binding:"required,min=10"`
+	DeliveryPreference   domain.DeliveryPreference    `json:"delivery_preference" binding:"required,oneof=IN_STORE DELIVERY CURBSIDE"`
+	DeliveryAddress      *string                      `json:"delivery_address,omitempty"`
+	PostalCode           *string                      `json:"postal_code,omitempty"`
+	SelectedProducts     *[]domain.AISuggestedProduct `json:"selected_products,omitempty"`
+	AddressID            *uint                        `json:"address_id,omitempty"`
+	Address              *AddressInput                `json:"address,omitempty"`
+	PrescriptionRequired bool                         `json:"prescription_required,omitempty"`
+	StoreID              *uint                        `json:"store_id,omitempty"`
+	PickupSlot           *time.Time                   `json:"pickup_slot,omitempty"`
+	SuggestionSessionID  *uint                        `json:"suggestion_session_id,omitempty"`
 }
 type GetOrdersFilters struct {
 	Status             *string `form:"status"`
 	DeliveryPreference *string `form:"delivery_preference"`
+	CreatedAfter       string  `form:"created_after"`
+	CreatedBefore      string  `form:"created_before"`
+	PostalCode         string  `form:"postal_code"`
+	Query              string  `form:"q"`
+	Sort               string  `form:"sort"`
 	SortBy             string  `form:"sort_by"`
 	SortOrder          string  `form:"sort_order"`
+	Cursor             string  `form:"cursor"`
 	Limit              int     `form:"limit"`
-	Offset             int     `form:"offset"`
+	IncludeTotal       bool    `form:"include_total"`
+}
+type InvalidSortError struct {
+	Field   string
+	Allowed []string
+}
+func (e *InvalidSortError) Error() string {
+	return fmt.Sprintf("cannot sort by %q; allowed sort fields: %s", e.Field, strings.Join(e.Allowed, ", "))
+}
+type InvalidFilterError struct {
+	Param  string
+	Value  string
+	Reason string
+}
+func (e *InvalidFilterError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Param, e.Value, e.Reason)
 }
 type UpdateOrderRequest struct {
 	Status              *domain.OrderStatus          `json:"status,omitempty"`
 	AISuggestedProducts *[]domain.AISuggestedProduct `json:"ai_suggested_products,omitempty"`
 }
+type CheckInRequest struct {
+	Note string `json:"note,omitempty" binding:"max=500"`
+}
+type CancelOrderRequest struct {
+	ReasonCode domain.CancellationReason `json:"reason_code" binding:"required"`
+	Note       string                    `json:"note,omitempty" binding:"max=1000"`
+}
+const DefaultCancellationCutoff = 30 * time.Minute
 type orderService struct {
-	orderRepo repository.OrderRepository
-	aiService AIService
+	orderRepo          repository.OrderRepository
+	aiService          AIService
+	publisher          events.Publisher
+	addressRepo        repository.AddressRepository
+	deliveryQuoter     DeliveryQuoter
+	pickupScheduler    PickupScheduler
+	storeDirectory     StoreDirectory
+	suggestionQuota    AIQuotaService
+	suggestionRepo     repository.AISuggestionRepository
+	guardrail          SuggestionGuardrail
+	cancellationCutoff time.Duration
+}
+type OrderServiceOption func(*orderService)
+func WithEventPublisher(publisher events.Publisher) OrderServiceOption {
+	return func(s *orderService) {
+		s.publisher = publisher
+	}
+}
+func WithAddressBook(addressRepo repository.AddressRepository) OrderServiceOption {
+	return func(s *orderService) {
+		s.addressRepo = addressRepo
+	}
+}
+func WithDeliveryQuoter(quoter DeliveryQuoter) OrderServiceOption {
+	return func(s *orderService) {
+		s.deliveryQuoter = quoter
+	}
+}
+func WithStoreDirectory(directory StoreDirectory) OrderServiceOption {
+	return func(s *orderService) {
+		s.storeDirectory = directory
+	}
+}
+func WithPickupScheduler(scheduler PickupScheduler) OrderServiceOption {
+	return func(s *orderService) {
+		s.pickupScheduler = scheduler
+	}
+}
+func WithSuggestionQuota(quota AIQuotaService) OrderServiceOption {
+	return func(s *orderService) {
+		s.suggestionQuota = quota
+	}
+}
+func WithSuggestionSessions(suggestionRepo repository.AISuggestionRepository) OrderServiceOption {
+	return func(s *orderService) {
+		s.suggestionRepo = suggestionRepo
+	}
+}
+func WithGuardrail(checker SuggestionGuardrail) OrderServiceOption {
+	return func(s *orderService) {
+		s.guardrail = checker
+	}
 }
-func NewOrderService(orderRepo repository.OrderRepository, aiService AIService) OrderService {
-	return &orderService{
-		orderRepo: orderRepo,
-		aiService: aiService,
+func WithCancellationCutoff(cutoff time.Duration) OrderServiceOption {
+	return func(s *orderService) {
+		s.cancellationCutoff = cutoff
 	}
 }
-func (s *orderService) GetAISuggestions(req *GetAISuggestionsRequest) ([]domain.AISuggestedProduct, error) {
+func NewOrderService(orderRepo repository.OrderRepository, aiService AIService, opts ...OrderServiceOption) OrderService {
+	s := &orderService{
+		orderRepo:          orderRepo,
+		aiService:          aiService,
+		cancellationCutoff: DefaultCancellationCutoff,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+func (s *orderService) publish(ctx context.Context, eventType string, order *domain.Order) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(ctx, events.NewOrderEvent(eventType, order))
+}
+func (s *orderService) GetAISuggestions(ctx context.Context, userID uint, req *GetAISuggestionsRequest) (*AISuggestionsResult, error) {
+	result := &AISuggestionsResult{Suggestions: []domain.AISuggestedProduct{}, Warnings: []domain.SuggestionWarning{}}
 	if s.aiService == nil {
-		return []domain.AISuggestedProduct{}, nil
+		return result, nil
+	}
+	if s.suggestionQuota != nil {
+		quota, err := s.suggestionQuota.Consume(ctx, userID)
+		result.Quota = quota
+		if err != nil {
+			return result, err
+		}
 	}
-	products, err := s.aiService.SuggestProducts(req.Summary, req.DeliveryAddress)
+	suggestions, err := s.aiService.SuggestProducts(ctx, userID, req.Summary, req.DeliveryAddress)
 	if err != nil {
+		if suggestions == nil {
+			s.refundSuggestion(ctx, userID, result.Quota)
+			return nil, err
+		}
+		if suggestions.RawOutput != "" {
+			s.saveSuggestionSession(ctx, userID, req, suggestions, result)
+		}
 		return nil, err
 	}
-	return products, nil
+	if s.guardrail != nil {
+		checked := s.guardrail.Check(req.Summary, suggestions.Products)
+		suggestions.Products = checked.Products
+		result.Warnings = checked.Warnings
+		result.RequiresReview = checked.RequiresReview
+	}
+	result.SessionID = s.saveSuggestionSession(ctx, userID, req, suggestions, result)
+	result.Suggestions = suggestions.Products
+	result.Count = len(suggestions.Products)
+	return result, nil
 }
-func (s *orderService) CreateOrder(userID uint, req *CreateOrderRequest) (*domain.Order, error) {
+func (s *orderService) saveSuggestionSession(ctx context.Context, userID uint, req *GetAISuggestionsRequest, suggestions *ProductSuggestions, result *AISuggestionsResult) *uint {
+	if s.suggestionRepo == nil {
+		return nil
+	}
+	session := &domain.AISuggestionSession{
+		UserID:          userID,
+		Summary:         req.Summary,
+		DeliveryAddress: req.DeliveryAddress,
+		Model:           suggestions.Model,
+		PromptName:      suggestions.PromptName,
+		PromptVersion:   suggestions.PromptVersion,
+		RawOutput:       suggestions.RawOutput,
+		Products:        suggestions.Products,
+		Warnings:        result.Warnings,
+		RequiresReview:  result.RequiresReview,
+	}
+	if err := s.suggestionRepo.Create(ctx, session); err != nil {
+		slog.ErrorContext(ctx, "failed to save ai suggestion session", "user_id", userID, "error", err)
+		return nil
+	}
+	return &session.ID
+}
+func (s *orderService) refundSuggestion(ctx context.Context, userID uint, quota *domain.AIQuotaStatus) {
+	if quota == nil {
+		return
+	}
+	if err := s.suggestionQuota.Refund(ctx, userID); err != nil {
+		slog.WarnContext(ctx, "failed to refund ai suggestion quota", "user_id", userID, "error", err)
+		return
+	}
+	quota.Used--
+	quota.Remaining++
+}
+func (s *orderService) CreateOrder(ctx context.Context, userID uint, req *CreateOrderRequest) (*domain.Order, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "OrderService.CreateOrder",
+		trace.WithAttributes(attribute.String("weel.order.delivery_preference", string(req.DeliveryPreference))))
+	order, err := s.createOrder(ctx, userID, req)
+	if order != nil {
+		span.SetAttributes(attribute.Int64("weel.order.id", int64(order.ID)))
+	}
+	tracing.End(span, err)
+	return order, err
+}
+func (s *orderService) createOrder(ctx context.Context, userID uint, req *CreateOrderRequest) (*domain.Order, error) {
 	if req.Summary == "" {
 		return nil, ErrInvalidInput
 	}
@@ -72,11 +265,6 @@ func (s *orderService) CreateOrder(userID uint, req *CreateOrderRequest) (*domai
 	if !validPreference {
 		return nil, ErrInvalidInput
 	}
-	if req.DeliveryPreference == domain.DeliveryPreferenceDelivery {
-		if req.DeliveryAddress == nil || *req.DeliveryAddress == "" {
-			return nil, ErrInvalidInput
-		}
-	}
 	order := &domain.Order{
 		UserID:             userID,
 		Summary:            req.Summary,
@@ -84,36 +272,258 @@ func (s *orderService) CreateOrder(userID uint, req *CreateOrderRequest) (*domai
 		DeliveryAddress:    req.DeliveryAddress,
 		PostalCode:         req.PostalCode,
 		Status:             domain.OrderStatusPending,
+		StoreID:            req.StoreID,
+	}
+	if s.storeDirectory != nil {
+		store, err := s.storeDirectory.ResolveStore(ctx, req.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		if store != nil {
+			if !store.Supports(order.DeliveryPreference) {
+				return nil, ErrDeliveryPreferenceNotSupported
+			}
+			order.StoreID = &store.ID
+		}
+	}
+	if req.DeliveryPreference == domain.DeliveryPreferenceDelivery {
+		snapshot, addressID, err := s.resolveDeliveryAddress(ctx, userID, req)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			formatted := snapshot.Format()
+			postalCode := snapshot.PostalCode
+			order.AddressID = addressID
+			order.ShippingAddress = snapshot
+			order.DeliveryAddress = &formatted
+			order.PostalCode = &postalCode
+		}
 	}
 	if req.SelectedProducts != nil && len(*req.SelectedProducts) > 0 {
-		if err := order.SetAISuggestedProducts(*req.SelectedProducts); err != nil {
+		products, err := s.checkOrderProducts(order, *req.SelectedProducts)
+		if err != nil {
+			return nil, err
+		}
+		if err := order.SetAISuggestedProducts(products); err != nil {
 			return nil, ErrInvalidInput
 		}
 	}
-	if err := s.orderRepo.Create(order); err != nil {
+	if req.PrescriptionRequired {
+		requirePrescription(order)
+	}
+	session, err := s.resolveSuggestionSession(ctx, userID, req.SuggestionSessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session != nil {
+		order.SuggestionSessionID = &session.ID
+		if session.RequiresReview {
+			requirePrescription(order)
+		}
+	}
+	if order.DeliveryPreference == domain.DeliveryPreferenceDelivery && s.deliveryQuoter != nil {
+		if err := s.applyDeliveryQuote(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	if req.PickupSlot != nil {
+		if err := s.reservePickupSlot(ctx, order, *req.PickupSlot); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		s.releasePickupSlot(ctx, order)
 		return nil, err
 	}
+	if session != nil {
+		s.linkSuggestionSession(ctx, session, order, req.SelectedProducts)
+	}
+	s.publish(ctx, events.OrderCreated, order)
 	return order, nil
 }
-func (s *orderService) GetOrders(userID uint, filters *GetOrdersFilters) ([]*domain.Order, error) {
+func (s *orderService) checkOrderProducts(order *domain.Order, products []domain.AISuggestedProduct) ([]domain.AISuggestedProduct, error) {
+	if s.guardrail == nil || len(products) == 0 {
+		return products, nil
+	}
+	checked := s.guardrail.Check(order.Summary, products)
+	for _, w := range checked.Warnings {
+		if w.Action == domain.GuardrailActionBlocked {
+			return nil, fmt.Errorf("%w: %s", ErrProductNotAllowed, w.Message)
+		}
+	}
+	if checked.PrescriptionRequired || checked.RequiresReview {
+		requirePrescription(order)
+	}
+	return checked.Products, nil
+}
+func requirePrescription(order *domain.Order) {
+	if order.PrescriptionStatus == nil {
+		status := domain.PrescriptionStatusRequired
+		order.PrescriptionStatus = &status
+	}
+}
+func (s *orderService) resolveSuggestionSession(ctx context.Context, userID uint, sessionID *uint) (*domain.AISuggestionSession, error) {
+	if sessionID == nil || s.suggestionRepo == nil {
+		return nil, nil
+	}
+	session, err := s.suggestionRepo.GetByID(ctx, *sessionID)
+	if err != nil || session.UserID != userID {
+		return nil, ErrSuggestionSessionNotFound
+	}
+	if session.OrderID != nil {
+		return nil, ErrSuggestionSessionUsed
+	}
+	return session, nil
+}
+func (s *orderService) linkSuggestionSession(ctx context.Context, session *domain.AISuggestionSession, order *domain.Order, selected *[]domain.AISuggestedProduct) {
+	accepted := 0
+	if selected != nil {
+		accepted = session.CountAccepted(*selected)
+	}
+	linked, err := s.suggestionRepo.LinkOrder(ctx, session.ID, order.ID, accepted)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to link ai suggestion session to order", "session_id", session.ID, "order_id", order.ID, "error", err)
+		return
+	}
+	if !linked {
+		slog.WarnContext(ctx, "ai suggestion session was already linked to another order", "session_id", session.ID, "order_id", order.ID)
+	}
+}
+func (s *orderService) resolveDeliveryAddress(ctx context.Context, userID uint, req *CreateOrderRequest) (*domain.AddressSnapshot, *uint, error) {
+	if req.AddressID != nil {
+		if s.addressRepo == nil {
+			return nil, nil, ErrAddressNotFound
+		}
+		address, err := s.addressRepo.GetByID(ctx, *req.AddressID)
+		if err != nil || address.UserID != userID {
+			return nil, nil, ErrAddressNotFound
+		}
+		return address.Snapshot(), &address.ID, nil
+	}
+	if req.Address != nil {
+		normalized, err := req.Address.normalize()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &domain.AddressSnapshot{PostalAddress: *normalized}, nil, nil
+	}
+	if req.DeliveryAddress == nil || *req.DeliveryAddress == "" {
+		return nil, nil, ErrInvalidInput
+	}
+	return nil, nil, nil
+}
+func (s *orderService) applyDeliveryQuote(ctx context.Context, order *domain.Order) error {
+	if order.PostalCode == nil || strings.TrimSpace(*order.PostalCode) == "" {
+		return ErrPostalCodeRequired
+	}
+	req := &DeliveryQuoteRequest{PostalCode: *order.PostalCode, Subtotal: &order.Total}
+	if order.ShippingAddress != nil {
+		req.Country = order.ShippingAddress.Country
+		req.Latitude = order.ShippingAddress.Latitude
+		req.Longitude = order.ShippingAddress.Longitude
+	}
+	quote, err := s.deliveryQuoter.Quote(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !quote.Deliverable {
+		return ErrOutOfDeliveryZone
+	}
+	if quote.MeetsMinimum != nil && !*quote.MeetsMinimum {
+		return ErrBelowMinimumOrder
+	}
+	order.DeliveryZoneID = quote.ZoneID
+	order.DeliveryFee = quote.Fee
+	return nil
+}
+func (s *orderService) reservePickupSlot(ctx context.Context, order *domain.Order, start time.Time) error {
+	if order.DeliveryPreference == domain.DeliveryPreferenceDelivery {
+		return ErrInvalidPickupSlot
+	}
+	if order.StoreID == nil {
+		return ErrPickupStoreRequired
+	}
+	if s.pickupScheduler == nil {
+		return ErrPickupNotConfigured
+	}
+	slot, err := s.pickupScheduler.Reserve(ctx, *order.StoreID, start)
+	if err != nil {
+		return err
+	}
+	order.PickupSlotID = &slot.ID
+	order.PickupSlotStart = &slot.StartsAt
+	order.PickupSlotEnd = &slot.EndsAt
+	return nil
+}
+func (s *orderService) releasePickupSlot(ctx context.Context, order *domain.Order) {
+	if order.PickupSlotID == nil || s.pickupScheduler == nil {
+		return
+	}
+	if err := s.pickupScheduler.Release(ctx, *order.PickupSlotID); err != nil {
+		slog.WarnContext(ctx, "failed to release pickup slot", "slot_id", *order.PickupSlotID, "order_id", order.ID, "error", err)
+	}
+}
+func (s *orderService) GetOrders(ctx context.Context, userID uint, filters *GetOrdersFilters) ([]*domain.Order, *pagination.Page, error) {
 	if filters == nil {
 		filters = &GetOrdersFilters{}
 	}
-	if filters.Limit == 0 {
-		filters.Limit = 100
+	sortFields, err := parseOrderSort(filters)
+	if err != nil {
+		return nil, nil, err
 	}
 	repoFilters := repository.OrderFilters{
-		Status:             filters.Status,
-		DeliveryPreference: filters.DeliveryPreference,
-		SortBy:             filters.SortBy,
-		SortOrder:          filters.SortOrder,
-		Limit:              filters.Limit,
-		Offset:             filters.Offset,
+		PostalCodePrefix: strings.TrimSpace(filters.PostalCode),
+		Search:           strings.TrimSpace(filters.Query),
+		Sort:             sortFields,
+		Cursor:           filters.Cursor,
+		Limit:            filters.Limit,
+		IncludeTotal:     filters.IncludeTotal,
+	}
+	if filters.Status != nil {
+		for _, status := range splitFilterValues(*filters.Status) {
+			if !isValidOrderStatus(domain.OrderStatus(status)) {
+				return nil, nil, &InvalidFilterError{Param: "status", Value: status, Reason: "unknown order status"}
+			}
+			repoFilters.Statuses = append(repoFilters.Statuses, status)
+		}
+	}
+	if filters.DeliveryPreference != nil {
+		for _, pref := range splitFilterValues(*filters.DeliveryPreference) {
+			if !isValidDeliveryPreference(domain.DeliveryPreference(pref)) {
+				return nil, nil, &InvalidFilterError{Param: "delivery_preference", Value: pref, Reason: "unknown delivery preference"}
+			}
+			repoFilters.DeliveryPreferences = append(repoFilters.DeliveryPreferences, pref)
+		}
+	}
+	if filters.CreatedAfter != "" {
+		t, err := parseFilterTime(filters.CreatedAfter)
+		if err != nil {
+			return nil, nil, &InvalidFilterError{Param: "created_after", Value: filters.CreatedAfter, Reason: "expected RFC 3339 timestamp or YYYY-MM-DD date"}
+		}
+		repoFilters.CreatedAfter = &t
+	}
+	if filters.CreatedBefore != "" {
+		t, err := parseFilterTime(filters.CreatedBefore)
+		if err != nil {
+			return nil, nil, &InvalidFilterError{Param: "created_before", Value: filters.CreatedBefore, Reason: "expected RFC 3339 timestamp or YYYY-MM-DD date"}
+		}
+		repoFilters.CreatedBefore = &t
+	}
+	if repoFilters.CreatedAfter != nil && repoFilters.CreatedBefore != nil && !repoFilters.CreatedAfter.Before(*repoFilters.CreatedBefore) {
+		return nil, nil, &InvalidFilterError{Param: "created_after", Value: filters.CreatedAfter, Reason: "must be earlier than created_before"}
 	}
-	return s.orderRepo.GetByUserIDWithFilters(userID, repoFilters)
+	orders, page, err := s.orderRepo.GetByUserIDWithFilters(ctx, userID, repoFilters)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return nil, nil, ErrInvalidCursor
+		}
+		return nil, nil, err
+	}
+	return orders, page, nil
 }
-func (s *orderService) GetOrderByID(orderID, userID uint) (*domain.Order, error) {
-	order, err := s.orderRepo.GetByID(orderID)
+func (s *orderService) GetOrderByID(ctx context.Context, orderID, userID uint) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
 		return nil, ErrOrderNotFound
 	}
@@ -122,14 +532,15 @@ func (s *orderService) GetOrderByID(orderID, userID uint) (*domain.Order, error)
 	}
 	return order, nil
 }
-func (s *orderService) UpdateOrder(orderID, userID uint, req *UpdateOrderRequest) (*domain.Order, error) {
-	order, err := s.orderRepo.GetByID(orderID)
+func (s *orderService) UpdateOrder(ctx context.Context, orderID, userID uint, role domain.UserRole, req *UpdateOrderRequest) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
 	if err != nil {
 		return nil, ErrOrderNotFound
 	}
-	if order.UserID != userID {
-		return nil, ErrUnauthorizedAccess
+	if err := s.authorizeOrderUpdate(ctx, order, userID, role); err != nil {
+		return nil, err
 	}
+	previousStatus := order.Status
 	if req.Status != nil {
 		validStatuses := []domain.OrderStatus{
 			domain.OrderStatusPending,
@@ -147,15 +558,191 @@ func (s *orderService) UpdateOrder(orderID, userID uint, req *UpdateOrderRequest
 		if !valid {
 			return nil, ErrInvalidOrderStatus
 		}
+		changed := *req.Status != order.Status
+		if changed && *req.Status != domain.OrderStatusCancelled && !role.IsStaff() {
+			return nil, ErrStatusChangeNotAllowed
+		}
+		if changed && (*req.Status == domain.OrderStatusProcessing || *req.Status == domain.OrderStatusCompleted) && order.AwaitingPrescriptionApproval() {
+			return nil, ErrPrescriptionNotApproved
+		}
+		if changed && *req.Status == domain.OrderStatusCancelled {
+			if err := s.applyCancellation(order, domain.CancellationReasonOther, ""); err != nil {
+				return nil, err
+			}
+		}
+		if changed && *req.Status == domain.OrderStatusProcessing {
+			now := time.Now()
+			order.ProcessingStartedAt = &now
+		}
 		order.Status = *req.Status
 	}
 	if req.AISuggestedProducts != nil {
-		if err := order.SetAISuggestedProducts(*req.AISuggestedProducts); err != nil {
+		products, err := s.checkOrderProducts(order, *req.AISuggestedProducts)
+		if err != nil {
+			return nil, err
+		}
+		if err := order.SetAISuggestedProducts(products); err != nil {
 			return nil, ErrInvalidInput
 		}
 	}
-	if err := s.orderRepo.Update(order); err != nil {
+	if err := s.orderRepo.Update(ctx, order); err != nil {
 		return nil, err
 	}
+	s.publish(ctx, events.OrderUpdated, order)
+	if order.Status != previousStatus {
+		s.publish(ctx, events.OrderStatusChanged, order)
+		if order.Status == domain.OrderStatusCancelled {
+			s.releasePickupSlot(ctx, order)
+			s.publish(ctx, events.OrderCancelled, order)
+		}
+	}
 	return order, nil
 }
+func (s *orderService) authorizeOrderUpdate(ctx context.Context, order *domain.Order, userID uint, role domain.UserRole) error {
+	if order.UserID == userID || role == domain.UserRoleAdmin {
+		return nil
+	}
+	if !role.IsStaff() || order.StoreID == nil || s.storeDirectory == nil {
+		return ErrUnauthorizedAccess
+	}
+	storeID, err := s.storeDirectory.StaffStoreID(ctx, userID)
+	if err != nil || storeID == nil || *storeID != *order.StoreID {
+		return ErrUnauthorizedAccess
+	}
+	return nil
+}
+func (s *orderService) CancelOrder(ctx context.Context, orderID, userID uint, req *CancelOrderRequest) (*domain.Order, error) {
+	if !req.ReasonCode.IsValid() {
+		return nil, ErrInvalidCancelReason
+	}
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+	if err := s.applyCancellation(order, req.ReasonCode, req.Note); err != nil {
+		return nil, err
+	}
+	order.Status = domain.OrderStatusCancelled
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	s.releasePickupSlot(ctx, order)
+	s.publish(ctx, events.OrderStatusChanged, order)
+	s.publish(ctx, events.OrderCancelled, order)
+	return order, nil
+}
+func (s *orderService) CheckIn(ctx context.Context, orderID, userID uint, req *CheckInRequest) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+	if order.DeliveryPreference != domain.DeliveryPreferenceCurbside ||
+		order.Status == domain.OrderStatusCancelled || order.Status == domain.OrderStatusCompleted {
+		return nil, ErrCheckInNotAllowed
+	}
+	if order.CustomerArrivedAt != nil {
+		return nil, ErrAlreadyCheckedIn
+	}
+	now := time.Now()
+	order.CustomerArrivedAt = &now
+	if note := strings.TrimSpace(req.Note); note != "" {
+		order.ArrivalNote = &note
+	}
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, events.OrderCustomerArrived, order)
+	return order, nil
+}
+func (s *orderService) applyCancellation(order *domain.Order, reason domain.CancellationReason, note string) error {
+	now := time.Now()
+	switch order.Status {
+	case domain.OrderStatusCancelled:
+		return ErrOrderAlreadyCancelled
+	case domain.OrderStatusCompleted:
+		return ErrOrderNotCancellable
+	case domain.OrderStatusProcessing:
+		started := order.UpdatedAt
+		if order.ProcessingStartedAt != nil {
+			started = *order.ProcessingStartedAt
+		}
+		if now.After(started.Add(s.cancellationCutoff)) {
+			return ErrCancellationWindowOver
+		}
+	}
+	order.CancellationReason = &reason
+	if note != "" {
+		order.CancellationNote = &note
+	}
+	order.CancelledAt = &now
+	return nil
+}
+func parseOrderSort(filters *GetOrdersFilters) ([]repository.SortField, error) {
+	spec := strings.TrimSpace(filters.Sort)
+	if spec == "" && filters.SortBy != "" {
+		spec = filters.SortBy
+		if !strings.EqualFold(filters.SortOrder, "asc") {
+			spec = "-" + spec
+		}
+	}
+	if spec == "" {
+		return nil, nil
+	}
+	allowed := repository.OrderSortFields()
+	seen := make(map[string]bool)
+	var fields []repository.SortField
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+		valid := false
+		for _, a := range allowed {
+			if a == name {
+				valid = true
+				break
+			}
+		}
+		if !valid || seen[name] {
+			return nil, &InvalidSortError{Field: part, Allowed: allowed}
+		}
+		seen[name] = true
+		fields = append(fields, repository.SortField{Field: name, Desc: desc})
+	}
+	return fields, nil
+}
+func splitFilterValues(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+func parseFilterTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+func isValidOrderStatus(status domain.OrderStatus) bool {
+	switch status {
+	case domain.OrderStatusPending, domain.OrderStatusProcessing, domain.OrderStatusCompleted, domain.OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+func isValidDeliveryPreference(pref domain.DeliveryPreference) bool {
+	switch pref {
+	case domain.DeliveryPreferenceInStore, domain.DeliveryPreferenceDelivery, domain.DeliveryPreferenceCurbside:
+		return true
+	}
+	return false
+}
@@ -1,8 +1,14 @@
 package service_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/events"
+	"weel-backend/internal/guardrail"
+	"weel-backend/internal/pagination"
 	"weel-backend/internal/repository"
 	"weel-backend/internal/service"
 
@@ -15,39 +21,143 @@ type MockOrderRepository struct {
 	mock.Mock
 }
 
-func (m *MockOrderRepository) Create(order *domain.Order) error {
+func (m *MockOrderRepository) Create(ctx context.Context, order *domain.Order) error {
 	args := m.Called(order)
 	return args.Error(0)
 }
-func (m *MockOrderRepository) GetByID(id uint) (*domain.Order, error) {
+func (m *MockOrderRepository) GetByID(ctx context.Context, id uint) (*domain.Order, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Order), args.Error(1)
 }
-func (m *MockOrderRepository) GetByUserID(userID uint, limit, offset int) ([]*domain.Order, error) {
+func (m *MockOrderRepository) GetByUserID(ctx context.Context, userID uint, limit, offset int) ([]*domain.Order, error) {
 	args := m.Called(userID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Order), args.Error(1)
 }
-func (m *MockOrderRepository) Update(order *domain.Order) error {
+func (m *MockOrderRepository) Update(ctx context.Context, order *domain.Order) error {
 	args := m.Called(order)
 	return args.Error(0)
 }
-func (m *MockOrderRepository) Delete(id uint) error {
+func (m *MockOrderRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
-func (m *MockOrderRepository) GetByUserIDWithFilters(userID uint, filters repository.OrderFilters) ([]*domain.Order, error) {
-	args := m.Called(userID, filters)
+func (m *MockOrderRepository) ListArrivedAtStore(ctx context.Context, storeID uint) ([]*domain.Order, error) {
+	args := m.Called(storeID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Order), args.Error(1)
 }
+func (m *MockOrderRepository) ListByStore(ctx context.Context, storeID uint, statuses []domain.OrderStatus, limit int) ([]*domain.Order, error) {
+	args := m.Called(storeID, statuses, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Order), args.Error(1)
+}
+func (m *MockOrderRepository) GetByUserIDWithFilters(ctx context.Context, userID uint, filters repository.OrderFilters) ([]*domain.Order, *pagination.Page, error) {
+	args := m.Called(userID, filters)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*domain.Order), args.Get(1).(*pagination.Page), args.Error(2)
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) {
+	p.events = append(p.events, event)
+}
+
+type stubPickupScheduler struct {
+	slot     *domain.PickupSlot
+	err      error
+	reserved []uint
+	released []uint
+}
+
+func (s *stubPickupScheduler) Reserve(ctx context.Context, storeID uint, start time.Time) (*domain.PickupSlot, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.reserved = append(s.reserved, storeID)
+	return s.slot, nil
+}
+func (s *stubPickupScheduler) Release(ctx context.Context, slotID uint) error {
+	s.released = append(s.released, slotID)
+	return nil
+}
+
+type stubStoreDirectory struct {
+	store      *domain.Store
+	err        error
+	staffStore map[uint]uint
+}
+
+func (s *stubStoreDirectory) ResolveStore(ctx context.Context, storeID *uint) (*domain.Store, error) {
+	return s.store, s.err
+}
+func (s *stubStoreDirectory) StaffStoreID(ctx context.Context, userID uint) (*uint, error) {
+	storeID, ok := s.staffStore[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &storeID, nil
+}
+
+type stubAIService struct {
+	products []domain.AISuggestedProduct
+	raw      string
+	err      error
+	calls    int
+}
+
+func (s *stubAIService) SuggestProducts(ctx context.Context, userID uint, summary string, address *string) (*service.ProductSuggestions, error) {
+	s.calls++
+	if s.err != nil && s.raw == "" {
+		return nil, s.err
+	}
+	return &service.ProductSuggestions{Products: s.products, Model: "gpt-4o-mini", RawOutput: s.raw}, s.err
+}
+func (s *stubAIService) Ping(ctx context.Context) error {
+	return nil
+}
+
+type MockAISuggestionRepository struct {
+	mock.Mock
+}
+
+func (m *MockAISuggestionRepository) Create(ctx context.Context, session *domain.AISuggestionSession) error {
+	args := m.Called(session)
+	session.ID = 42
+	return args.Error(0)
+}
+func (m *MockAISuggestionRepository) GetByID(ctx context.Context, id uint) (*domain.AISuggestionSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AISuggestionSession), args.Error(1)
+}
+func (m *MockAISuggestionRepository) LinkOrder(ctx context.Context, id, orderID uint, accepted int) (bool, error) {
+	args := m.Called(id, orderID, accepted)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockAISuggestionRepository) Stats(ctx context.Context, from, to time.Time) (*domain.AISuggestionStats, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AISuggestionStats), args.Error(1)
+}
 
 type OrderServiceTestSuite struct {
 	suite.Suite
@@ -70,7 +180,7 @@ func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_Success() {
 		PostalCode:         &postalCode,
 	}
 	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
-	order, err := suite.orderService.CreateOrder(userID, req)
+	order, err := suite.orderService.CreateOrder(context.Background(), userID, req)
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), order)
 	assert.Equal(suite.T(), userID, order.UserID)
@@ -91,7 +201,7 @@ func (suite *OrderServiceTestSuite) TestCreateOrder_InStore_Success() {
 		PostalCode:         nil,
 	}
 	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
-	order, err := suite.orderService.CreateOrder(userID, req)
+	order, err := suite.orderService.CreateOrder(context.Background(), userID, req)
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), order)
 	assert.Equal(suite.T(), domain.DeliveryPreferenceInStore, order.DeliveryPreference)
@@ -108,7 +218,7 @@ func (suite *OrderServiceTestSuite) TestCreateOrder_Curbside_Success() {
 		PostalCode:         nil,
 	}
 	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
-	order, err := suite.orderService.CreateOrder(userID, req)
+	order, err := suite.orderService.CreateOrder(context.Background(), userID, req)
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), order)
 	assert.Equal(suite.T(), domain.DeliveryPreferenceCurbside, order.DeliveryPreference)
@@ -121,11 +231,122 @@ func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_WithoutAddress() {
 		DeliveryPreference: domain.DeliveryPreferenceDelivery,
 		DeliveryAddress:    nil,
 	}
-	order, err := suite.orderService.CreateOrder(userID, req)
+	order, err := suite.orderService.CreateOrder(context.Background(), userID, req)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), order)
 	assert.Equal(suite.T(), service.ErrInvalidInput, err)
 }
+func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_SavedAddressSnapshot() {
+	addressRepo := new(MockAddressRepository)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithAddressBook(addressRepo))
+	saved := &domain.Address{
+		ID:     5,
+		UserID: 1,
+		Label:  "Office",
+		PostalAddress: domain.PostalAddress{
+			Line1:      "350 Fifth Ave",
+			City:       "New York",
+			Region:     "NY",
+			PostalCode: "10118",
+			Country:    "US",
+		},
+	}
+	addressID := uint(5)
+	addressRepo.On("GetByID", addressID).Return(saved, nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Weekly vitamins and cold medicine",
+		DeliveryPreference: domain.DeliveryPreferenceDelivery,
+		AddressID:          &addressID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &addressID, order.AddressID)
+	assert.Equal(suite.T(), "Office", order.ShippingAddress.Label)
+	assert.Equal(suite.T(), "10118", *order.PostalCode)
+	assert.Equal(suite.T(), "350 Fifth Ave, New York, NY 10118, US", *order.DeliveryAddress)
+	saved.Line1 = "1 Changed Rd"
+	assert.Equal(suite.T(), "350 Fifth Ave", order.ShippingAddress.Line1)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_AddressOfOtherUser() {
+	addressRepo := new(MockAddressRepository)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithAddressBook(addressRepo))
+	addressID := uint(5)
+	addressRepo.On("GetByID", addressID).Return(&domain.Address{ID: 5, UserID: 2}, nil)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Weekly vitamins and cold medicine",
+		DeliveryPreference: domain.DeliveryPreferenceDelivery,
+		AddressID:          &addressID,
+	})
+	assert.Nil(suite.T(), order)
+	assert.Equal(suite.T(), service.ErrAddressNotFound, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_InlineAddressValidated() {
+	order, err := suite.orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Weekly vitamins and cold medicine",
+		DeliveryPreference: domain.DeliveryPreferenceDelivery,
+		Address: &service.AddressInput{
+			Line1:      "10 Downing St",
+			City:       "London",
+			PostalCode: "12345",
+			Country:    "GB",
+		},
+	})
+	assert.Nil(suite.T(), order)
+	assert.Equal(suite.T(), service.ErrInvalidPostalCode, err)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_OutOfZone() {
+	zoneRepo := new(MockDeliveryZoneRepository)
+	zoneRepo.On("ListActive").Return(testDeliveryZones(), nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithDeliveryQuoter(service.NewDeliveryZoneService(zoneRepo)))
+	address := "1 Market St, San Francisco, CA"
+	postalCode := "94105"
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Weekly vitamins and cold medicine",
+		DeliveryPreference: domain.DeliveryPreferenceDelivery,
+		DeliveryAddress:    &address,
+		PostalCode:         &postalCode,
+	})
+	assert.Nil(suite.T(), order)
+	assert.Equal(suite.T(), service.ErrOutOfDeliveryZone, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_AppliesZoneFee() {
+	zoneRepo := new(MockDeliveryZoneRepository)
+	zoneRepo.On("ListActive").Return(testDeliveryZones(), nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithDeliveryQuoter(service.NewDeliveryZoneService(zoneRepo)))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Weekly vitamins and cold medicine",
+		DeliveryPreference: domain.DeliveryPreferenceDelivery,
+		Address: &service.AddressInput{
+			Line1:      "350 Fifth Ave",
+			City:       "New York",
+			PostalCode: "10016",
+			Country:    "US",
+		},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(2), *order.DeliveryZoneID)
+	assert.Equal(suite.T(), 2.99, order.DeliveryFee)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_Delivery_BelowZoneMinimum() {
+	zoneRepo := new(MockDeliveryZoneRepository)
+	zoneRepo.On("ListActive").Return(testDeliveryZones(), nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithDeliveryQuoter(service.NewDeliveryZoneService(zoneRepo)))
+	address := "20 W 34th St, New York, NY"
+	postalCode := "10001"
+	products := []domain.AISuggestedProduct{{Name: "Vitamin C", Quantity: 1, Price: 8.5}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Weekly vitamins and cold medicine",
+		DeliveryPreference: domain.DeliveryPreferenceDelivery,
+		DeliveryAddress:    &address,
+		PostalCode:         &postalCode,
+		SelectedProducts:   &products,
+	})
+	assert.Nil(suite.T(), order)
+	assert.Equal(suite.T(), service.ErrBelowMinimumOrder, err)
+}
 func (suite *OrderServiceTestSuite) TestCreateOrder_EmptySummary() {
 	userID := uint(1)
 	address := "123 Main St"
@@ -134,7 +355,7 @@ func (suite *OrderServiceTestSuite) TestCreateOrder_EmptySummary() {
 		DeliveryPreference: domain.DeliveryPreferenceDelivery,
 		DeliveryAddress:    &address,
 	}
-	order, err := suite.orderService.CreateOrder(userID, req)
+	order, err := suite.orderService.CreateOrder(context.Background(), userID, req)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), order)
 	assert.Equal(suite.T(), service.ErrInvalidInput, err)
@@ -147,7 +368,7 @@ func (suite *OrderServiceTestSuite) TestCreateOrder_InvalidPreference() {
 		DeliveryPreference: domain.DeliveryPreference("INVALID"),
 		DeliveryAddress:    &address,
 	}
-	order, err := suite.orderService.CreateOrder(userID, req)
+	order, err := suite.orderService.CreateOrder(context.Background(), userID, req)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), order)
 	assert.Equal(suite.T(), service.ErrInvalidInput, err)
@@ -160,7 +381,7 @@ func (suite *OrderServiceTestSuite) TestGetOrderByID_Success() {
 		UserID: userID,
 	}
 	suite.mockRepo.On("GetByID", orderID).Return(order, nil)
-	result, err := suite.orderService.GetOrderByID(orderID, userID)
+	result, err := suite.orderService.GetOrderByID(context.Background(), orderID, userID)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), order.ID, result.ID)
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -174,7 +395,7 @@ func (suite *OrderServiceTestSuite) TestGetOrderByID_Unauthorized() {
 		UserID: otherUserID,
 	}
 	suite.mockRepo.On("GetByID", orderID).Return(order, nil)
-	result, err := suite.orderService.GetOrderByID(orderID, userID)
+	result, err := suite.orderService.GetOrderByID(context.Background(), orderID, userID)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), result)
 	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
@@ -194,11 +415,88 @@ func (suite *OrderServiceTestSuite) TestUpdateOrder_Success() {
 	req := &service.UpdateOrderRequest{
 		Status: &status,
 	}
-	result, err := suite.orderService.UpdateOrder(orderID, userID, req)
+	result, err := suite.orderService.UpdateOrder(context.Background(), orderID, userID, domain.UserRolePharmacist, req)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), status, result.Status)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
+func (suite *OrderServiceTestSuite) TestUpdateOrder_ProcessingRequiresApprovedPrescription() {
+	prescriptionStatus := domain.PrescriptionStatusSubmitted
+	status := domain.OrderStatusProcessing
+	order := &domain.Order{
+		ID:                 1,
+		UserID:             1,
+		Status:             domain.OrderStatusPending,
+		PrescriptionStatus: &prescriptionStatus,
+	}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	result, err := suite.orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRolePharmacist, &service.UpdateOrderRequest{Status: &status})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrPrescriptionNotApproved, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_ProcessingWithApprovedPrescription() {
+	prescriptionStatus := domain.PrescriptionStatusApproved
+	status := domain.OrderStatusProcessing
+	order := &domain.Order{
+		ID:                 1,
+		UserID:             1,
+		Status:             domain.OrderStatusPending,
+		PrescriptionStatus: &prescriptionStatus,
+	}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	result, err := suite.orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRolePharmacist, &service.UpdateOrderRequest{Status: &status})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.OrderStatusProcessing, result.Status)
+	assert.NotNil(suite.T(), result.ProcessingStartedAt)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_CompletedRequiresApprovedPrescription() {
+	prescriptionStatus := domain.PrescriptionStatusRequired
+	status := domain.OrderStatusCompleted
+	order := &domain.Order{
+		ID:                 1,
+		UserID:             1,
+		Status:             domain.OrderStatusPending,
+		PrescriptionStatus: &prescriptionStatus,
+	}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	result, err := suite.orderService.UpdateOrder(context.Background(), 1, 2, domain.UserRoleAdmin, &service.UpdateOrderRequest{Status: &status})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrPrescriptionNotApproved, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_CustomerCannotAdvanceStatus() {
+	status := domain.OrderStatusCompleted
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusPending}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	result, err := suite.orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRoleCustomer, &service.UpdateOrderRequest{Status: &status})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrStatusChangeNotAllowed, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_CustomerCanCancel() {
+	status := domain.OrderStatusCancelled
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusPending}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	result, err := suite.orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRoleCustomer, &service.UpdateOrderRequest{Status: &status})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.OrderStatusCancelled, result.Status)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_PharmacistScopedToStore() {
+	directory := &stubStoreDirectory{staffStore: map[uint]uint{5: 2, 6: 3}}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithStoreDirectory(directory))
+	storeID := uint(2)
+	status := domain.OrderStatusProcessing
+	suite.mockRepo.On("GetByID", uint(1)).Return(&domain.Order{ID: 1, UserID: 1, StoreID: &storeID, Status: domain.OrderStatusPending}, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	_, err := orderService.UpdateOrder(context.Background(), 1, 6, domain.UserRolePharmacist, &service.UpdateOrderRequest{Status: &status})
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+	result, err := orderService.UpdateOrder(context.Background(), 1, 5, domain.UserRolePharmacist, &service.UpdateOrderRequest{Status: &status})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.OrderStatusProcessing, result.Status)
+}
 func (suite *OrderServiceTestSuite) TestUpdateOrder_InvalidStatus() {
 	orderID := uint(1)
 	userID := uint(1)
@@ -211,12 +509,553 @@ func (suite *OrderServiceTestSuite) TestUpdateOrder_InvalidStatus() {
 	req := &service.UpdateOrderRequest{
 		Status: &invalidStatus,
 	}
-	result, err := suite.orderService.UpdateOrder(orderID, userID, req)
+	result, err := suite.orderService.UpdateOrder(context.Background(), orderID, userID, domain.UserRoleCustomer, req)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), result)
 	assert.Equal(suite.T(), service.ErrInvalidOrderStatus, err)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
+func (suite *OrderServiceTestSuite) TestCreateOrder_PublishesEvent() {
+	publisher := &recordingPublisher{}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithEventPublisher(publisher))
+	req := &service.CreateOrderRequest{
+		Summary:            "I need paracetamol for a headache",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+	}
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	order, err := orderService.CreateOrder(context.Background(), 1, req)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), publisher.events, 1) {
+		assert.Equal(suite.T(), events.OrderCreated, publisher.events[0].Type)
+		assert.Equal(suite.T(), order, publisher.events[0].Order)
+	}
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_PublishesStatusChange() {
+	publisher := &recordingPublisher{}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithEventPublisher(publisher))
+	status := domain.OrderStatusProcessing
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusPending}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	_, err := orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRoleAdmin, &service.UpdateOrderRequest{Status: &status})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), publisher.events, 2) {
+		assert.Equal(suite.T(), events.OrderUpdated, publisher.events[0].Type)
+		assert.Equal(suite.T(), events.OrderStatusChanged, publisher.events[1].Type)
+	}
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_PassesCursor() {
+	userID := uint(1)
+	next := "next-token"
+	page := &pagination.Page{NextCursor: &next, HasMore: true, Limit: 2}
+	orders := []*domain.Order{{ID: 3, UserID: userID}, {ID: 2, UserID: userID}}
+	suite.mockRepo.On("GetByUserIDWithFilters", userID, mock.MatchedBy(func(f repository.OrderFilters) bool {
+		return f.Cursor == "abc" && f.Limit == 2 && f.IncludeTotal
+	})).Return(orders, page, nil)
+	result, resultPage, err := suite.orderService.GetOrders(context.Background(), userID, &service.GetOrdersFilters{Cursor: "abc", Limit: 2, IncludeTotal: true})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+	assert.Equal(suite.T(), page, resultPage)
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_InvalidCursor() {
+	suite.mockRepo.On("GetByUserIDWithFilters", uint(1), mock.Anything).Return(nil, nil, pagination.ErrInvalidCursor)
+	result, page, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{Cursor: "garbage"})
+	assert.Nil(suite.T(), result)
+	assert.Nil(suite.T(), page)
+	assert.Equal(suite.T(), service.ErrInvalidCursor, err)
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_MultiFieldSort() {
+	suite.mockRepo.On("GetByUserIDWithFilters", uint(1), mock.MatchedBy(func(f repository.OrderFilters) bool {
+		return len(f.Sort) == 2 &&
+			f.Sort[0] == repository.SortField{Field: "created_at", Desc: true} &&
+			f.Sort[1] == repository.SortField{Field: "status", Desc: false}
+	})).Return([]*domain.Order{}, &pagination.Page{}, nil)
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{Sort: "-created_at,status"})
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_LegacySortParams() {
+	suite.mockRepo.On("GetByUserIDWithFilters", uint(1), mock.MatchedBy(func(f repository.OrderFilters) bool {
+		return len(f.Sort) == 1 && f.Sort[0] == repository.SortField{Field: "total", Desc: false}
+	})).Return([]*domain.Order{}, &pagination.Page{}, nil)
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{SortBy: "total", SortOrder: "asc"})
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_RejectsUnknownSortField() {
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{Sort: "-created_at,(SELECT 1)"})
+	var sortErr *service.InvalidSortError
+	if assert.ErrorAs(suite.T(), err, &sortErr) {
+		assert.Equal(suite.T(), "(SELECT 1)", sortErr.Field)
+		assert.Contains(suite.T(), sortErr.Allowed, "created_at")
+		assert.Contains(suite.T(), sortErr.Allowed, "total")
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetByUserIDWithFilters", mock.Anything, mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_RejectsDuplicateSortField() {
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{Sort: "status,-status"})
+	var sortErr *service.InvalidSortError
+	assert.ErrorAs(suite.T(), err, &sortErr)
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_AdvancedFilters() {
+	status := "pending, processing"
+	preference := "DELIVERY"
+	suite.mockRepo.On("GetByUserIDWithFilters", uint(1), mock.MatchedBy(func(f repository.OrderFilters) bool {
+		return assert.ObjectsAreEqual([]string{"pending", "processing"}, f.Statuses) &&
+			assert.ObjectsAreEqual([]string{"DELIVERY"}, f.DeliveryPreferences) &&
+			f.CreatedAfter != nil && f.CreatedAfter.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			f.CreatedBefore != nil && f.CreatedBefore.Equal(time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)) &&
+			f.PostalCodePrefix == "100" &&
+			f.Search == "ibuprofen"
+	})).Return([]*domain.Order{}, &pagination.Page{}, nil)
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{
+		Status:             &status,
+		DeliveryPreference: &preference,
+		CreatedAfter:       "2024-01-01",
+		CreatedBefore:      "2024-02-01T12:00:00Z",
+		PostalCode:         "100",
+		Query:              " ibuprofen ",
+	})
+	assert.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_InvalidStatusFilter() {
+	status := "pending,shipped"
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{Status: &status})
+	var filterErr *service.InvalidFilterError
+	if assert.ErrorAs(suite.T(), err, &filterErr) {
+		assert.Equal(suite.T(), "status", filterErr.Param)
+		assert.Equal(suite.T(), "shipped", filterErr.Value)
+	}
+}
+func (suite *OrderServiceTestSuite) TestGetOrders_InvalidDateRange() {
+	_, _, err := suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{CreatedAfter: "2024-03-01", CreatedBefore: "2024-02-01"})
+	var filterErr *service.InvalidFilterError
+	assert.ErrorAs(suite.T(), err, &filterErr)
+	_, _, err = suite.orderService.GetOrders(context.Background(), 1, &service.GetOrdersFilters{CreatedAfter: "yesterday"})
+	assert.ErrorAs(suite.T(), err, &filterErr)
+}
+func (suite *OrderServiceTestSuite) TestCancelOrder_Pending() {
+	publisher := &recordingPublisher{}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithEventPublisher(publisher))
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusPending}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	result, err := orderService.CancelOrder(context.Background(), 1, 1, &service.CancelOrderRequest{
+		ReasonCode: domain.CancellationReasonOrderedByMistake,
+		Note:       "Wrong strength",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.OrderStatusCancelled, result.Status)
+	assert.Equal(suite.T(), domain.CancellationReasonOrderedByMistake, *result.CancellationReason)
+	assert.Equal(suite.T(), "Wrong strength", *result.CancellationNote)
+	assert.NotNil(suite.T(), result.CancelledAt)
+	if assert.Len(suite.T(), publisher.events, 2) {
+		assert.Equal(suite.T(), events.OrderCancelled, publisher.events[1].Type)
+	}
+}
+func (suite *OrderServiceTestSuite) TestCancelOrder_Completed() {
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusCompleted}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	result, err := suite.orderService.CancelOrder(context.Background(), 1, 1, &service.CancelOrderRequest{ReasonCode: domain.CancellationReasonChangedMind})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrOrderNotCancellable, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCancelOrder_ProcessingWithinCutoff() {
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithCancellationCutoff(time.Hour))
+	started := time.Now().Add(-10 * time.Minute)
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusProcessing, ProcessingStartedAt: &started}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	result, err := orderService.CancelOrder(context.Background(), 1, 1, &service.CancelOrderRequest{ReasonCode: domain.CancellationReasonTooSlow})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.OrderStatusCancelled, result.Status)
+}
+func (suite *OrderServiceTestSuite) TestCancelOrder_ProcessingPastCutoff() {
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithCancellationCutoff(5*time.Minute))
+	started := time.Now().Add(-10 * time.Minute)
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusProcessing, ProcessingStartedAt: &started}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	result, err := orderService.CancelOrder(context.Background(), 1, 1, &service.CancelOrderRequest{ReasonCode: domain.CancellationReasonTooSlow})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrCancellationWindowOver, err)
+}
+func (suite *OrderServiceTestSuite) TestCancelOrder_InvalidReason() {
+	result, err := suite.orderService.CancelOrder(context.Background(), 1, 1, &service.CancelOrderRequest{ReasonCode: "because"})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrInvalidCancelReason, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_CancelAppliesCutoff() {
+	status := domain.OrderStatusCancelled
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithCancellationCutoff(0))
+	started := time.Now().Add(-time.Minute)
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusProcessing, ProcessingStartedAt: &started}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	result, err := orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRoleCustomer, &service.UpdateOrderRequest{Status: &status})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrCancellationWindowOver, err)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_ReservesPickupSlot() {
+	scheduler := &stubPickupScheduler{slot: &domain.PickupSlot{ID: 7, StoreID: 2}}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithPickupScheduler(scheduler))
+	storeID := uint(2)
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	scheduler.slot.StartsAt = start
+	scheduler.slot.EndsAt = start.Add(30 * time.Minute)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Pick up my allergy medication refill",
+		DeliveryPreference: domain.DeliveryPreferenceCurbside,
+		StoreID:            &storeID,
+		PickupSlot:         &start,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(7), *order.PickupSlotID)
+	assert.Equal(suite.T(), storeID, *order.StoreID)
+	assert.True(suite.T(), start.Equal(*order.PickupSlotStart))
+	assert.Equal(suite.T(), []uint{2}, scheduler.reserved)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_PickupSlotFull() {
+	scheduler := &stubPickupScheduler{err: service.ErrPickupSlotFull}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithPickupScheduler(scheduler))
+	storeID := uint(2)
+	start := time.Now().Add(time.Hour)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Pick up my allergy medication refill",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+		StoreID:            &storeID,
+		PickupSlot:         &start,
+	})
+	assert.Nil(suite.T(), order)
+	assert.Equal(suite.T(), service.ErrPickupSlotFull, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_PickupSlotRequiresStore() {
+	start := time.Now().Add(time.Hour)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithPickupScheduler(&stubPickupScheduler{}))
+	_, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Pick up my allergy medication refill",
+		DeliveryPreference: domain.DeliveryPreferenceCurbside,
+		PickupSlot:         &start,
+	})
+	assert.Equal(suite.T(), service.ErrPickupStoreRequired, err)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_ReleasesSlotWhenCreateFails() {
+	scheduler := &stubPickupScheduler{slot: &domain.PickupSlot{ID: 7, StoreID: 2}}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithPickupScheduler(scheduler))
+	storeID := uint(2)
+	start := time.Now().Add(time.Hour)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(errors.New("db down"))
+	_, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Pick up my allergy medication refill",
+		DeliveryPreference: domain.DeliveryPreferenceCurbside,
+		StoreID:            &storeID,
+		PickupSlot:         &start,
+	})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), []uint{7}, scheduler.released)
+}
+func (suite *OrderServiceTestSuite) TestCancelOrder_ReleasesPickupSlot() {
+	scheduler := &stubPickupScheduler{}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithPickupScheduler(scheduler))
+	slotID := uint(9)
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusPending, PickupSlotID: &slotID}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	_, err := orderService.CancelOrder(context.Background(), 1, 1, &service.CancelOrderRequest{ReasonCode: domain.CancellationReasonChangedMind})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint{9}, scheduler.released)
+}
+func (suite *OrderServiceTestSuite) TestCheckIn_Curbside() {
+	publisher := &recordingPublisher{}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithEventPublisher(publisher))
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusProcessing, DeliveryPreference: domain.DeliveryPreferenceCurbside}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	result, err := orderService.CheckIn(context.Background(), 1, 1, &service.CheckInRequest{Note: " Blue sedan, bay 3 "})
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), result.CustomerArrivedAt)
+	assert.Equal(suite.T(), "Blue sedan, bay 3", *result.ArrivalNote)
+	if assert.Len(suite.T(), publisher.events, 1) {
+		assert.Equal(suite.T(), events.OrderCustomerArrived, publisher.events[0].Type)
+	}
+	_, err = orderService.CheckIn(context.Background(), 1, 1, &service.CheckInRequest{})
+	assert.Equal(suite.T(), service.ErrAlreadyCheckedIn, err)
+}
+func (suite *OrderServiceTestSuite) TestCheckIn_NotCurbside() {
+	order := &domain.Order{ID: 1, UserID: 1, Status: domain.OrderStatusPending, DeliveryPreference: domain.DeliveryPreferenceDelivery}
+	suite.mockRepo.On("GetByID", uint(1)).Return(order, nil)
+	_, err := suite.orderService.CheckIn(context.Background(), 1, 1, &service.CheckInRequest{})
+	assert.Equal(suite.T(), service.ErrCheckInNotAllowed, err)
+	_, err = suite.orderService.CheckIn(context.Background(), 1, 2, &service.CheckInRequest{})
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_ScopedToDefaultStore() {
+	directory := &stubStoreDirectory{store: &domain.Store{ID: 4, Active: true}}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithStoreDirectory(directory))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Refill my blood pressure medication",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(4), *order.StoreID)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_StoreDoesNotSupportPreference() {
+	directory := &stubStoreDirectory{store: &domain.Store{ID: 4, Active: true, DeliveryPreferences: domain.StringList{"IN_STORE"}}}
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithStoreDirectory(directory))
+	storeID := uint(4)
+	_, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Refill my blood pressure medication",
+		DeliveryPreference: domain.DeliveryPreferenceCurbside,
+		StoreID:            &storeID,
+	})
+	assert.Equal(suite.T(), service.ErrDeliveryPreferenceNotSupported, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_ConsumesQuota() {
+	ai := &stubAIService{products: []domain.AISuggestedProduct{{Name: "Ibuprofen"}}}
+	quotaRepo := new(MockAIQuotaRepository)
+	quotaRepo.On("GetLimit", uint(1)).Return(nil, nil)
+	quotaRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 3).Return(1, true, nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai,
+		service.WithSuggestionQuota(service.NewAIQuotaService(quotaRepo, new(MockUserRepository), 3)))
+	result, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Headache and mild fever"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, result.Count)
+	assert.Equal(suite.T(), 2, result.Quota.Remaining)
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_QuotaExceeded() {
+	ai := &stubAIService{}
+	quotaRepo := new(MockAIQuotaRepository)
+	quotaRepo.On("GetLimit", uint(1)).Return(nil, nil)
+	quotaRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 3).Return(3, false, nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai,
+		service.WithSuggestionQuota(service.NewAIQuotaService(quotaRepo, new(MockUserRepository), 3)))
+	result, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Headache and mild fever"})
+	assert.Equal(suite.T(), service.ErrAIQuotaExceeded, err)
+	assert.Equal(suite.T(), 0, result.Quota.Remaining)
+	assert.Equal(suite.T(), 0, ai.calls)
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_RefundsOnProviderError() {
+	ai := &stubAIService{err: errors.New("upstream timeout")}
+	quotaRepo := new(MockAIQuotaRepository)
+	quotaRepo.On("GetLimit", uint(1)).Return(nil, nil)
+	quotaRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 3).Return(1, true, nil)
+	quotaRepo.On("DecrementUsage", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai,
+		service.WithSuggestionQuota(service.NewAIQuotaService(quotaRepo, new(MockUserRepository), 3)))
+	_, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Headache and mild fever"})
+	assert.Error(suite.T(), err)
+	quotaRepo.AssertCalled(suite.T(), "DecrementUsage", uint(1), mock.AnythingOfType("time.Time"))
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_ChargesQuotaForUnparseableCompletion() {
+	ai := &stubAIService{raw: "Sorry, I can't help with that", err: errors.New("failed to parse AI response")}
+	quotaRepo := new(MockAIQuotaRepository)
+	quotaRepo.On("GetLimit", uint(1)).Return(nil, nil)
+	quotaRepo.On("IncrementUsage", uint(1), mock.AnythingOfType("time.Time"), 3).Return(1, true, nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai,
+		service.WithSuggestionQuota(service.NewAIQuotaService(quotaRepo, new(MockUserRepository), 3)))
+	_, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Headache and mild fever"})
+	assert.Error(suite.T(), err)
+	quotaRepo.AssertNotCalled(suite.T(), "DecrementUsage", mock.Anything, mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_SavesSession() {
+	ai := &stubAIService{products: []domain.AISuggestedProduct{{Name: "Ibuprofen"}}, raw: `[{"name":"Ibuprofen"}]`}
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("Create", mock.MatchedBy(func(session *domain.AISuggestionSession) bool {
+		return session.UserID == 1 && session.Model == "gpt-4o-mini" && session.RawOutput == `[{"name":"Ibuprofen"}]` && len(session.Products) == 1
+	})).Return(nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai, service.WithSuggestionSessions(sessions))
+	result, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Headache and mild fever"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(42), *result.SessionID)
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_SavesUnparseableOutput() {
+	ai := &stubAIService{raw: "Sorry, I can't help with that", err: errors.New("failed to parse AI response")}
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("Create", mock.AnythingOfType("*domain.AISuggestionSession")).Return(nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai, service.WithSuggestionSessions(sessions))
+	_, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Headache and mild fever"})
+	assert.Error(suite.T(), err)
+	sessions.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+func (suite *OrderServiceTestSuite) TestGetAISuggestions_AppliesGuardrail() {
+	ai := &stubAIService{products: []domain.AISuggestedProduct{
+		{Name: "Advil 200mg", Quantity: 2},
+		{Name: "Oxycodone 5mg", Quantity: 1},
+	}, raw: "[]"}
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("Create", mock.MatchedBy(func(session *domain.AISuggestionSession) bool {
+		return len(session.Products) == 1 && session.RequiresReview && len(session.Warnings) == 2
+	})).Return(nil)
+	orderService := service.NewOrderService(suite.mockRepo, ai, service.WithSuggestionSessions(sessions), service.WithGuardrail(checker))
+	result, err := orderService.GetAISuggestions(context.Background(), 1, &service.GetAISuggestionsRequest{Summary: "Back pain, I also take warfarin daily"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, result.Count)
+	assert.Equal(suite.T(), "Advil 200mg", result.Suggestions[0].Name)
+	assert.True(suite.T(), result.RequiresReview)
+	codes := []string{}
+	for _, w := range result.Warnings {
+		codes = append(codes, w.Code)
+	}
+	assert.ElementsMatch(suite.T(), []string{guardrail.CodeControlledSubstance, guardrail.CodeInteraction}, codes)
+	sessions.AssertExpectations(suite.T())
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_PrescriptionRequiredBySelectedProducts() {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithGuardrail(checker))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	selected := []domain.AISuggestedProduct{{Name: "Amoxicillin 500mg", Quantity: 1, Price: 12}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Antibiotics for my sore throat",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+		SelectedProducts:   &selected,
+	})
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), order.PrescriptionStatus) {
+		assert.Equal(suite.T(), domain.PrescriptionStatusRequired, *order.PrescriptionStatus)
+	}
+	assert.True(suite.T(), order.AwaitingPrescriptionApproval())
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_OverTheCounterNeedsNoPrescription() {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithGuardrail(checker))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	selected := []domain.AISuggestedProduct{{Name: "Vitamin C 500mg", Quantity: 1, Price: 6}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Something to help with a cold",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+		SelectedProducts:   &selected,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), order.PrescriptionStatus)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_RejectsBlockedProducts() {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithGuardrail(checker))
+	selected := []domain.AISuggestedProduct{{Name: "Melatonin 3mg", Quantity: 1, Price: 8}, {Name: "Xanax 0.5mg", Quantity: 1, Price: 20}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "I can't sleep at night",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+		SelectedProducts:   &selected,
+	})
+	assert.Nil(suite.T(), order)
+	assert.ErrorIs(suite.T(), err, service.ErrProductNotAllowed)
+	assert.Contains(suite.T(), err.Error(), "Xanax 0.5mg")
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_ClampsQuantities() {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithGuardrail(checker))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	selected := []domain.AISuggestedProduct{{Name: "Sudafed 12 hour", Quantity: 10, Price: 9}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Blocked nose for a week",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+		SelectedProducts:   &selected,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), *order.AISuggestedProducts, `"quantity":1`)
+}
+func (suite *OrderServiceTestSuite) TestUpdateOrder_RejectsBlockedProducts() {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithGuardrail(checker))
+	suite.mockRepo.On("GetByID", uint(1)).Return(&domain.Order{ID: 1, UserID: 1, Summary: "Back pain", Status: domain.OrderStatusPending}, nil)
+	products := []domain.AISuggestedProduct{{Name: "Oxycodone 5mg", Quantity: 1, Price: 30}}
+	result, err := orderService.UpdateOrder(context.Background(), 1, 1, domain.UserRoleCustomer, &service.UpdateOrderRequest{AISuggestedProducts: &products})
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, service.ErrProductNotAllowed)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_LinksSuggestionSession() {
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("GetByID", uint(42)).Return(&domain.AISuggestionSession{
+		ID:       42,
+		UserID:   1,
+		Products: domain.SuggestedProducts{{Name: "Ibuprofen 200mg"}, {Name: "Vitamin C"}},
+	}, nil)
+	sessions.On("LinkOrder", uint(42), uint(0), 1).Return(true, nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithSuggestionSessions(sessions))
+	sessionID := uint(42)
+	selected := []domain.AISuggestedProduct{{Name: "ibuprofen  200MG", Quantity: 1, Price: 5}, {Name: "Bandages", Quantity: 1, Price: 3}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:             "Refill my usual pain relief",
+		DeliveryPreference:  domain.DeliveryPreferenceInStore,
+		SelectedProducts:    &selected,
+		SuggestionSessionID: &sessionID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(42), *order.SuggestionSessionID)
+	sessions.AssertExpectations(suite.T())
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_FlaggedSessionNeedsPharmacistReview() {
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("GetByID", uint(42)).Return(&domain.AISuggestionSession{ID: 42, UserID: 1, RequiresReview: true}, nil)
+	sessions.On("LinkOrder", uint(42), uint(0), 0).Return(true, nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithSuggestionSessions(sessions))
+	sessionID := uint(42)
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:             "Headache, I'm on Coumadin for my heart",
+		DeliveryPreference:  domain.DeliveryPreferenceInStore,
+		SuggestionSessionID: &sessionID,
+	})
+	assert.NoError(suite.T(), err)
+	if assert.NotNil(suite.T(), order.PrescriptionStatus) {
+		assert.Equal(suite.T(), domain.PrescriptionStatusRequired, *order.PrescriptionStatus)
+	}
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_InteractingProductsNeedPharmacistReview() {
+	checker, err := guardrail.NewChecker(guardrail.DefaultRules(), guardrail.PrescriptionFlag)
+	suite.Require().NoError(err)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithGuardrail(checker))
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Order")).Return(nil)
+	selected := []domain.AISuggestedProduct{{Name: "Aleve 220mg", Quantity: 1, Price: 9}, {Name: "Bayer Aspirin 81mg", Quantity: 1, Price: 6}}
+	order, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:            "Knee pain after running",
+		DeliveryPreference: domain.DeliveryPreferenceInStore,
+		SelectedProducts:   &selected,
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), order.AwaitingPrescriptionApproval())
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_SuggestionSessionOfOtherUser() {
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("GetByID", uint(42)).Return(&domain.AISuggestionSession{ID: 42, UserID: 2}, nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithSuggestionSessions(sessions))
+	sessionID := uint(42)
+	_, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:             "Refill my usual pain relief",
+		DeliveryPreference:  domain.DeliveryPreferenceInStore,
+		SuggestionSessionID: &sessionID,
+	})
+	assert.Equal(suite.T(), service.ErrSuggestionSessionNotFound, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *OrderServiceTestSuite) TestCreateOrder_SuggestionSessionAlreadyUsed() {
+	orderID := uint(7)
+	sessions := new(MockAISuggestionRepository)
+	sessions.On("GetByID", uint(42)).Return(&domain.AISuggestionSession{ID: 42, UserID: 1, OrderID: &orderID}, nil)
+	orderService := service.NewOrderService(suite.mockRepo, nil, service.WithSuggestionSessions(sessions))
+	sessionID := uint(42)
+	_, err := orderService.CreateOrder(context.Background(), 1, &service.CreateOrderRequest{
+		Summary:             "Refill my usual pain relief",
+		DeliveryPreference:  domain.DeliveryPreferenceInStore,
+		SuggestionSessionID: &sessionID,
+	})
+	assert.Equal(suite.T(), service.ErrSuggestionSessionUsed, err)
+}
 func TestOrderServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderServiceTestSuite))
 }
@@ -0,0 +1,232 @@
+package service
+import (
+	"context"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+)
+const (
+	defaultPickupHorizonDays = 7
+	maxPickupQueryDays       = 31
+)
+type AvailableSlot struct {
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int       `json:"capacity"`
+	Remaining int       `json:"remaining"`
+}
+type PickupSlotsQuery struct {
+	Date string `form:"date"`
+	Days int    `form:"days" binding:"omitempty,min=1,max=31"`
+}
+type PickupConfigRequest struct {
+	Hours           []domain.DailyHours `json:"hours" binding:"required,min=1"`
+	SlotMinutes     int                 `json:"slot_minutes" binding:"required,min=5,max=240"`
+	Capacity        int                 `json:"capacity" binding:"required,min=1"`
+	LeadTimeMinutes int                 `json:"lead_time_minutes" binding:"min=0"`
+	HorizonDays     int                 `json:"horizon_days" binding:"omitempty,min=1,max=60"`
+}
+type PickupScheduler interface {
+	Reserve(ctx context.Context, storeID uint, start time.Time) (*domain.PickupSlot, error)
+	Release(ctx context.Context, slotID uint) error
+}
+type PickupService interface {
+	PickupScheduler
+	GetConfig(ctx context.Context, storeID uint) (*domain.PickupConfig, error)
+	UpdateConfig(ctx context.Context, storeID uint, req *PickupConfigRequest) (*domain.PickupConfig, error)
+	AvailableSlots(ctx context.Context, storeID uint, query *PickupSlotsQuery) ([]AvailableSlot, error)
+	ListArrivals(ctx context.Context, storeID uint) ([]*domain.Order, error)
+}
+type pickupService struct {
+	pickupRepo repository.PickupRepository
+	storeRepo  repository.StoreRepository
+	orderRepo  repository.OrderRepository
+	now        func() time.Time
+}
+type PickupServiceOption func(*pickupService)
+func WithPickupClock(now func() time.Time) PickupServiceOption {
+	return func(s *pickupService) {
+		s.now = now
+	}
+}
+func NewPickupService(pickupRepo repository.PickupRepository, storeRepo repository.StoreRepository, orderRepo repository.OrderRepository, opts ...PickupServiceOption) PickupService {
+	s := &pickupService{
+		pickupRepo: pickupRepo,
+		storeRepo:  storeRepo,
+		orderRepo:  orderRepo,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+func (s *pickupService) GetConfig(ctx context.Context, storeID uint) (*domain.PickupConfig, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, ErrStoreNotFound
+	}
+	config, err := s.pickupRepo.GetConfig(ctx, storeID)
+	if err != nil {
+		return nil, ErrPickupNotConfigured
+	}
+	return config, nil
+}
+func (s *pickupService) UpdateConfig(ctx context.Context, storeID uint, req *PickupConfigRequest) (*domain.PickupConfig, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, ErrStoreNotFound
+	}
+	if !validOpeningHours(req.Hours) {
+		return nil, ErrInvalidPickupConfig
+	}
+	horizon := req.HorizonDays
+	if horizon == 0 {
+		horizon = defaultPickupHorizonDays
+	}
+	config := &domain.PickupConfig{
+		StoreID:         storeID,
+		Hours:           domain.OpeningHours(req.Hours),
+		SlotMinutes:     req.SlotMinutes,
+		Capacity:        req.Capacity,
+		LeadTimeMinutes: req.LeadTimeMinutes,
+		HorizonDays:     horizon,
+	}
+	if err := s.pickupRepo.SaveConfig(ctx, config); err != nil {
+		return nil, err
+	}
+	return s.pickupRepo.GetConfig(ctx, storeID)
+}
+func (s *pickupService) AvailableSlots(ctx context.Context, storeID uint, query *PickupSlotsQuery) ([]AvailableSlot, error) {
+	store, config, err := s.load(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	loc := store.Location()
+	now := s.now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if query.Date != "" {
+		day, err = time.ParseInLocation("2006-01-02", query.Date, loc)
+		if err != nil {
+			return nil, ErrInvalidPickupSlot
+		}
+	}
+	days := query.Days
+	if days == 0 {
+		days = 1
+	}
+	if days > maxPickupQueryDays {
+		days = maxPickupQueryDays
+	}
+	end := day.AddDate(0, 0, days)
+	existing, err := s.pickupRepo.ListSlots(ctx, storeID, day, end)
+	if err != nil {
+		return nil, err
+	}
+	reserved := make(map[int64]*domain.PickupSlot, len(existing))
+	for _, slot := range existing {
+		reserved[slot.StartsAt.Unix()] = slot
+	}
+	slots := []AvailableSlot{}
+	for d := day; d.Before(end); d = d.AddDate(0, 0, 1) {
+		for _, candidate := range s.slotsForDay(config, d) {
+			if !s.bookable(config, candidate.StartsAt) {
+				continue
+			}
+			candidate.Capacity = config.Capacity
+			candidate.Remaining = config.Capacity
+			if slot, ok := reserved[candidate.StartsAt.Unix()]; ok {
+				candidate.Capacity = slot.Capacity
+				candidate.Remaining = slot.Capacity - slot.Reserved
+			}
+			if candidate.Remaining > 0 {
+				slots = append(slots, candidate)
+			}
+		}
+	}
+	return slots, nil
+}
+func (s *pickupService) Reserve(ctx context.Context, storeID uint, start time.Time) (*domain.PickupSlot, error) {
+	store, config, err := s.load(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	local := start.In(store.Location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	var match *AvailableSlot
+	for _, candidate := range s.slotsForDay(config, day) {
+		if candidate.StartsAt.Equal(start) {
+			c := candidate
+			match = &c
+			break
+		}
+	}
+	if match == nil || !s.bookable(config, match.StartsAt) {
+		return nil, ErrInvalidPickupSlot
+	}
+	slot, err := s.pickupRepo.EnsureSlot(ctx, &domain.PickupSlot{
+		StoreID:  storeID,
+		StartsAt: match.StartsAt.UTC(),
+		EndsAt:   match.EndsAt.UTC(),
+		Capacity: config.Capacity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.pickupRepo.Reserve(ctx, slot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPickupSlotFull
+	}
+	slot.Reserved++
+	return slot, nil
+}
+func (s *pickupService) Release(ctx context.Context, slotID uint) error {
+	return s.pickupRepo.Release(ctx, slotID)
+}
+func (s *pickupService) ListArrivals(ctx context.Context, storeID uint) ([]*domain.Order, error) {
+	if _, err := s.storeRepo.GetByID(ctx, storeID); err != nil {
+		return nil, ErrStoreNotFound
+	}
+	return s.orderRepo.ListArrivedAtStore(ctx, storeID)
+}
+func (s *pickupService) load(ctx context.Context, storeID uint) (*domain.Store, *domain.PickupConfig, error) {
+	store, err := s.storeRepo.GetByID(ctx, storeID)
+	if err != nil {
+		return nil, nil, ErrStoreNotFound
+	}
+	config, err := s.pickupRepo.GetConfig(ctx, storeID)
+	if err != nil {
+		return nil, nil, ErrPickupNotConfigured
+	}
+	return store, config, nil
+}
+func (s *pickupService) slotsForDay(config *domain.PickupConfig, day time.Time) []AvailableSlot {
+	var slots []AvailableSlot
+	length := time.Duration(config.SlotMinutes) * time.Minute
+	if length <= 0 {
+		return slots
+	}
+	for _, hours := range config.Hours.For(day.Weekday()) {
+		open, err := domain.ParseClock(hours.Open)
+		if err != nil {
+			continue
+		}
+		closing, err := domain.ParseClock(hours.Close)
+		if err != nil {
+			continue
+		}
+		for offset := open; offset+length <= closing; offset += length {
+			startsAt := time.Date(day.Year(), day.Month(), day.Day(), 0, int(offset/time.Minute), 0, 0, day.Location())
+			endsAt := time.Date(day.Year(), day.Month(), day.Day(), 0, int((offset+length)/time.Minute), 0, 0, day.Location())
+			slots = append(slots, AvailableSlot{StartsAt: startsAt, EndsAt: endsAt})
+		}
+	}
+	return slots
+}
+func (s *pickupService) bookable(config *domain.PickupConfig, start time.Time) bool {
+	now := s.now()
+	earliest := now.Add(time.Duration(config.LeadTimeMinutes) * time.Minute)
+	latest := now.AddDate(0, 0, config.HorizonDays)
+	return !start.Before(earliest) && start.Before(latest)
+}
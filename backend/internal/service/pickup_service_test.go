@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockPickupRepository struct {
+	mock.Mock
+}
+
+func (m *MockPickupRepository) GetConfig(ctx context.Context, storeID uint) (*domain.PickupConfig, error) {
+	args := m.Called(storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PickupConfig), args.Error(1)
+}
+func (m *MockPickupRepository) SaveConfig(ctx context.Context, config *domain.PickupConfig) error {
+	args := m.Called(config)
+	return args.Error(0)
+}
+func (m *MockPickupRepository) ListSlots(ctx context.Context, storeID uint, from, to time.Time) ([]*domain.PickupSlot, error) {
+	args := m.Called(storeID, from, to)
+	return args.Get(0).([]*domain.PickupSlot), args.Error(1)
+}
+func (m *MockPickupRepository) EnsureSlot(ctx context.Context, slot *domain.PickupSlot) (*domain.PickupSlot, error) {
+	args := m.Called(slot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PickupSlot), args.Error(1)
+}
+func (m *MockPickupRepository) Reserve(ctx context.Context, slotID uint) (bool, error) {
+	args := m.Called(slotID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockPickupRepository) Release(ctx context.Context, slotID uint) error {
+	args := m.Called(slotID)
+	return args.Error(0)
+}
+
+type PickupServiceTestSuite struct {
+	suite.Suite
+	pickupService  service.PickupService
+	mockPickupRepo *MockPickupRepository
+	mockStoreRepo  *MockStoreRepository
+	loc            *time.Location
+}
+
+func (suite *PickupServiceTestSuite) SetupTest() {
+	loc, err := time.LoadLocation("America/New_York")
+	suite.Require().NoError(err)
+	suite.loc = loc
+	suite.mockPickupRepo = new(MockPickupRepository)
+	suite.mockStoreRepo = new(MockStoreRepository)
+	now := time.Date(2024, 3, 4, 8, 50, 0, 0, loc)
+	suite.pickupService = service.NewPickupService(suite.mockPickupRepo, suite.mockStoreRepo, new(MockOrderRepository),
+		service.WithPickupClock(func() time.Time { return now }))
+	suite.mockStoreRepo.On("GetByID", uint(1)).Return(&domain.Store{ID: 1, Name: "Midtown", Timezone: "America/New_York"}, nil).Maybe()
+	suite.mockStoreRepo.On("GetByID", uint(99)).Return(nil, assert.AnError).Maybe()
+	suite.mockPickupRepo.On("GetConfig", uint(1)).Return(&domain.PickupConfig{
+		StoreID:         1,
+		Hours:           domain.OpeningHours{{Weekday: time.Monday, Open: "09:00", Close: "11:00"}},
+		SlotMinutes:     30,
+		Capacity:        2,
+		LeadTimeMinutes: 30,
+		HorizonDays:     7,
+	}, nil).Maybe()
+}
+func (suite *PickupServiceTestSuite) TestAvailableSlots_AppliesLeadTimeAndCapacity() {
+	full := time.Date(2024, 3, 4, 10, 0, 0, 0, suite.loc)
+	suite.mockPickupRepo.On("ListSlots", uint(1), mock.Anything, mock.Anything).Return([]*domain.PickupSlot{
+		{ID: 3, StoreID: 1, StartsAt: full.UTC(), Capacity: 2, Reserved: 2},
+	}, nil)
+	slots, err := suite.pickupService.AvailableSlots(context.Background(), 1, &service.PickupSlotsQuery{})
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), slots, 2) {
+		assert.True(suite.T(), slots[0].StartsAt.Equal(time.Date(2024, 3, 4, 9, 30, 0, 0, suite.loc)))
+		assert.True(suite.T(), slots[1].StartsAt.Equal(time.Date(2024, 3, 4, 10, 30, 0, 0, suite.loc)))
+		assert.Equal(suite.T(), 2, slots[1].Remaining)
+	}
+}
+func (suite *PickupServiceTestSuite) TestAvailableSlots_ClosedDay() {
+	suite.mockPickupRepo.On("ListSlots", uint(1), mock.Anything, mock.Anything).Return([]*domain.PickupSlot{}, nil)
+	slots, err := suite.pickupService.AvailableSlots(context.Background(), 1, &service.PickupSlotsQuery{Date: "2024-03-05"})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), slots)
+}
+func (suite *PickupServiceTestSuite) TestAvailableSlots_UnknownStore() {
+	_, err := suite.pickupService.AvailableSlots(context.Background(), 99, &service.PickupSlotsQuery{})
+	assert.Equal(suite.T(), service.ErrStoreNotFound, err)
+}
+func (suite *PickupServiceTestSuite) TestReserve_Success() {
+	start := time.Date(2024, 3, 4, 10, 30, 0, 0, suite.loc)
+	suite.mockPickupRepo.On("EnsureSlot", mock.MatchedBy(func(slot *domain.PickupSlot) bool {
+		return slot.StartsAt.Equal(start) && slot.EndsAt.Equal(start.Add(30*time.Minute)) && slot.Capacity == 2
+	})).Return(&domain.PickupSlot{ID: 5, StoreID: 1, StartsAt: start.UTC(), Capacity: 2, Reserved: 1}, nil)
+	suite.mockPickupRepo.On("Reserve", uint(5)).Return(true, nil)
+	slot, err := suite.pickupService.Reserve(context.Background(), 1, start.UTC())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(5), slot.ID)
+	assert.Equal(suite.T(), 2, slot.Reserved)
+}
+func (suite *PickupServiceTestSuite) TestReserve_Full() {
+	start := time.Date(2024, 3, 4, 10, 30, 0, 0, suite.loc)
+	suite.mockPickupRepo.On("EnsureSlot", mock.Anything).Return(&domain.PickupSlot{ID: 5, StoreID: 1, Capacity: 2, Reserved: 2}, nil)
+	suite.mockPickupRepo.On("Reserve", uint(5)).Return(false, nil)
+	_, err := suite.pickupService.Reserve(context.Background(), 1, start)
+	assert.Equal(suite.T(), service.ErrPickupSlotFull, err)
+}
+func (suite *PickupServiceTestSuite) TestReserve_RejectsUnofferedOrTooSoon() {
+	_, err := suite.pickupService.Reserve(context.Background(), 1, time.Date(2024, 3, 4, 10, 15, 0, 0, suite.loc))
+	assert.Equal(suite.T(), service.ErrInvalidPickupSlot, err)
+	_, err = suite.pickupService.Reserve(context.Background(), 1, time.Date(2024, 3, 4, 9, 0, 0, 0, suite.loc))
+	assert.Equal(suite.T(), service.ErrInvalidPickupSlot, err)
+	suite.mockPickupRepo.AssertNotCalled(suite.T(), "EnsureSlot", mock.Anything)
+}
+func (suite *PickupServiceTestSuite) TestUpdateConfig_Validates() {
+	_, err := suite.pickupService.UpdateConfig(context.Background(), 1, &service.PickupConfigRequest{
+		Hours:       []domain.DailyHours{{Weekday: time.Monday, Open: "18:00", Close: "09:00"}},
+		SlotMinutes: 30,
+		Capacity:    1,
+	})
+	assert.Equal(suite.T(), service.ErrInvalidPickupConfig, err)
+	suite.mockPickupRepo.AssertNotCalled(suite.T(), "SaveConfig", mock.Anything)
+}
+func TestPickupServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PickupServiceTestSuite))
+}
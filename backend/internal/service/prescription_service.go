@@ -0,0 +1,237 @@
+package service
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+	"weel-backend/internal/storage"
+)
+const (
+	MaxPrescriptionSize    = 10 << 20
+	prescriptionQueueLimit = 100
+	PrescriptionURLExpiry  = 15 * time.Minute
+)
+var prescriptionExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+type ReviewPrescriptionRequest struct {
+	Decision domain.PrescriptionStatus `json:"decision" binding:"required,oneof=approved rejected"`
+	Note     *string                   `json:"note,omitempty"`
+}
+type PrescriptionService interface {
+	Upload(ctx context.Context, orderID, userID uint, fileName string, r io.Reader) (*domain.Prescription, error)
+	ListForOrder(ctx context.Context, orderID, userID uint, role domain.UserRole) ([]*domain.Prescription, error)
+	ListQueue(ctx context.Context, reviewerID uint, role domain.UserRole, status domain.PrescriptionStatus) ([]*domain.Prescription, error)
+	Open(ctx context.Context, prescriptionID, userID uint, role domain.UserRole) (io.ReadCloser, *domain.Prescription, error)
+	SignedURL(ctx context.Context, prescriptionID, userID uint, role domain.UserRole) (string, time.Time, error)
+	Review(ctx context.Context, prescriptionID, reviewerID uint, role domain.UserRole, req *ReviewPrescriptionRequest) (*domain.Prescription, error)
+}
+type prescriptionService struct {
+	prescriptionRepo repository.PrescriptionRepository
+	orderRepo        repository.OrderRepository
+	directory        StoreDirectory
+	store            storage.BlobStore
+	now              func() time.Time
+}
+func NewPrescriptionService(prescriptionRepo repository.PrescriptionRepository, orderRepo repository.OrderRepository, directory StoreDirectory, store storage.BlobStore) PrescriptionService {
+	return &prescriptionService{
+		prescriptionRepo: prescriptionRepo,
+		orderRepo:        orderRepo,
+		directory:        directory,
+		store:            store,
+		now:              time.Now,
+	}
+}
+func (s *prescriptionService) Upload(ctx context.Context, orderID, userID uint, fileName string, r io.Reader) (*domain.Prescription, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+	if order.PrescriptionStatus == nil {
+		return nil, ErrPrescriptionNotRequired
+	}
+	if *order.PrescriptionStatus == domain.PrescriptionStatusApproved {
+		return nil, ErrPrescriptionReviewed
+	}
+	data, err := io.ReadAll(io.LimitReader(r, MaxPrescriptionSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxPrescriptionSize {
+		return nil, ErrPrescriptionTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := prescriptionExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedFileType
+	}
+	key, err := generatePrescriptionKey(order.ID, ext)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.store.Put(ctx, key, bytes.NewReader(data), contentType)
+	if err != nil {
+		return nil, err
+	}
+	prescription := &domain.Prescription{
+		OrderID:      order.ID,
+		UploadedByID: userID,
+		FileKey:      obj.Key,
+		FileName:     fileName,
+		ContentType:  contentType,
+		SizeBytes:    obj.Size,
+		Status:       domain.PrescriptionStatusSubmitted,
+	}
+	if err := s.prescriptionRepo.Create(ctx, prescription); err != nil {
+		_ = s.store.Delete(ctx, obj.Key)
+		return nil, err
+	}
+	status := domain.PrescriptionStatusSubmitted
+	order.PrescriptionStatus = &status
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	return prescription, nil
+}
+func (s *prescriptionService) ListForOrder(ctx context.Context, orderID, userID uint, role domain.UserRole) ([]*domain.Prescription, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		if err := s.authorizeReviewer(ctx, order, userID, role); err != nil {
+			return nil, err
+		}
+	}
+	return s.prescriptionRepo.ListByOrderID(ctx, orderID)
+}
+func (s *prescriptionService) ListQueue(ctx context.Context, reviewerID uint, role domain.UserRole, status domain.PrescriptionStatus) ([]*domain.Prescription, error) {
+	storeID, err := s.reviewerStoreID(ctx, reviewerID, role)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = domain.PrescriptionStatusSubmitted
+	}
+	return s.prescriptionRepo.ListByStatus(ctx, status, storeID, prescriptionQueueLimit)
+}
+func (s *prescriptionService) Open(ctx context.Context, prescriptionID, userID uint, role domain.UserRole) (io.ReadCloser, *domain.Prescription, error) {
+	prescription, err := s.authorize(ctx, prescriptionID, userID, role)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, _, err := s.store.Get(ctx, prescription.FileKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, nil, ErrPrescriptionNotFound
+		}
+		return nil, nil, err
+	}
+	return rc, prescription, nil
+}
+func (s *prescriptionService) SignedURL(ctx context.Context, prescriptionID, userID uint, role domain.UserRole) (string, time.Time, error) {
+	prescription, err := s.authorize(ctx, prescriptionID, userID, role)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := s.now().Add(PrescriptionURLExpiry)
+	url, err := s.store.SignedURL(ctx, prescription.FileKey, PrescriptionURLExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return url, expiresAt, nil
+}
+func (s *prescriptionService) authorize(ctx context.Context, prescriptionID, userID uint, role domain.UserRole) (*domain.Prescription, error) {
+	prescription, err := s.prescriptionRepo.GetByID(ctx, prescriptionID)
+	if err != nil {
+		return nil, ErrPrescriptionNotFound
+	}
+	order, err := s.orderRepo.GetByID(ctx, prescription.OrderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		if err := s.authorizeReviewer(ctx, order, userID, role); err != nil {
+			return nil, err
+		}
+	}
+	return prescription, nil
+}
+func (s *prescriptionService) authorizeReviewer(ctx context.Context, order *domain.Order, userID uint, role domain.UserRole) error {
+	if !role.CanReviewPrescriptions() {
+		return ErrUnauthorizedAccess
+	}
+	storeID, err := s.reviewerStoreID(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+	if storeID != nil && (order.StoreID == nil || *order.StoreID != *storeID) {
+		return ErrUnauthorizedAccess
+	}
+	return nil
+}
+func (s *prescriptionService) reviewerStoreID(ctx context.Context, userID uint, role domain.UserRole) (*uint, error) {
+	if role == domain.UserRoleAdmin {
+		return nil, nil
+	}
+	storeID, err := s.directory.StaffStoreID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if storeID == nil {
+		return nil, ErrUnauthorizedAccess
+	}
+	return storeID, nil
+}
+func (s *prescriptionService) Review(ctx context.Context, prescriptionID, reviewerID uint, role domain.UserRole, req *ReviewPrescriptionRequest) (*domain.Prescription, error) {
+	if req.Decision != domain.PrescriptionStatusApproved && req.Decision != domain.PrescriptionStatusRejected {
+		return nil, ErrInvalidReviewDecision
+	}
+	prescription, err := s.prescriptionRepo.GetByID(ctx, prescriptionID)
+	if err != nil {
+		return nil, ErrPrescriptionNotFound
+	}
+	if prescription.Status != domain.PrescriptionStatusSubmitted {
+		return nil, ErrPrescriptionReviewed
+	}
+	order, err := s.orderRepo.GetByID(ctx, prescription.OrderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := s.authorizeReviewer(ctx, order, reviewerID, role); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	prescription.Status = req.Decision
+	prescription.ReviewedByID = &reviewerID
+	prescription.ReviewedAt = &now
+	prescription.ReviewNote = req.Note
+	if err := s.prescriptionRepo.Update(ctx, prescription); err != nil {
+		return nil, err
+	}
+	status := req.Decision
+	order.PrescriptionStatus = &status
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	return prescription, nil
+}
+func generatePrescriptionKey(orderID uint, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("prescriptions/%d/%s%s", orderID, hex.EncodeToString(b), ext), nil
+}
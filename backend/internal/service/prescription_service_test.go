@@ -0,0 +1,200 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+	"weel-backend/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockPrescriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPrescriptionRepository) Create(ctx context.Context, prescription *domain.Prescription) error {
+	args := m.Called(prescription)
+	return args.Error(0)
+}
+func (m *MockPrescriptionRepository) GetByID(ctx context.Context, id uint) (*domain.Prescription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Prescription), args.Error(1)
+}
+func (m *MockPrescriptionRepository) ListByOrderID(ctx context.Context, orderID uint) ([]*domain.Prescription, error) {
+	args := m.Called(orderID)
+	return args.Get(0).([]*domain.Prescription), args.Error(1)
+}
+func (m *MockPrescriptionRepository) ListByStatus(ctx context.Context, status domain.PrescriptionStatus, storeID *uint, limit int) ([]*domain.Prescription, error) {
+	args := m.Called(status, storeID, limit)
+	return args.Get(0).([]*domain.Prescription), args.Error(1)
+}
+func (m *MockPrescriptionRepository) Update(ctx context.Context, prescription *domain.Prescription) error {
+	args := m.Called(prescription)
+	return args.Error(0)
+}
+
+type PrescriptionServiceTestSuite struct {
+	suite.Suite
+	prescriptionService service.PrescriptionService
+	mockRepo            *MockPrescriptionRepository
+	mockOrderRepo       *MockOrderRepository
+	directory           *stubStoreDirectory
+	store               storage.BlobStore
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func (suite *PrescriptionServiceTestSuite) SetupTest() {
+	store, err := storage.NewLocalStore(suite.T().TempDir())
+	require.NoError(suite.T(), err)
+	suite.store = store
+	suite.mockRepo = new(MockPrescriptionRepository)
+	suite.mockOrderRepo = new(MockOrderRepository)
+	suite.directory = &stubStoreDirectory{staffStore: map[uint]uint{42: 2, 99: 2, 43: 3}}
+	suite.prescriptionService = service.NewPrescriptionService(suite.mockRepo, suite.mockOrderRepo, suite.directory, store)
+}
+func (suite *PrescriptionServiceTestSuite) requiredOrder() *domain.Order {
+	status := domain.PrescriptionStatusRequired
+	storeID := uint(2)
+	return &domain.Order{ID: 7, UserID: 1, StoreID: &storeID, Status: domain.OrderStatusPending, PrescriptionStatus: &status}
+}
+func (suite *PrescriptionServiceTestSuite) TestUpload_StoresFileAndMarksSubmitted() {
+	order := suite.requiredOrder()
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(order, nil)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.Prescription")).Return(nil)
+	suite.mockOrderRepo.On("Update", order).Return(nil)
+	prescription, err := suite.prescriptionService.Upload(context.Background(), 7, 1, "rx.png", bytes.NewReader(pngHeader))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "image/png", prescription.ContentType)
+	assert.Equal(suite.T(), domain.PrescriptionStatusSubmitted, prescription.Status)
+	assert.True(suite.T(), strings.HasPrefix(prescription.FileKey, "prescriptions/7/"))
+	assert.Equal(suite.T(), domain.PrescriptionStatusSubmitted, *order.PrescriptionStatus)
+	rc, _, err := suite.store.Get(context.Background(), prescription.FileKey)
+	require.NoError(suite.T(), err)
+	defer rc.Close()
+	data, _ := io.ReadAll(rc)
+	assert.Equal(suite.T(), pngHeader, data)
+}
+func (suite *PrescriptionServiceTestSuite) TestUpload_RejectsUnsupportedType() {
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	prescription, err := suite.prescriptionService.Upload(context.Background(), 7, 1, "rx.txt", strings.NewReader("plain text prescription"))
+	assert.Nil(suite.T(), prescription)
+	assert.Equal(suite.T(), service.ErrUnsupportedFileType, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *PrescriptionServiceTestSuite) TestUpload_RejectsOversizedFile() {
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	large := io.MultiReader(bytes.NewReader(pngHeader), bytes.NewReader(make([]byte, service.MaxPrescriptionSize)))
+	prescription, err := suite.prescriptionService.Upload(context.Background(), 7, 1, "rx.png", large)
+	assert.Nil(suite.T(), prescription)
+	assert.Equal(suite.T(), service.ErrPrescriptionTooLarge, err)
+}
+func (suite *PrescriptionServiceTestSuite) TestUpload_OtherUsersOrder() {
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	prescription, err := suite.prescriptionService.Upload(context.Background(), 7, 2, "rx.png", bytes.NewReader(pngHeader))
+	assert.Nil(suite.T(), prescription)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func (suite *PrescriptionServiceTestSuite) TestUpload_NotRequired() {
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(&domain.Order{ID: 7, UserID: 1}, nil)
+	prescription, err := suite.prescriptionService.Upload(context.Background(), 7, 1, "rx.png", bytes.NewReader(pngHeader))
+	assert.Nil(suite.T(), prescription)
+	assert.Equal(suite.T(), service.ErrPrescriptionNotRequired, err)
+}
+func (suite *PrescriptionServiceTestSuite) TestReview_ApprovesAndRecordsReviewer() {
+	order := suite.requiredOrder()
+	prescription := &domain.Prescription{ID: 3, OrderID: 7, Status: domain.PrescriptionStatusSubmitted}
+	suite.mockRepo.On("GetByID", uint(3)).Return(prescription, nil)
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(order, nil)
+	suite.mockRepo.On("Update", prescription).Return(nil)
+	suite.mockOrderRepo.On("Update", order).Return(nil)
+	result, err := suite.prescriptionService.Review(context.Background(), 3, 42, domain.UserRolePharmacist, &service.ReviewPrescriptionRequest{Decision: domain.PrescriptionStatusApproved})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.PrescriptionStatusApproved, result.Status)
+	assert.Equal(suite.T(), uint(42), *result.ReviewedByID)
+	assert.NotNil(suite.T(), result.ReviewedAt)
+	assert.Equal(suite.T(), domain.PrescriptionStatusApproved, *order.PrescriptionStatus)
+	assert.False(suite.T(), order.AwaitingPrescriptionApproval())
+}
+func (suite *PrescriptionServiceTestSuite) TestReview_AlreadyReviewed() {
+	prescription := &domain.Prescription{ID: 3, OrderID: 7, Status: domain.PrescriptionStatusRejected}
+	suite.mockRepo.On("GetByID", uint(3)).Return(prescription, nil)
+	result, err := suite.prescriptionService.Review(context.Background(), 3, 42, domain.UserRolePharmacist, &service.ReviewPrescriptionRequest{Decision: domain.PrescriptionStatusApproved})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrPrescriptionReviewed, err)
+}
+func (suite *PrescriptionServiceTestSuite) TestReview_RejectsOtherStores() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.Prescription{ID: 3, OrderID: 7, Status: domain.PrescriptionStatusSubmitted}, nil)
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	result, err := suite.prescriptionService.Review(context.Background(), 3, 43, domain.UserRolePharmacist, &service.ReviewPrescriptionRequest{Decision: domain.PrescriptionStatusApproved})
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+	suite.mockOrderRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *PrescriptionServiceTestSuite) TestReview_AdminCanReviewAnyStore() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.Prescription{ID: 3, OrderID: 7, Status: domain.PrescriptionStatusSubmitted}, nil)
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.Prescription")).Return(nil)
+	suite.mockOrderRepo.On("Update", mock.AnythingOfType("*domain.Order")).Return(nil)
+	result, err := suite.prescriptionService.Review(context.Background(), 3, 500, domain.UserRoleAdmin, &service.ReviewPrescriptionRequest{Decision: domain.PrescriptionStatusRejected})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.PrescriptionStatusRejected, result.Status)
+}
+func (suite *PrescriptionServiceTestSuite) TestListQueue_ScopedToPharmacistStore() {
+	storeID := uint(2)
+	suite.mockRepo.On("ListByStatus", domain.PrescriptionStatusSubmitted, &storeID, 100).Return([]*domain.Prescription{{ID: 3}}, nil)
+	result, err := suite.prescriptionService.ListQueue(context.Background(), 42, domain.UserRolePharmacist, "")
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+}
+func (suite *PrescriptionServiceTestSuite) TestListQueue_AdminSeesAllStores() {
+	suite.mockRepo.On("ListByStatus", domain.PrescriptionStatusSubmitted, (*uint)(nil), 100).Return([]*domain.Prescription{}, nil)
+	_, err := suite.prescriptionService.ListQueue(context.Background(), 500, domain.UserRoleAdmin, "")
+	require.NoError(suite.T(), err)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *PrescriptionServiceTestSuite) TestListQueue_UnassignedPharmacist() {
+	result, err := suite.prescriptionService.ListQueue(context.Background(), 77, domain.UserRolePharmacist, "")
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "ListByStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+func (suite *PrescriptionServiceTestSuite) TestOpen_StaffCanReadOwnStorePrescription() {
+	_, err := suite.store.Put(context.Background(), "prescriptions/7/abc.png", bytes.NewReader(pngHeader), "image/png")
+	require.NoError(suite.T(), err)
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.Prescription{ID: 3, OrderID: 7, FileKey: "prescriptions/7/abc.png"}, nil)
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	rc, prescription, err := suite.prescriptionService.Open(context.Background(), 3, 99, domain.UserRolePharmacist)
+	require.NoError(suite.T(), err)
+	defer rc.Close()
+	assert.Equal(suite.T(), uint(3), prescription.ID)
+}
+func (suite *PrescriptionServiceTestSuite) TestOpen_StaffCannotReadOtherStores() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.Prescription{ID: 3, OrderID: 7, FileKey: "prescriptions/7/abc.png"}, nil)
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	rc, _, err := suite.prescriptionService.Open(context.Background(), 3, 43, domain.UserRolePharmacist)
+	assert.Nil(suite.T(), rc)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func (suite *PrescriptionServiceTestSuite) TestOpen_CustomerCannotReadOthers() {
+	suite.mockRepo.On("GetByID", uint(3)).Return(&domain.Prescription{ID: 3, OrderID: 7, FileKey: "prescriptions/7/abc.png"}, nil)
+	suite.mockOrderRepo.On("GetByID", uint(7)).Return(suite.requiredOrder(), nil)
+	rc, _, err := suite.prescriptionService.Open(context.Background(), 3, 2, domain.UserRoleCustomer)
+	assert.Nil(suite.T(), rc)
+	assert.Equal(suite.T(), service.ErrUnauthorizedAccess, err)
+}
+func TestPrescriptionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PrescriptionServiceTestSuite))
+}
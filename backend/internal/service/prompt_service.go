@@ -0,0 +1,165 @@
+package service
+import (
+	"context"
+	"log/slog"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/prompt"
+	"weel-backend/internal/repository"
+)
+var ErrInvalidPromptTemplate = prompt.ErrInvalidTemplate
+type PromptTemplateRequest struct {
+	Model       string   `json:"model" binding:"required,max=100"`
+	Temperature *float32 `json:"temperature" binding:"required,min=0,max=2"`
+	MaxTokens   int      `json:"max_tokens" binding:"required,min=1,max=4096"`
+	System      string   `json:"system"`
+	User        string   `json:"user" binding:"required"`
+	Activate    bool     `json:"activate"`
+}
+type PromptPreviewRequest struct {
+	Version         *int    `json:"version,omitempty"`
+	Summary         string  `json:"summary" binding:"required"`
+	DeliveryAddress *string `json:"delivery_address,omitempty"`
+}
+type PromptTemplateList struct {
+	Name          string                  `json:"name"`
+	ActiveVersion int                     `json:"active_version"`
+	Versions      []domain.PromptTemplate `json:"versions"`
+}
+type PromptSource interface {
+	Active(ctx context.Context, name string) (*domain.PromptTemplate, error)
+}
+type PromptService interface {
+	PromptSource
+	List(ctx context.Context) ([]*PromptTemplateList, error)
+	Get(ctx context.Context, name string) (*PromptTemplateList, error)
+	Create(ctx context.Context, name string, userID uint, req *PromptTemplateRequest) (*domain.PromptTemplate, error)
+	Activate(ctx context.Context, name string, version int) (*domain.PromptTemplate, error)
+	Preview(ctx context.Context, name string, req *PromptPreviewRequest) (*domain.RenderedPrompt, error)
+}
+type promptService struct {
+	promptRepo repository.PromptTemplateRepository
+}
+func NewPromptService(promptRepo repository.PromptTemplateRepository) PromptService {
+	return &promptService{promptRepo: promptRepo}
+}
+func (s *promptService) Active(ctx context.Context, name string) (*domain.PromptTemplate, error) {
+	override, err := s.promptRepo.GetActive(ctx, name)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load prompt template override, using built-in", "component", "ai", "prompt", name, "error", err)
+	}
+	if override != nil {
+		return override, nil
+	}
+	return prompt.Default(name)
+}
+func (s *promptService) List(ctx context.Context) ([]*PromptTemplateList, error) {
+	lists := make([]*PromptTemplateList, 0, len(prompt.Names()))
+	for _, name := range prompt.Names() {
+		list, err := s.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+func (s *promptService) Get(ctx context.Context, name string) (*PromptTemplateList, error) {
+	versions := prompt.Defaults(name)
+	if len(versions) == 0 {
+		return nil, ErrPromptTemplateNotFound
+	}
+	overrides, err := s.promptRepo.List(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, overrides...)
+	list := &PromptTemplateList{Name: name, Versions: versions}
+	for _, t := range overrides {
+		if t.Active {
+			list.ActiveVersion = t.Version
+		}
+	}
+	if list.ActiveVersion == 0 {
+		latest := len(prompt.Defaults(name)) - 1
+		versions[latest].Active = true
+		list.ActiveVersion = versions[latest].Version
+	}
+	return list, nil
+}
+func (s *promptService) Create(ctx context.Context, name string, userID uint, req *PromptTemplateRequest) (*domain.PromptTemplate, error) {
+	latest, err := prompt.Default(name)
+	if err != nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+	template := &domain.PromptTemplate{
+		Name:        name,
+		Model:       req.Model,
+		Temperature: *req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		System:      req.System,
+		User:        req.User,
+		Active:      req.Activate,
+		CreatedBy:   &userID,
+	}
+	if err := prompt.Validate(template); err != nil {
+		return nil, err
+	}
+	if err := s.promptRepo.Create(ctx, template, latest.Version); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+func (s *promptService) Activate(ctx context.Context, name string, version int) (*domain.PromptTemplate, error) {
+	latest, err := prompt.Default(name)
+	if err != nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+	if _, ok := prompt.DefaultVersion(name, version); ok {
+		if version != latest.Version {
+			return nil, ErrPromptTemplateRetired
+		}
+		if err := s.promptRepo.Deactivate(ctx, name); err != nil {
+			return nil, err
+		}
+		latest.Active = true
+		return latest, nil
+	}
+	found, err := s.promptRepo.Activate(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrPromptTemplateNotFound
+	}
+	return s.promptRepo.GetVersion(ctx, name, version)
+}
+func (s *promptService) Preview(ctx context.Context, name string, req *PromptPreviewRequest) (*domain.RenderedPrompt, error) {
+	if _, err := prompt.Default(name); err != nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+	template, err := s.resolve(ctx, name, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	data := prompt.Data{Summary: req.Summary}
+	if req.DeliveryAddress != nil {
+		data.DeliveryAddress = *req.DeliveryAddress
+	}
+	return prompt.Render(template, data)
+}
+func (s *promptService) resolve(ctx context.Context, name string, version *int) (*domain.PromptTemplate, error) {
+	if version == nil {
+		return s.Active(ctx, name)
+	}
+	if t, ok := prompt.DefaultVersion(name, *version); ok {
+		return t, nil
+	}
+	t, err := s.promptRepo.GetVersion(ctx, name, *version)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+	return t, nil
+}
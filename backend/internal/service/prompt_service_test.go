@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/prompt"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockPromptTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromptTemplateRepository) List(ctx context.Context, name string) ([]domain.PromptTemplate, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PromptTemplate), args.Error(1)
+}
+func (m *MockPromptTemplateRepository) GetVersion(ctx context.Context, name string, version int) (*domain.PromptTemplate, error) {
+	args := m.Called(name, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PromptTemplate), args.Error(1)
+}
+func (m *MockPromptTemplateRepository) GetActive(ctx context.Context, name string) (*domain.PromptTemplate, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PromptTemplate), args.Error(1)
+}
+func (m *MockPromptTemplateRepository) Create(ctx context.Context, template *domain.PromptTemplate, minVersion int) error {
+	args := m.Called(template, minVersion)
+	template.Version = minVersion + 1
+	return args.Error(0)
+}
+func (m *MockPromptTemplateRepository) Activate(ctx context.Context, name string, version int) (bool, error) {
+	args := m.Called(name, version)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockPromptTemplateRepository) Deactivate(ctx context.Context, name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+type PromptServiceTestSuite struct {
+	suite.Suite
+	promptService service.PromptService
+	mockRepo      *MockPromptTemplateRepository
+}
+
+func (suite *PromptServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockPromptTemplateRepository)
+	suite.promptService = service.NewPromptService(suite.mockRepo)
+}
+func (suite *PromptServiceTestSuite) override(version int) *domain.PromptTemplate {
+	return &domain.PromptTemplate{
+		Name:        prompt.SuggestProducts,
+		Version:     version,
+		Model:       "gpt-4o",
+		Temperature: 0.2,
+		MaxTokens:   800,
+		User:        "Suggest products for: {{.Summary}}",
+		Active:      true,
+	}
+}
+func (suite *PromptServiceTestSuite) TestActive_FallsBackToBuiltIn() {
+	suite.mockRepo.On("GetActive", prompt.SuggestProducts).Return(nil, errors.New("connection refused"))
+	template, err := suite.promptService.Active(context.Background(), prompt.SuggestProducts)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), template.BuiltIn)
+	assert.Equal(suite.T(), "gpt-4o-mini", template.Model)
+}
+func (suite *PromptServiceTestSuite) TestActive_UsesOverride() {
+	suite.mockRepo.On("GetActive", prompt.SuggestProducts).Return(suite.override(2), nil)
+	template, err := suite.promptService.Active(context.Background(), prompt.SuggestProducts)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, template.Version)
+	assert.Equal(suite.T(), "gpt-4o", template.Model)
+}
+func (suite *PromptServiceTestSuite) TestGet_MarksActiveVersion() {
+	inactive := suite.override(2)
+	inactive.Active = false
+	suite.mockRepo.On("List", prompt.SuggestProducts).Return([]domain.PromptTemplate{*inactive}, nil)
+	list, err := suite.promptService.Get(context.Background(), prompt.SuggestProducts)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, list.ActiveVersion)
+	assert.Len(suite.T(), list.Versions, 2)
+	assert.True(suite.T(), list.Versions[0].Active)
+	_, err = suite.promptService.Get(context.Background(), "unknown")
+	assert.Equal(suite.T(), service.ErrPromptTemplateNotFound, err)
+}
+func (suite *PromptServiceTestSuite) TestCreate_NumbersAfterBuiltInVersions() {
+	temperature := float32(0.2)
+	suite.mockRepo.On("Create", mock.MatchedBy(func(t *domain.PromptTemplate) bool {
+		return t.Name == prompt.SuggestProducts && t.Model == "gpt-4o" && *t.CreatedBy == 7
+	}), 1).Return(nil)
+	template, err := suite.promptService.Create(context.Background(), prompt.SuggestProducts, 7, &service.PromptTemplateRequest{
+		Model:       "gpt-4o",
+		Temperature: &temperature,
+		MaxTokens:   800,
+		User:        "Suggest products for: {{.Summary}}",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, template.Version)
+}
+func (suite *PromptServiceTestSuite) TestCreate_InvalidTemplate() {
+	temperature := float32(0.2)
+	_, err := suite.promptService.Create(context.Background(), prompt.SuggestProducts, 7, &service.PromptTemplateRequest{
+		Model:       "gpt-4o",
+		Temperature: &temperature,
+		MaxTokens:   800,
+		User:        "Suggest products for: {{.Sumary}}",
+	})
+	assert.ErrorIs(suite.T(), err, service.ErrInvalidPromptTemplate)
+	suite.mockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+func (suite *PromptServiceTestSuite) TestActivate_BuiltInRevertsOverrides() {
+	suite.mockRepo.On("Deactivate", prompt.SuggestProducts).Return(nil)
+	template, err := suite.promptService.Activate(context.Background(), prompt.SuggestProducts, 1)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), template.BuiltIn)
+	assert.True(suite.T(), template.Active)
+}
+func (suite *PromptServiceTestSuite) TestActivate_UnknownVersion() {
+	suite.mockRepo.On("Activate", prompt.SuggestProducts, 9).Return(false, nil)
+	_, err := suite.promptService.Activate(context.Background(), prompt.SuggestProducts, 9)
+	assert.Equal(suite.T(), service.ErrPromptTemplateNotFound, err)
+}
+func (suite *PromptServiceTestSuite) TestPreview_RendersRequestedVersion() {
+	suite.mockRepo.On("GetVersion", prompt.SuggestProducts, 2).Return(suite.override(2), nil)
+	version := 2
+	rendered, err := suite.promptService.Preview(context.Background(), prompt.SuggestProducts, &service.PromptPreviewRequest{
+		Version: &version,
+		Summary: "Headache and mild fever",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Suggest products for: Headache and mild fever", rendered.User)
+	assert.Equal(suite.T(), "gpt-4o", rendered.Model)
+	assert.Equal(suite.T(), 2, rendered.Version)
+}
+func TestPromptServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PromptServiceTestSuite))
+}
@@ -0,0 +1,243 @@
+package service
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/repository"
+	"gorm.io/gorm"
+)
+const (
+	storeQueueLimit    = 200
+	inventoryPageLimit = 100
+)
+type StoreRequest struct {
+	Name                string                      `json:"name" binding:"required,max=120"`
+	Phone               string                      `json:"phone" binding:"max=32"`
+	Address             *AddressInput               `json:"address,omitempty"`
+	Timezone            string                      `json:"timezone" binding:"max=64"`
+	Hours               []domain.DailyHours         `json:"hours"`
+	DeliveryPreferences []domain.DeliveryPreference `json:"delivery_preferences" binding:"dive,oneof=IN_STORE DELIVERY CURBSIDE"`
+	Active              *bool                       `json:"active,omitempty"`
+}
+type StoreQueueQuery struct {
+	Status []string `form:"status" binding:"dive,oneof=pending processing completed cancelled"`
+}
+type InventoryQuery struct {
+	Search string `form:"q" binding:"max=100"`
+}
+type InventoryItemRequest struct {
+	Name     string  `json:"name" binding:"required,max=255"`
+	Quantity int     `json:"quantity" binding:"min=0"`
+	Price    float64 `json:"price" binding:"min=0"`
+}
+type StoreDirectory interface {
+	ResolveStore(ctx context.Context, storeID *uint) (*domain.Store, error)
+	StaffStoreID(ctx context.Context, userID uint) (*uint, error)
+}
+type StoreService interface {
+	StoreDirectory
+	ListStores(ctx context.Context, includeInactive bool) ([]*domain.Store, error)
+	GetStore(ctx context.Context, storeID uint) (*domain.Store, error)
+	CreateStore(ctx context.Context, req *StoreRequest) (*domain.Store, error)
+	UpdateStore(ctx context.Context, storeID uint, req *StoreRequest) (*domain.Store, error)
+	ListStaff(ctx context.Context, storeID uint) ([]*domain.User, error)
+	AssignStaff(ctx context.Context, storeID, userID uint) (*domain.User, error)
+	RemoveStaff(ctx context.Context, storeID, userID uint) error
+	OrderQueue(ctx context.Context, storeID uint, query *StoreQueueQuery) ([]*domain.Order, error)
+	ListInventory(ctx context.Context, storeID uint, query *InventoryQuery) ([]*domain.InventoryItem, error)
+	UpsertInventoryItem(ctx context.Context, storeID uint, sku string, req *InventoryItemRequest) (*domain.InventoryItem, error)
+}
+type storeService struct {
+	storeRepo     repository.StoreRepository
+	userRepo      repository.UserRepository
+	orderRepo     repository.OrderRepository
+	inventoryRepo repository.InventoryRepository
+}
+func NewStoreService(storeRepo repository.StoreRepository, userRepo repository.UserRepository, orderRepo repository.OrderRepository, inventoryRepo repository.InventoryRepository) StoreService {
+	return &storeService{
+		storeRepo:     storeRepo,
+		userRepo:      userRepo,
+		orderRepo:     orderRepo,
+		inventoryRepo: inventoryRepo,
+	}
+}
+func (s *storeService) ResolveStore(ctx context.Context, storeID *uint) (*domain.Store, error) {
+	if storeID == nil {
+		store, err := s.storeRepo.GetDefault(ctx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	store, err := s.storeRepo.GetByID(ctx, *storeID)
+	if err != nil || !store.Active {
+		return nil, ErrStoreNotFound
+	}
+	return store, nil
+}
+func (s *storeService) ListStores(ctx context.Context, includeInactive bool) ([]*domain.Store, error) {
+	if includeInactive {
+		return s.storeRepo.ListAll(ctx)
+	}
+	return s.storeRepo.List(ctx)
+}
+func (s *storeService) GetStore(ctx context.Context, storeID uint) (*domain.Store, error) {
+	store, err := s.storeRepo.GetByID(ctx, storeID)
+	if err != nil {
+		return nil, ErrStoreNotFound
+	}
+	return store, nil
+}
+func (s *storeService) CreateStore(ctx context.Context, req *StoreRequest) (*domain.Store, error) {
+	store := &domain.Store{Active: true}
+	if err := applyStoreRequest(store, req); err != nil {
+		return nil, err
+	}
+	if err := s.storeRepo.Create(ctx, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+func (s *storeService) UpdateStore(ctx context.Context, storeID uint, req *StoreRequest) (*domain.Store, error) {
+	store, err := s.GetStore(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyStoreRequest(store, req); err != nil {
+		return nil, err
+	}
+	if err := s.storeRepo.Update(ctx, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+func (s *storeService) ListStaff(ctx context.Context, storeID uint) ([]*domain.User, error) {
+	if _, err := s.GetStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return s.storeRepo.ListStaff(ctx, storeID)
+}
+func (s *storeService) AssignStaff(ctx context.Context, storeID, userID uint) (*domain.User, error) {
+	if _, err := s.GetStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.Role.IsStaff() {
+		return nil, ErrNotStaff
+	}
+	user.StoreID = &storeID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+func (s *storeService) RemoveStaff(ctx context.Context, storeID, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.StoreID == nil || *user.StoreID != storeID {
+		return ErrUserNotFound
+	}
+	user.StoreID = nil
+	return s.userRepo.Update(ctx, user)
+}
+func (s *storeService) StaffStoreID(ctx context.Context, userID uint) (*uint, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user.StoreID, nil
+}
+func (s *storeService) OrderQueue(ctx context.Context, storeID uint, query *StoreQueueQuery) ([]*domain.Order, error) {
+	if _, err := s.GetStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	statuses := []domain.OrderStatus{domain.OrderStatusPending, domain.OrderStatusProcessing}
+	if len(query.Status) > 0 {
+		statuses = statuses[:0]
+		for _, status := range query.Status {
+			statuses = append(statuses, domain.OrderStatus(status))
+		}
+	}
+	return s.orderRepo.ListByStore(ctx, storeID, statuses, storeQueueLimit)
+}
+func (s *storeService) ListInventory(ctx context.Context, storeID uint, query *InventoryQuery) ([]*domain.InventoryItem, error) {
+	if _, err := s.GetStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	return s.inventoryRepo.ListByStore(ctx, storeID, query.Search, inventoryPageLimit)
+}
+func (s *storeService) UpsertInventoryItem(ctx context.Context, storeID uint, sku string, req *InventoryItemRequest) (*domain.InventoryItem, error) {
+	if _, err := s.GetStore(ctx, storeID); err != nil {
+		return nil, err
+	}
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	if sku == "" || len(sku) > 64 {
+		return nil, ErrInvalidSKU
+	}
+	item := &domain.InventoryItem{
+		StoreID:  storeID,
+		SKU:      sku,
+		Name:     strings.TrimSpace(req.Name),
+		Quantity: req.Quantity,
+		Price:    req.Price,
+	}
+	if err := s.inventoryRepo.Upsert(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.inventoryRepo.GetBySKU(ctx, storeID, sku)
+}
+func applyStoreRequest(store *domain.Store, req *StoreRequest) error {
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+	if !validOpeningHours(req.Hours) {
+		return ErrInvalidOpeningHours
+	}
+	if req.Address != nil {
+		normalized, err := req.Address.normalize()
+		if err != nil {
+			return err
+		}
+		store.Address = &domain.AddressSnapshot{PostalAddress: *normalized}
+	}
+	preferences := domain.StringList{}
+	for _, pref := range req.DeliveryPreferences {
+		preferences = append(preferences, string(pref))
+	}
+	store.Name = strings.TrimSpace(req.Name)
+	store.Phone = strings.TrimSpace(req.Phone)
+	store.Timezone = timezone
+	store.Hours = domain.OpeningHours(req.Hours)
+	store.DeliveryPreferences = preferences
+	if req.Active != nil {
+		store.Active = *req.Active
+	}
+	return nil
+}
+func validOpeningHours(hours []domain.DailyHours) bool {
+	for _, h := range hours {
+		open, err := domain.ParseClock(h.Open)
+		if err != nil {
+			return false
+		}
+		closing, err := domain.ParseClock(h.Close)
+		if err != nil || closing <= open || h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockStoreRepository struct {
+	mock.Mock
+}
+
+func (m *MockStoreRepository) Create(ctx context.Context, store *domain.Store) error {
+	args := m.Called(store)
+	return args.Error(0)
+}
+func (m *MockStoreRepository) GetByID(ctx context.Context, id uint) (*domain.Store, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+func (m *MockStoreRepository) GetDefault(ctx context.Context) (*domain.Store, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+func (m *MockStoreRepository) List(ctx context.Context) ([]*domain.Store, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.Store), args.Error(1)
+}
+func (m *MockStoreRepository) ListAll(ctx context.Context) ([]*domain.Store, error) {
+	args := m.Called()
+	return args.Get(0).([]*domain.Store), args.Error(1)
+}
+func (m *MockStoreRepository) Update(ctx context.Context, store *domain.Store) error {
+	args := m.Called(store)
+	return args.Error(0)
+}
+func (m *MockStoreRepository) ListStaff(ctx context.Context, storeID uint) ([]*domain.User, error) {
+	args := m.Called(storeID)
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+type MockInventoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) ListByStore(ctx context.Context, storeID uint, search string, limit int) ([]*domain.InventoryItem, error) {
+	args := m.Called(storeID, search, limit)
+	return args.Get(0).([]*domain.InventoryItem), args.Error(1)
+}
+func (m *MockInventoryRepository) GetBySKU(ctx context.Context, storeID uint, sku string) (*domain.InventoryItem, error) {
+	args := m.Called(storeID, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.InventoryItem), args.Error(1)
+}
+func (m *MockInventoryRepository) Upsert(ctx context.Context, item *domain.InventoryItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+type StoreServiceTestSuite struct {
+	suite.Suite
+	storeService      service.StoreService
+	mockStoreRepo     *MockStoreRepository
+	mockUserRepo      *MockUserRepository
+	mockOrderRepo     *MockOrderRepository
+	mockInventoryRepo *MockInventoryRepository
+}
+
+func (suite *StoreServiceTestSuite) SetupTest() {
+	suite.mockStoreRepo = new(MockStoreRepository)
+	suite.mockUserRepo = new(MockUserRepository)
+	suite.mockOrderRepo = new(MockOrderRepository)
+	suite.mockInventoryRepo = new(MockInventoryRepository)
+	suite.storeService = service.NewStoreService(suite.mockStoreRepo, suite.mockUserRepo, suite.mockOrderRepo, suite.mockInventoryRepo)
+	suite.mockStoreRepo.On("GetByID", uint(1)).Return(&domain.Store{ID: 1, Name: "Midtown", Active: true}, nil).Maybe()
+	suite.mockStoreRepo.On("GetByID", uint(2)).Return(&domain.Store{ID: 2, Name: "Closed branch", Active: false}, nil).Maybe()
+	suite.mockStoreRepo.On("GetByID", uint(99)).Return(nil, errors.New("record not found")).Maybe()
+}
+func (suite *StoreServiceTestSuite) TestResolveStore() {
+	suite.mockStoreRepo.On("GetDefault").Return(&domain.Store{ID: 1, Active: true}, nil).Once()
+	store, err := suite.storeService.ResolveStore(context.Background(), nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), store.ID)
+	inactive := uint(2)
+	_, err = suite.storeService.ResolveStore(context.Background(), &inactive)
+	assert.Equal(suite.T(), service.ErrStoreNotFound, err)
+	suite.mockStoreRepo.On("GetDefault").Return(nil, gorm.ErrRecordNotFound).Once()
+	store, err = suite.storeService.ResolveStore(context.Background(), nil)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), store)
+	dbErr := errors.New("connection refused")
+	suite.mockStoreRepo.On("GetDefault").Return(nil, dbErr).Once()
+	store, err = suite.storeService.ResolveStore(context.Background(), nil)
+	assert.Equal(suite.T(), dbErr, err)
+	assert.Nil(suite.T(), store)
+}
+func (suite *StoreServiceTestSuite) TestCreateStore_NormalizesInput() {
+	suite.mockStoreRepo.On("Create", mock.AnythingOfType("*domain.Store")).Return(nil)
+	store, err := suite.storeService.CreateStore(context.Background(), &service.StoreRequest{
+		Name:                " Brooklyn Heights ",
+		Address:             &service.AddressInput{Line1: "142 Montague St", City: "Brooklyn", PostalCode: "11201", Country: "us"},
+		Timezone:            "America/New_York",
+		Hours:               []domain.DailyHours{{Weekday: time.Monday, Open: "09:00", Close: "18:00"}},
+		DeliveryPreferences: []domain.DeliveryPreference{domain.DeliveryPreferenceInStore},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Brooklyn Heights", store.Name)
+	assert.Equal(suite.T(), "US", store.Address.Country)
+	assert.True(suite.T(), store.Active)
+	assert.True(suite.T(), store.Supports(domain.DeliveryPreferenceInStore))
+	assert.False(suite.T(), store.Supports(domain.DeliveryPreferenceCurbside))
+}
+func (suite *StoreServiceTestSuite) TestCreateStore_Invalid() {
+	_, err := suite.storeService.CreateStore(context.Background(), &service.StoreRequest{Name: "Nowhere", Timezone: "Mars/Olympus"})
+	assert.Equal(suite.T(), service.ErrInvalidTimezone, err)
+	_, err = suite.storeService.CreateStore(context.Background(), &service.StoreRequest{
+		Name:  "Backwards",
+		Hours: []domain.DailyHours{{Weekday: time.Monday, Open: "18:00", Close: "09:00"}},
+	})
+	assert.Equal(suite.T(), service.ErrInvalidOpeningHours, err)
+	suite.mockStoreRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+func (suite *StoreServiceTestSuite) TestAssignStaff() {
+	pharmacist := &domain.User{ID: 5, Role: domain.UserRolePharmacist}
+	suite.mockUserRepo.On("GetByID", uint(5)).Return(pharmacist, nil)
+	suite.mockUserRepo.On("Update", pharmacist).Return(nil)
+	user, err := suite.storeService.AssignStaff(context.Background(), 1, 5)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), *user.StoreID)
+	storeID, err := suite.storeService.StaffStoreID(context.Background(), 5)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), *storeID)
+}
+func (suite *StoreServiceTestSuite) TestAssignStaff_CustomerRejected() {
+	suite.mockUserRepo.On("GetByID", uint(6)).Return(&domain.User{ID: 6, Role: domain.UserRoleCustomer}, nil)
+	_, err := suite.storeService.AssignStaff(context.Background(), 1, 6)
+	assert.Equal(suite.T(), service.ErrNotStaff, err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+func (suite *StoreServiceTestSuite) TestOrderQueue_DefaultsToOpenOrders() {
+	open := []domain.OrderStatus{domain.OrderStatusPending, domain.OrderStatusProcessing}
+	suite.mockOrderRepo.On("ListByStore", uint(1), open, mock.Anything).Return([]*domain.Order{{ID: 3}}, nil)
+	orders, err := suite.storeService.OrderQueue(context.Background(), 1, &service.StoreQueueQuery{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), orders, 1)
+	_, err = suite.storeService.OrderQueue(context.Background(), 99, &service.StoreQueueQuery{})
+	assert.Equal(suite.T(), service.ErrStoreNotFound, err)
+}
+func (suite *StoreServiceTestSuite) TestUpsertInventoryItem() {
+	suite.mockInventoryRepo.On("Upsert", mock.MatchedBy(func(item *domain.InventoryItem) bool {
+		return item.StoreID == 1 && item.SKU == "IBU-200" && item.Quantity == 12
+	})).Return(nil)
+	suite.mockInventoryRepo.On("GetBySKU", uint(1), "IBU-200").Return(&domain.InventoryItem{ID: 8, StoreID: 1, SKU: "IBU-200", Quantity: 12}, nil)
+	item, err := suite.storeService.UpsertInventoryItem(context.Background(), 1, " ibu-200 ", &service.InventoryItemRequest{Name: "Ibuprofen 200mg", Quantity: 12, Price: 4.5})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(8), item.ID)
+	_, err = suite.storeService.UpsertInventoryItem(context.Background(), 1, "  ", &service.InventoryItemRequest{Name: "Blank"})
+	assert.Equal(suite.T(), service.ErrInvalidSKU, err)
+}
+func TestStoreServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreServiceTestSuite))
+}
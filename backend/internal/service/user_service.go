@@ -1,15 +1,18 @@
 package service
 import (
+	"context"
+	"errors"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/pagination"
 	"weel-backend/internal/repository"
 )
 type UserService interface {
-	CreateUser(req *CreateUserRequest) (*domain.User, error)
-	GetUserByID(id uint) (*domain.User, error)
-	GetUserByEmail(email string) (*domain.User, error)
-	UpdateUser(id uint, req *UpdateUserRequest) (*domain.User, error)
-	DeleteUser(id uint) error
-	ListUsers(limit, offset int) ([]*domain.User, int64, error)
+	CreateUser(ctx context.Context, req *CreateUserRequest) (*domain.User, error)
+	GetUserByID(ctx context.Context, id uint) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	UpdateUser(ctx context.Context, id uint, req *UpdateUserRequest) (*domain.User, error)
+	DeleteUser(ctx context.Context, id uint) error
+	ListUsers(ctx context.Context, params pagination.Params) ([]*domain.User, *pagination.Page, error)
 }
 type CreateUserRequest struct {
 	Email     string `json:"email" binding:"required,email"`
@@ -28,8 +31,8 @@ type userService struct {
 func NewUserService(userRepo repository.UserRepository) UserService {
 	return &userService{userRepo: userRepo}
 }
-func (s *userService) CreateUser(req *CreateUserRequest) (*domain.User, error) {
-	existingUser, _ := s.userRepo.GetByEmail(req.Email)
+func (s *userService) CreateUser(ctx context.Context, req *CreateUserRequest) (*domain.User, error) {
+	existingUser, _ := s.userRepo.GetByEmail(ctx, req.Email)
 	if existingUser != nil {
 		return nil, ErrEmailExists
 	}
@@ -42,33 +45,34 @@ func (s *userService) CreateUser(req *CreateUserRequest) (*domain.User, error) {
 		Password:  hashedPassword,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		Role:      domain.UserRoleCustomer,
 	}
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
-func (s *userService) GetUserByID(id uint) (*domain.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
-func (s *userService) GetUserByEmail(email string) (*domain.User, error) {
-	user, err := s.userRepo.GetByEmail(email)
+func (s *userService) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 	return user, nil
 }
-func (s *userService) UpdateUser(id uint, req *UpdateUserRequest) (*domain.User, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) UpdateUser(ctx context.Context, id uint, req *UpdateUserRequest) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 	if req.Email != nil && *req.Email != user.Email {
-		existingUser, _ := s.userRepo.GetByEmail(*req.Email)
+		existingUser, _ := s.userRepo.GetByEmail(ctx, *req.Email)
 		if existingUser != nil {
 			return nil, ErrEmailExists
 		}
@@ -80,26 +84,25 @@ func (s *userService) UpdateUser(id uint, req *UpdateUserRequest) (*domain.User,
 	if req.LastName != nil {
 		user.LastName = *req.LastName
 	}
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
-func (s *userService) DeleteUser(id uint) error {
-	_, err := s.userRepo.GetByID(id)
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+	_, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return ErrUserNotFound
 	}
-	return s.userRepo.Delete(id)
+	return s.userRepo.Delete(ctx, id)
 }
-func (s *userService) ListUsers(limit, offset int) ([]*domain.User, int64, error) {
-	users, err := s.userRepo.List(limit, offset)
+func (s *userService) ListUsers(ctx context.Context, params pagination.Params) ([]*domain.User, *pagination.Page, error) {
+	users, page, err := s.userRepo.ListPage(ctx, params)
 	if err != nil {
-		return nil, 0, err
-	}
-	total, err := s.userRepo.Count()
-	if err != nil {
-		return nil, 0, err
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return nil, nil, ErrInvalidCursor
+		}
+		return nil, nil, err
 	}
-	return users, total, nil
+	return users, page, nil
 }
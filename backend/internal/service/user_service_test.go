@@ -1,7 +1,9 @@
 package service_test
 import (
+	"context"
 	"testing"
 	"weel-backend/internal/domain"
+	"weel-backend/internal/pagination"
 	"weel-backend/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -10,40 +12,47 @@ import (
 type MockUserRepository struct {
 	mock.Mock
 }
-func (m *MockUserRepository) Create(user *domain.User) error {
+func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
-func (m *MockUserRepository) GetByID(id uint) (*domain.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
-func (m *MockUserRepository) GetByEmail(email string) (*domain.User, error) {
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.User), args.Error(1)
 }
-func (m *MockUserRepository) Update(user *domain.User) error {
+func (m *MockUserRepository) Update(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
-func (m *MockUserRepository) Delete(id uint) error {
+func (m *MockUserRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
-func (m *MockUserRepository) List(limit, offset int) ([]*domain.User, error) {
+func (m *MockUserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
 	args := m.Called(limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.User), args.Error(1)
 }
-func (m *MockUserRepository) Count() (int64, error) {
+func (m *MockUserRepository) ListPage(ctx context.Context, params pagination.Params) ([]*domain.User, *pagination.Page, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*domain.User), args.Get(1).(*pagination.Page), args.Error(2)
+}
+func (m *MockUserRepository) Count(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -64,7 +73,7 @@ func (suite *UserServiceTestSuite) TestCreateUser_Success() {
 	}
 	suite.mockRepo.On("GetByEmail", "test@example.com").Return(nil, assert.AnError)
 	suite.mockRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
-	user, err := suite.userService.CreateUser(req)
+	user, err := suite.userService.CreateUser(context.Background(), req)
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), user)
 	assert.Equal(suite.T(), req.Email, user.Email)
@@ -78,7 +87,7 @@ func (suite *UserServiceTestSuite) TestCreateUser_EmailExists() {
 	}
 	existingUser := &domain.User{ID: 1, Email: "test@example.com"}
 	suite.mockRepo.On("GetByEmail", "test@example.com").Return(existingUser, nil)
-	user, err := suite.userService.CreateUser(req)
+	user, err := suite.userService.CreateUser(context.Background(), req)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), user)
 	assert.Equal(suite.T(), service.ErrEmailExists, err)
@@ -92,14 +101,14 @@ func (suite *UserServiceTestSuite) TestGetUserByID_Success() {
 		LastName:  "User",
 	}
 	suite.mockRepo.On("GetByID", uint(1)).Return(expectedUser, nil)
-	user, err := suite.userService.GetUserByID(1)
+	user, err := suite.userService.GetUserByID(context.Background(), 1)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expectedUser.ID, user.ID)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 func (suite *UserServiceTestSuite) TestGetUserByID_NotFound() {
 	suite.mockRepo.On("GetByID", uint(1)).Return(nil, assert.AnError)
-	user, err := suite.userService.GetUserByID(1)
+	user, err := suite.userService.GetUserByID(context.Background(), 1)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), user)
 	assert.Equal(suite.T(), service.ErrUserNotFound, err)
@@ -117,7 +126,7 @@ func (suite *UserServiceTestSuite) TestUpdateUser_Success() {
 	}
 	suite.mockRepo.On("GetByID", uint(1)).Return(existingUser, nil)
 	suite.mockRepo.On("Update", mock.AnythingOfType("*domain.User")).Return(nil)
-	user, err := suite.userService.UpdateUser(1, req)
+	user, err := suite.userService.UpdateUser(context.Background(), 1, req)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "Updated", user.FirstName)
 	suite.mockRepo.AssertExpectations(suite.T())
@@ -126,7 +135,7 @@ func (suite *UserServiceTestSuite) TestDeleteUser_Success() {
 	existingUser := &domain.User{ID: 1}
 	suite.mockRepo.On("GetByID", uint(1)).Return(existingUser, nil)
 	suite.mockRepo.On("Delete", uint(1)).Return(nil)
-	err := suite.userService.DeleteUser(1)
+	err := suite.userService.DeleteUser(context.Background(), 1)
 	assert.NoError(suite.T(), err)
 	suite.mockRepo.AssertExpectations(suite.T())
 }
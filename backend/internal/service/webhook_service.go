@@ -0,0 +1,455 @@
+package service
+import (
+	"context"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+	"weel-backend/config"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/events"
+	"weel-backend/internal/repository"
+)
+const (
+	WebhookSignatureHeader = "X-Weel-Signature"
+	WebhookEventHeader     = "X-Weel-Event"
+	WebhookDeliveryHeader  = "X-Weel-Delivery"
+	WebhookTimestampHeader = "X-Weel-Timestamp"
+)
+var webhookRetrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	12 * time.Hour,
+}
+const (
+	webhookPollInterval  = 15 * time.Second
+	webhookClaimLease    = 2 * time.Minute
+	webhookClaimBatch    = 50
+	webhookMaxBodyLogged = 1024
+)
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, userID uint, req *CreateWebhookRequest) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id, userID uint) (*domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id, userID uint, req *UpdateWebhookRequest) (*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id, userID uint) error
+	ListDeliveries(ctx context.Context, subscriptionID, userID uint, limit int) ([]*domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, subscriptionID, deliveryID, userID uint) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID, userID uint) (*domain.WebhookDelivery, error)
+	HandleEvent(ctx context.Context, event events.Event)
+	DeliverDue(ctx context.Context) int
+	Start()
+	Stop()
+}
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Secret      string   `json:"secret,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+type UpdateWebhookRequest struct {
+	URL         *string   `json:"url,omitempty"`
+	Events      *[]string `json:"events,omitempty"`
+	Secret      *string   `json:"secret,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+}
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	cfg         config.WebhookConfig
+	client      *http.Client
+	now         func() time.Time
+	wake        chan struct{}
+	stop        chan struct{}
+	wg          sync.WaitGroup
+	startOnce   sync.Once
+	stopOnce    sync.Once
+}
+func NewWebhookService(webhookRepo repository.WebhookRepository, cfg config.WebhookConfig, client *http.Client) WebhookService {
+	if client == nil {
+		client = newWebhookHTTPClient(cfg)
+	}
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		cfg:         cfg,
+		client:      client,
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+	}
+}
+func newWebhookHTTPClient(cfg config.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = guardWebhookDial
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+func guardWebhookDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, host)
+	}
+	return nil
+}
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || carrierGradeNAT.Contains(ip))
+}
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+func (s *webhookService) CreateSubscription(ctx context.Context, userID uint, req *CreateWebhookRequest) (*domain.WebhookSubscription, error) {
+	if err := s.validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+	sub := &domain.WebhookSubscription{
+		UserID:      userID,
+		URL:         req.URL,
+		Events:      domain.StringList(req.Events),
+		Secret:      secret,
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+func (s *webhookService) ListSubscriptions(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptionsByUserID(ctx, userID)
+}
+func (s *webhookService) GetSubscription(ctx context.Context, id, userID uint) (*domain.WebhookSubscription, error) {
+	sub, err := s.webhookRepo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	if sub.UserID != userID {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+func (s *webhookService) UpdateSubscription(ctx context.Context, id, userID uint, req *UpdateWebhookRequest) (*domain.WebhookSubscription, error) {
+	sub, err := s.GetSubscription(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL != nil {
+		if err := s.validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *req.URL
+	}
+	if req.Events != nil {
+		if err := validateWebhookEvents(*req.Events); err != nil {
+			return nil, err
+		}
+		sub.Events = domain.StringList(*req.Events)
+	}
+	if req.Secret != nil && *req.Secret != "" {
+		sub.Secret = *req.Secret
+	}
+	if req.Description != nil {
+		sub.Description = *req.Description
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := s.webhookRepo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+func (s *webhookService) DeleteSubscription(ctx context.Context, id, userID uint) error {
+	if _, err := s.GetSubscription(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.webhookRepo.DeleteSubscription(ctx, id)
+}
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID, userID uint, limit int) ([]*domain.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID, userID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.webhookRepo.ListDeliveriesBySubscriptionID(ctx, subscriptionID, limit)
+}
+func (s *webhookService) GetDelivery(ctx context.Context, subscriptionID, deliveryID, userID uint) (*domain.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID, userID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil || delivery.SubscriptionID != subscriptionID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+func (s *webhookService) Redeliver(ctx context.Context, subscriptionID, deliveryID, userID uint) (*domain.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID, userID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil || delivery.SubscriptionID != subscriptionID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	queued, err := s.webhookRepo.RequeueDelivery(ctx, delivery.ID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if !queued {
+		return nil, ErrWebhookDeliveryInProgress
+	}
+	s.notify()
+	return s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+}
+func (s *webhookService) HandleEvent(ctx context.Context, event events.Event) {
+	if event.Order == nil {
+		return
+	}
+	subs, err := s.webhookRepo.ListActiveSubscriptionsByUserID(ctx, event.Order.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load webhook subscriptions", "event_type", event.Type, "error", err)
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to serialize webhook payload", "event_type", event.Type, "error", err)
+		return
+	}
+	queued := 0
+	for _, sub := range subs {
+		if sub.UserID != event.Order.UserID || !sub.Events.Contains(event.Type) {
+			continue
+		}
+		now := s.now()
+		delivery := &domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         domain.WebhookDeliveryStatusPending,
+			NextAttemptAt:  &now,
+		}
+		if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			slog.ErrorContext(ctx, "failed to queue webhook delivery", "subscription_id", sub.ID, "error", err)
+			continue
+		}
+		queued++
+	}
+	if queued > 0 {
+		s.notify()
+	}
+}
+func (s *webhookService) DeliverDue(ctx context.Context) int {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, s.now(), webhookClaimLease, webhookClaimBatch)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim webhook deliveries", "error", err)
+		return 0
+	}
+	subs := make(map[uint]*domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = s.webhookRepo.GetSubscriptionByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				sub = nil
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		if sub == nil || !sub.Active {
+			msg := "subscription removed or inactive"
+			delivery.Status = domain.WebhookDeliveryStatusFailed
+			delivery.LastError = &msg
+			delivery.NextAttemptAt = nil
+			if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+				slog.ErrorContext(ctx, "failed to update webhook delivery", "delivery_id", delivery.ID, "error", err)
+			}
+			continue
+		}
+		s.attempt(ctx, sub, delivery, true)
+	}
+	return len(deliveries)
+}
+func (s *webhookService) Start() {
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+func (s *webhookService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
+func (s *webhookService) run() {
+	defer s.wg.Done()
+	ctx := context.Background()
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		for {
+			if s.DeliverDue(ctx) < webhookClaimBatch {
+				break
+			}
+		}
+	}
+}
+func (s *webhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+func (s *webhookService) attempt(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery, retry bool) {
+	delivery.AttemptCount++
+	record := &domain.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.AttemptCount,
+	}
+	started := s.now()
+	code, body, err := s.send(ctx, sub, delivery, started)
+	record.DurationMs = time.Since(started).Milliseconds()
+	if code != 0 {
+		record.ResponseCode = &code
+		delivery.LastResponseCode = &code
+	}
+	if body != "" {
+		record.ResponseBody = &body
+	}
+	if err == nil && (code < 200 || code >= 300) {
+		err = fmt.Errorf("unexpected response status %d", code)
+	}
+	if err != nil {
+		msg := err.Error()
+		record.Error = &msg
+		delivery.LastError = &msg
+		if retry && delivery.AttemptCount <= len(webhookRetrySchedule) {
+			next := s.now().Add(webhookRetrySchedule[delivery.AttemptCount-1])
+			delivery.Status = domain.WebhookDeliveryStatusPending
+			delivery.NextAttemptAt = &next
+		} else {
+			delivery.Status = domain.WebhookDeliveryStatusFailed
+			delivery.NextAttemptAt = nil
+		}
+	} else {
+		now := s.now()
+		delivery.Status = domain.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+	}
+	if err := s.webhookRepo.CreateAttempt(ctx, record); err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook attempt", "delivery_id", delivery.ID, "error", err)
+	}
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "failed to update webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+func (s *webhookService) send(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery, at time.Time) (int, string, error) {
+	if err := s.validateWebhookURL(sub.URL); err != nil {
+		return 0, "", err
+	}
+	payload := []byte(delivery.Payload)
+	timestamp := at.Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "weel-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, timestamp, payload))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxBodyLogged))
+	return resp.StatusCode, string(body), nil
+}
+func (s *webhookService) validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidWebhookURL
+	}
+	if s.cfg.RequireHTTPS && u.Scheme != "https" {
+		return ErrInvalidWebhookURL
+	}
+	if s.cfg.AllowPrivateNetworks {
+		return nil
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublicIP(ip) {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+func validateWebhookEvents(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return ErrInvalidWebhookEvent
+	}
+	for _, t := range eventTypes {
+		if !events.IsOrderEventType(t) {
+			return ErrInvalidWebhookEvent
+		}
+	}
+	return nil
+}
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
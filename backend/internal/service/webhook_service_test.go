@@ -0,0 +1,307 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+	"weel-backend/config"
+	"weel-backend/internal/domain"
+	"weel-backend/internal/events"
+	"weel-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+func (m *MockWebhookRepository) GetSubscriptionByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+func (m *MockWebhookRepository) ListSubscriptionsByUserID(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*domain.WebhookSubscription), args.Error(1)
+}
+func (m *MockWebhookRepository) ListActiveSubscriptionsByUserID(ctx context.Context, userID uint) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*domain.WebhookSubscription), args.Error(1)
+}
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+func (m *MockWebhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*domain.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+func (m *MockWebhookRepository) ListDeliveriesBySubscriptionID(ctx context.Context, subscriptionID uint, limit int) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(subscriptionID, limit)
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(now, lease, limit)
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+func (m *MockWebhookRepository) RequeueDelivery(ctx context.Context, id uint, now time.Time) (bool, error) {
+	args := m.Called(id, now)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+func (m *MockWebhookRepository) CreateAttempt(ctx context.Context, attempt *domain.WebhookDeliveryAttempt) error {
+	args := m.Called(attempt)
+	return args.Error(0)
+}
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	webhookService service.WebhookService
+	mockRepo       *MockWebhookRepository
+}
+
+func (suite *WebhookServiceTestSuite) SetupTest() {
+	suite.mockRepo = new(MockWebhookRepository)
+	suite.webhookService = service.NewWebhookService(suite.mockRepo, config.WebhookConfig{AllowPrivateNetworks: true}, nil)
+}
+func (suite *WebhookServiceTestSuite) TestCreateSubscription_GeneratesSecret() {
+	req := &service.CreateWebhookRequest{
+		URL:    "https://partner.example.com/hooks",
+		Events: []string{events.OrderCreated},
+	}
+	suite.mockRepo.On("CreateSubscription", mock.AnythingOfType("*domain.WebhookSubscription")).Return(nil)
+	sub, err := suite.webhookService.CreateSubscription(context.Background(), 1, req)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), sub.Secret)
+	assert.True(suite.T(), sub.Active)
+	assert.Equal(suite.T(), domain.StringList{events.OrderCreated}, sub.Events)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *WebhookServiceTestSuite) TestCreateSubscription_InvalidEvent() {
+	req := &service.CreateWebhookRequest{
+		URL:    "https://partner.example.com/hooks",
+		Events: []string{"user.deleted"},
+	}
+	sub, err := suite.webhookService.CreateSubscription(context.Background(), 1, req)
+	assert.Nil(suite.T(), sub)
+	assert.Equal(suite.T(), service.ErrInvalidWebhookEvent, err)
+}
+func (suite *WebhookServiceTestSuite) TestCreateSubscription_InvalidURL() {
+	req := &service.CreateWebhookRequest{
+		URL:    "ftp://partner.example.com/hooks",
+		Events: []string{events.OrderCreated},
+	}
+	sub, err := suite.webhookService.CreateSubscription(context.Background(), 1, req)
+	assert.Nil(suite.T(), sub)
+	assert.Equal(suite.T(), service.ErrInvalidWebhookURL, err)
+}
+func (suite *WebhookServiceTestSuite) TestCreateSubscription_RejectsUnsafeURLs() {
+	webhookService := service.NewWebhookService(suite.mockRepo, config.WebhookConfig{RequireHTTPS: true}, nil)
+	for _, raw := range []string{
+		"http://partner.example.com/hooks",
+		"https://127.0.0.1/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hooks",
+		"https://[::1]:8443/hooks",
+		"https://0.0.0.0/hooks",
+	} {
+		sub, err := webhookService.CreateSubscription(context.Background(), 1, &service.CreateWebhookRequest{URL: raw, Events: []string{events.OrderCreated}})
+		assert.Nil(suite.T(), sub, raw)
+		assert.Equal(suite.T(), service.ErrInvalidWebhookURL, err, raw)
+	}
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateSubscription", mock.Anything)
+}
+func (suite *WebhookServiceTestSuite) TestDeliverDue_BlocksPrivateAddressesAtDial() {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	webhookService := service.NewWebhookService(suite.mockRepo, config.WebhookConfig{}, nil)
+	sub := &domain.WebhookSubscription{ID: 1, URL: "http://localhost:" + serverURL.Port(), Secret: "s", Active: true}
+	delivery := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: `{}`}
+	suite.mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.WebhookDelivery{delivery}, nil)
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(sub, nil)
+	suite.mockRepo.On("CreateAttempt", mock.AnythingOfType("*domain.WebhookDeliveryAttempt")).Return(nil)
+	suite.mockRepo.On("UpdateDelivery", delivery).Return(nil)
+	webhookService.DeliverDue(context.Background())
+	assert.False(suite.T(), hit)
+	assert.Equal(suite.T(), domain.WebhookDeliveryStatusPending, delivery.Status)
+	if assert.NotNil(suite.T(), delivery.LastError) {
+		assert.Contains(suite.T(), *delivery.LastError, service.ErrWebhookAddressBlocked.Error())
+	}
+}
+func (suite *WebhookServiceTestSuite) TestGetSubscription_OtherUser() {
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(&domain.WebhookSubscription{ID: 1, UserID: 2}, nil)
+	sub, err := suite.webhookService.GetSubscription(context.Background(), 1, 1)
+	assert.Nil(suite.T(), sub)
+	assert.Equal(suite.T(), service.ErrWebhookNotFound, err)
+}
+func (suite *WebhookServiceTestSuite) TestHandleEvent_QueuesMatchingSubscriptions() {
+	subs := []*domain.WebhookSubscription{
+		{ID: 1, UserID: 1, Events: domain.StringList{events.OrderCreated}, Active: true},
+		{ID: 2, UserID: 1, Events: domain.StringList{events.OrderStatusChanged}, Active: true},
+	}
+	suite.mockRepo.On("ListActiveSubscriptionsByUserID", uint(1)).Return(subs, nil)
+	suite.mockRepo.On("CreateDelivery", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.SubscriptionID == 1 && d.EventType == events.OrderCreated
+	})).Return(nil).Once()
+	order := &domain.Order{ID: 7, UserID: 1, Status: domain.OrderStatusPending}
+	suite.webhookService.HandleEvent(context.Background(), events.NewOrderEvent(events.OrderCreated, order))
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateDelivery", 1)
+}
+func (suite *WebhookServiceTestSuite) TestHandleEvent_OnlyNotifiesOrderOwner() {
+	owner := &domain.WebhookSubscription{ID: 1, UserID: 1, Events: domain.StringList{events.OrderCreated}, Active: true}
+	other := &domain.WebhookSubscription{ID: 2, UserID: 2, Events: domain.StringList{events.OrderCreated}, Active: true}
+	suite.mockRepo.On("ListActiveSubscriptionsByUserID", uint(1)).Return([]*domain.WebhookSubscription{owner}, nil)
+	suite.mockRepo.On("ListActiveSubscriptionsByUserID", uint(2)).Return([]*domain.WebhookSubscription{other}, nil)
+	suite.mockRepo.On("CreateDelivery", mock.AnythingOfType("*domain.WebhookDelivery")).Return(nil)
+	order := &domain.Order{ID: 7, UserID: 1, Status: domain.OrderStatusPending}
+	suite.webhookService.HandleEvent(context.Background(), events.NewOrderEvent(events.OrderCreated, order))
+	suite.mockRepo.AssertNumberOfCalls(suite.T(), "CreateDelivery", 1)
+	suite.mockRepo.AssertCalled(suite.T(), "CreateDelivery", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.SubscriptionID == owner.ID
+	}))
+	suite.mockRepo.AssertNotCalled(suite.T(), "ListActiveSubscriptionsByUserID", uint(2))
+}
+func (suite *WebhookServiceTestSuite) TestDeliverDue_SignsPayload() {
+	secret := "whsec_test"
+	payload := `{"id":"evt_1","type":"order.created"}`
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	sub := &domain.WebhookSubscription{ID: 1, URL: server.URL, Secret: secret, Active: true}
+	delivery := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: payload}
+	suite.mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.WebhookDelivery{delivery}, nil)
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(sub, nil)
+	suite.mockRepo.On("CreateAttempt", mock.MatchedBy(func(a *domain.WebhookDeliveryAttempt) bool {
+		return a.ResponseCode != nil && *a.ResponseCode == http.StatusOK && a.Attempt == 1
+	})).Return(nil)
+	suite.mockRepo.On("UpdateDelivery", delivery).Return(nil)
+	processed := suite.webhookService.DeliverDue(context.Background())
+	assert.Equal(suite.T(), 1, processed)
+	assert.Equal(suite.T(), domain.WebhookDeliveryStatusSucceeded, delivery.Status)
+	assert.NotNil(suite.T(), delivery.DeliveredAt)
+	if assert.NotNil(suite.T(), received) {
+		timestamp, err := strconv.ParseInt(received.Header.Get(service.WebhookTimestampHeader), 10, 64)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), service.SignWebhookPayload(secret, timestamp, []byte(payload)), received.Header.Get(service.WebhookSignatureHeader))
+		assert.Equal(suite.T(), events.OrderCreated, received.Header.Get(service.WebhookEventHeader))
+		assert.JSONEq(suite.T(), payload, string(body))
+	}
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+func (suite *WebhookServiceTestSuite) TestDeliverDue_SchedulesRetryOnFailure() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	sub := &domain.WebhookSubscription{ID: 1, URL: server.URL, Secret: "s", Active: true}
+	delivery := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: `{}`}
+	suite.mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.WebhookDelivery{delivery}, nil)
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(sub, nil)
+	suite.mockRepo.On("CreateAttempt", mock.AnythingOfType("*domain.WebhookDeliveryAttempt")).Return(nil)
+	suite.mockRepo.On("UpdateDelivery", delivery).Return(nil)
+	suite.webhookService.DeliverDue(context.Background())
+	assert.Equal(suite.T(), domain.WebhookDeliveryStatusPending, delivery.Status)
+	assert.Equal(suite.T(), 1, delivery.AttemptCount)
+	assert.Equal(suite.T(), http.StatusBadGateway, *delivery.LastResponseCode)
+	if assert.NotNil(suite.T(), delivery.NextAttemptAt) {
+		assert.True(suite.T(), delivery.NextAttemptAt.After(time.Now()))
+	}
+}
+func (suite *WebhookServiceTestSuite) TestDeliverDue_GivesUpAfterRetries() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	sub := &domain.WebhookSubscription{ID: 1, URL: server.URL, Secret: "s", Active: true}
+	delivery := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: `{}`, AttemptCount: 5}
+	suite.mockRepo.On("ClaimDueDeliveries", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.WebhookDelivery{delivery}, nil)
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(sub, nil)
+	suite.mockRepo.On("CreateAttempt", mock.AnythingOfType("*domain.WebhookDeliveryAttempt")).Return(nil)
+	suite.mockRepo.On("UpdateDelivery", delivery).Return(nil)
+	suite.webhookService.DeliverDue(context.Background())
+	assert.Equal(suite.T(), domain.WebhookDeliveryStatusFailed, delivery.Status)
+	assert.Nil(suite.T(), delivery.NextAttemptAt)
+}
+func (suite *WebhookServiceTestSuite) TestRedeliver_RequeuesForWorker() {
+	sub := &domain.WebhookSubscription{ID: 1, UserID: 1, URL: "https://example.com/hook", Secret: "s", Active: true}
+	delivery := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: `{}`, Status: domain.WebhookDeliveryStatusFailed, AttemptCount: 6}
+	queued := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: `{}`, Status: domain.WebhookDeliveryStatusPending, AttemptCount: 6}
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(sub, nil)
+	suite.mockRepo.On("GetDeliveryByID", uint(10)).Return(delivery, nil).Once()
+	suite.mockRepo.On("RequeueDelivery", uint(10), mock.AnythingOfType("time.Time")).Return(true, nil)
+	suite.mockRepo.On("GetDeliveryByID", uint(10)).Return(queued, nil).Once()
+	result, err := suite.webhookService.Redeliver(context.Background(), 1, 10, 1)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.WebhookDeliveryStatusPending, result.Status)
+	assert.Equal(suite.T(), 6, result.AttemptCount)
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAttempt", mock.Anything)
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateDelivery", mock.Anything)
+}
+func (suite *WebhookServiceTestSuite) TestRedeliver_AlreadyQueued() {
+	sub := &domain.WebhookSubscription{ID: 1, UserID: 1, URL: "https://example.com/hook", Secret: "s", Active: true}
+	delivery := &domain.WebhookDelivery{ID: 10, SubscriptionID: 1, EventType: events.OrderCreated, Payload: `{}`, Status: domain.WebhookDeliveryStatusPending, AttemptCount: 1}
+	suite.mockRepo.On("GetSubscriptionByID", uint(1)).Return(sub, nil)
+	suite.mockRepo.On("GetDeliveryByID", uint(10)).Return(delivery, nil)
+	suite.mockRepo.On("RequeueDelivery", uint(10), mock.AnythingOfType("time.Time")).Return(false, nil)
+	result, err := suite.webhookService.Redeliver(context.Background(), 1, 10, 1)
+	assert.Nil(suite.T(), result)
+	assert.Equal(suite.T(), service.ErrWebhookDeliveryInProgress, err)
+	suite.mockRepo.AssertNotCalled(suite.T(), "CreateAttempt", mock.Anything)
+}
+func (suite *WebhookServiceTestSuite) TestEventPayload_ContainsOrder() {
+	order := &domain.Order{ID: 7, UserID: 1, Summary: "Paracetamol for a fever", Status: domain.OrderStatusPending}
+	event := events.NewOrderEvent(events.OrderCreated, order)
+	data, err := json.Marshal(event)
+	assert.NoError(suite.T(), err)
+	var decoded struct {
+		Type  string       `json:"type"`
+		Order domain.Order `json:"order"`
+	}
+	assert.NoError(suite.T(), json.Unmarshal(data, &decoded))
+	assert.Equal(suite.T(), events.OrderCreated, decoded.Type)
+	assert.Equal(suite.T(), order.ID, decoded.Order.ID)
+	assert.Equal(suite.T(), order.Summary, decoded.Order.Summary)
+}
+func TestWebhookServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
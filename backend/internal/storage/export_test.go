@@ -0,0 +1,8 @@
+package storage
+import "time"
+func (s *LocalStore) SetNow(now func() time.Time) {
+	s.now = now
+}
+func (s *S3Store) SetNow(now func() time.Time) {
+	s.now = now
+}
@@ -0,0 +1,65 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"weel-backend/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	obj, err := store.Put(ctx, "prescriptions/1/rx.pdf", strings.NewReader("%PDF-1.4"), "application/pdf")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), obj.Size)
+	rc, got, err := store.Get(ctx, "prescriptions/1/rx.pdf")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "%PDF-1.4", string(data))
+	assert.Equal(t, "application/pdf", got.ContentType)
+	require.NoError(t, store.Delete(ctx, "prescriptions/1/rx.pdf"))
+	_, _, err = store.Get(ctx, "prescriptions/1/rx.pdf")
+	assert.Equal(t, storage.ErrNotFound, err)
+	assert.NoError(t, store.Delete(ctx, "prescriptions/1/rx.pdf"))
+}
+func TestLocalStore_RejectsTraversal(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	for _, key := range []string{"../etc/passwd", "a/../../b", "", "a\\b"} {
+		_, err := store.Put(context.Background(), key, bytes.NewReader(nil), "")
+		assert.Equal(t, storage.ErrInvalidKey, err, key)
+	}
+}
+func TestLocalStore_SignedURL(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), storage.WithSignedURLs("http://localhost:8080/api/v1/files/", []byte("secret")))
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	store.SetNow(func() time.Time { return now })
+	raw, err := store.SignedURL(context.Background(), "prescriptions/1/rx.pdf", time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/files/prescriptions/1/rx.pdf", u.Path)
+	key := strings.TrimPrefix(u.Path, "/api/v1/files/")
+	q := u.Query()
+	assert.NoError(t, store.VerifySignedURL(key, q.Get("expires"), q.Get("signature")))
+	assert.Equal(t, storage.ErrInvalidSignature, store.VerifySignedURL("prescriptions/2/rx.pdf", q.Get("expires"), q.Get("signature")))
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, storage.ErrInvalidSignature, store.VerifySignedURL(key, q.Get("expires"), q.Get("signature")))
+}
+func TestLocalStore_SignedURLDisabled(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.SignedURL(context.Background(), "a.pdf", time.Minute)
+	assert.Equal(t, storage.ErrSigningDisabled, err)
+}
@@ -0,0 +1,63 @@
+package storage
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"weel-backend/config"
+)
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+var (
+	ErrNotFound         = errors.New("object not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrSigningDisabled  = errors.New("signed urls are not configured for this store")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+}
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (*Object, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		var opts []LocalOption
+		if cfg.PublicURL != "" && cfg.SigningSecret != "" {
+			opts = append(opts, WithSignedURLs(cfg.PublicURL, []byte(cfg.SigningSecret)))
+		}
+		return NewLocalStore(cfg.LocalPath, opts...)
+	case DriverS3:
+		return NewS3Store(S3Options{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			UsePathStyle:    cfg.S3.UsePathStyle,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
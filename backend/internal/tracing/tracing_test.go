@@ -0,0 +1,116 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"weel-backend/config"
+	"weel-backend/internal/middleware"
+	"weel-backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+	return out
+}
+
+func TestSetupExporters(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: "none"})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), config.TracingConfig{Exporter: "jaeger"})
+	assert.Error(t, err)
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+	shutdown, err = tracing.Setup(context.Background(), config.TracingConfig{Exporter: "stdout", ServiceName: "test", SampleRatio: 1})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestTracingMiddlewareRecordsServerSpan(t *testing.T) {
+	recorder := recordSpans(t)
+	tracing.Setup(context.Background(), config.TracingConfig{Exporter: "none"})
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.RequestIDMiddleware(), middleware.TracingMiddleware())
+	var handlerSpan trace.SpanContext
+	engine.GET("/orders/:id", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /orders/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	a := attrs(span)
+	assert.Equal(t, "/orders/:id", a["http.route"].AsString())
+	assert.Equal(t, int64(500), a["http.response.status_code"].AsInt64())
+}
+
+func TestGormPluginRecordsQuerySpans(t *testing.T) {
+	recorder := recordSpans(t)
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(tracing.NewGormPlugin()))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	var w widget
+	db.WithContext(ctx).Where("name = ?", "secret-value").First(&w)
+	db.WithContext(ctx).Create(&widget{Name: "bolt"})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	query, insert := spans[0], spans[1]
+	assert.Equal(t, "db.select widgets", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	a := attrs(query)
+	assert.Equal(t, "postgresql", a["db.system"].AsString())
+	assert.Equal(t, "widgets", a["db.collection.name"].AsString())
+	assert.Contains(t, a["db.query.text"].AsString(), `WHERE name = $1`)
+	assert.NotContains(t, a["db.query.text"].AsString(), "secret-value")
+	assert.Equal(t, "db.create widgets", insert.Name())
+}
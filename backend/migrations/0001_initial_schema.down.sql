@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS prescriptions;
+DROP TABLE IF EXISTS feature_flags;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS pickup_slots;
+DROP TABLE IF EXISTS pickup_configs;
+DROP TABLE IF EXISTS inventory_items;
+DROP TABLE IF EXISTS stores;
+DROP TABLE IF EXISTS delivery_zones;
+DROP TABLE IF EXISTS addresses;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,256 @@
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    email text NOT NULL,
+    password text NOT NULL,
+    first_name text NOT NULL,
+    last_name text NOT NULL,
+    role varchar(20) NOT NULL DEFAULT 'customer',
+    store_id bigint,
+    last_login timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(20) NOT NULL DEFAULT 'customer';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS store_id bigint;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_store_id ON users (store_id);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS addresses (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    label varchar(50),
+    line1 varchar(255) NOT NULL,
+    line2 varchar(255),
+    city varchar(100) NOT NULL,
+    region varchar(100),
+    postal_code varchar(20) NOT NULL,
+    country char(2) NOT NULL,
+    latitude decimal,
+    longitude decimal,
+    is_default boolean NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS latitude decimal;
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS longitude decimal;
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id ON addresses (user_id);
+CREATE INDEX IF NOT EXISTS idx_addresses_deleted_at ON addresses (deleted_at);
+
+CREATE TABLE IF NOT EXISTS delivery_zones (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    country varchar(2),
+    postal_codes jsonb NOT NULL DEFAULT '[]',
+    postal_prefixes jsonb NOT NULL DEFAULT '[]',
+    polygon jsonb,
+    fee numeric(10,2) NOT NULL DEFAULT 0,
+    min_order_amount numeric(10,2) NOT NULL DEFAULT 0,
+    priority bigint NOT NULL DEFAULT 0,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_delivery_zones_deleted_at ON delivery_zones (deleted_at);
+
+CREATE TABLE IF NOT EXISTS stores (
+    id bigserial PRIMARY KEY,
+    name varchar(120) NOT NULL,
+    phone varchar(32),
+    address jsonb,
+    timezone varchar(64) NOT NULL DEFAULT 'UTC',
+    hours jsonb NOT NULL DEFAULT '[]',
+    delivery_preferences jsonb NOT NULL DEFAULT '[]',
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS phone varchar(32);
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS address jsonb;
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS hours jsonb NOT NULL DEFAULT '[]';
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS delivery_preferences jsonb NOT NULL DEFAULT '[]';
+CREATE INDEX IF NOT EXISTS idx_stores_deleted_at ON stores (deleted_at);
+
+CREATE TABLE IF NOT EXISTS inventory_items (
+    id bigserial PRIMARY KEY,
+    store_id bigint NOT NULL,
+    sku varchar(64) NOT NULL,
+    name varchar(255) NOT NULL,
+    quantity bigint NOT NULL DEFAULT 0,
+    price numeric(10,2) NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_store_sku ON inventory_items (store_id, sku);
+CREATE INDEX IF NOT EXISTS idx_inventory_items_deleted_at ON inventory_items (deleted_at);
+
+CREATE TABLE IF NOT EXISTS pickup_configs (
+    id bigserial PRIMARY KEY,
+    store_id bigint NOT NULL,
+    hours jsonb NOT NULL DEFAULT '[]',
+    slot_minutes bigint NOT NULL DEFAULT 15,
+    capacity bigint NOT NULL DEFAULT 4,
+    lead_time_minutes bigint NOT NULL DEFAULT 30,
+    horizon_days bigint NOT NULL DEFAULT 7,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pickup_configs_store_id ON pickup_configs (store_id);
+
+CREATE TABLE IF NOT EXISTS pickup_slots (
+    id bigserial PRIMARY KEY,
+    store_id bigint NOT NULL,
+    starts_at timestamptz NOT NULL,
+    ends_at timestamptz NOT NULL,
+    capacity bigint NOT NULL,
+    reserved bigint NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pickup_slots_store_start ON pickup_slots (store_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    summary text NOT NULL,
+    delivery_preference varchar(20) NOT NULL,
+    delivery_address text,
+    postal_code varchar(20),
+    address_id bigint,
+    shipping_address jsonb,
+    delivery_zone_id bigint,
+    delivery_fee numeric(10,2) NOT NULL DEFAULT 0,
+    store_id bigint,
+    pickup_slot_id bigint,
+    pickup_slot_start timestamptz,
+    pickup_slot_end timestamptz,
+    customer_arrived_at timestamptz,
+    arrival_note text,
+    ai_suggested_products jsonb,
+    total numeric(12,2) NOT NULL DEFAULT 0,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    prescription_status varchar(20),
+    processing_started_at timestamptz,
+    cancellation_reason varchar(40),
+    cancellation_note text,
+    cancelled_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS address_id bigint;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_address jsonb;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_zone_id bigint;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_fee numeric(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS store_id bigint;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pickup_slot_id bigint;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pickup_slot_start timestamptz;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pickup_slot_end timestamptz;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_arrived_at timestamptz;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS arrival_note text;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total numeric(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS prescription_status varchar(20);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS processing_started_at timestamptz;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_reason varchar(40);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_note text;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancelled_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_store_id ON orders (store_id);
+CREATE INDEX IF NOT EXISTS idx_orders_prescription_status ON orders (prescription_status);
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON orders (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_orders_user_created_at ON orders (user_id, created_at DESC, id DESC);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(summary, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(jsonb_path_query_array(ai_suggested_products, '$[*].name')::text, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_orders_search_vector ON orders USING GIN (search_vector);
+UPDATE orders SET total = COALESCE((
+    SELECT ROUND(SUM((p->>'price')::numeric * (p->>'quantity')::numeric), 2)
+    FROM jsonb_array_elements(orders.ai_suggested_products) p
+), 0)
+WHERE total = 0 AND ai_suggested_products IS NOT NULL AND jsonb_typeof(ai_suggested_products) = 'array';
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    description text,
+    enabled boolean NOT NULL DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_feature_flags_name ON feature_flags (name);
+CREATE INDEX IF NOT EXISTS idx_feature_flags_deleted_at ON feature_flags (deleted_at);
+
+CREATE TABLE IF NOT EXISTS prescriptions (
+    id bigserial PRIMARY KEY,
+    order_id bigint NOT NULL,
+    uploaded_by_id bigint NOT NULL,
+    file_key text NOT NULL,
+    file_name varchar(255) NOT NULL,
+    content_type varchar(100) NOT NULL,
+    size_bytes bigint NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'submitted',
+    reviewed_by_id bigint,
+    reviewed_at timestamptz,
+    review_note text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_prescriptions_reviewed_by FOREIGN KEY (reviewed_by_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_prescriptions_order_id ON prescriptions (order_id);
+CREATE INDEX IF NOT EXISTS idx_prescriptions_status ON prescriptions (status);
+CREATE INDEX IF NOT EXISTS idx_prescriptions_deleted_at ON prescriptions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    url text NOT NULL,
+    events jsonb NOT NULL,
+    secret text NOT NULL,
+    description text,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_user_id ON webhook_subscriptions (user_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigserial PRIMARY KEY,
+    subscription_id bigint NOT NULL,
+    event_id varchar(64) NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload jsonb NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    attempt_count bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz,
+    last_response_code bigint,
+    last_error text,
+    delivered_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id bigserial PRIMARY KEY,
+    delivery_id bigint NOT NULL,
+    attempt bigint NOT NULL,
+    response_code bigint,
+    response_body text,
+    error text,
+    duration_ms bigint,
+    created_at timestamptz,
+    CONSTRAINT fk_webhook_deliveries_attempts FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries (id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
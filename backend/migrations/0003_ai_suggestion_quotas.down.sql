@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ai_suggestion_usage;
+DROP TABLE IF EXISTS ai_suggestion_quotas;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS ai_suggestion_quotas (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    daily_limit bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_ai_suggestion_quotas_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_suggestion_quotas_user_id ON ai_suggestion_quotas (user_id);
+
+CREATE TABLE IF NOT EXISTS ai_suggestion_usage (
+    user_id bigint NOT NULL,
+    day date NOT NULL,
+    count bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id, day)
+);
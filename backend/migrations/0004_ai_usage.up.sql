@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id bigserial PRIMARY KEY,
+    user_id bigint,
+    operation varchar(50) NOT NULL,
+    model varchar(100) NOT NULL,
+    prompt_tokens bigint NOT NULL DEFAULT 0,
+    completion_tokens bigint NOT NULL DEFAULT 0,
+    latency_ms bigint NOT NULL DEFAULT 0,
+    outcome varchar(20) NOT NULL,
+    estimated_cost_usd numeric(12,6) NOT NULL DEFAULT 0,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_user_id ON ai_usage (user_id);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_outcome ON ai_usage (outcome);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_created_at ON ai_usage (created_at);
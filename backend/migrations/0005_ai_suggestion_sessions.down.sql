@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_suggestion_session_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS suggestion_session_id;
+DROP TABLE IF EXISTS ai_suggestion_sessions;
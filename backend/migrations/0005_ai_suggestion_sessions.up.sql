@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS ai_suggestion_sessions (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    summary text NOT NULL,
+    delivery_address text,
+    model varchar(100) NOT NULL,
+    raw_output text NOT NULL,
+    products jsonb NOT NULL DEFAULT '[]',
+    order_id bigint,
+    accepted_count bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_ai_suggestion_sessions_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_ai_suggestion_sessions_order FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX IF NOT EXISTS idx_ai_suggestion_sessions_user_id ON ai_suggestion_sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_ai_suggestion_sessions_order_id ON ai_suggestion_sessions (order_id);
+CREATE INDEX IF NOT EXISTS idx_ai_suggestion_sessions_created_at ON ai_suggestion_sessions (created_at);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS suggestion_session_id bigint;
+CREATE INDEX IF NOT EXISTS idx_orders_suggestion_session_id ON orders (suggestion_session_id);
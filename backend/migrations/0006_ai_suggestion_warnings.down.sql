@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ai_suggestion_sessions_requires_review;
+ALTER TABLE ai_suggestion_sessions DROP COLUMN IF EXISTS requires_review;
+ALTER TABLE ai_suggestion_sessions DROP COLUMN IF EXISTS warnings;
@@ -0,0 +1,3 @@
+ALTER TABLE ai_suggestion_sessions ADD COLUMN IF NOT EXISTS warnings jsonb NOT NULL DEFAULT '[]';
+ALTER TABLE ai_suggestion_sessions ADD COLUMN IF NOT EXISTS requires_review boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_ai_suggestion_sessions_requires_review ON ai_suggestion_sessions (requires_review) WHERE requires_review;
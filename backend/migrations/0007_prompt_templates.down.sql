@@ -0,0 +1,3 @@
+ALTER TABLE ai_suggestion_sessions DROP COLUMN IF EXISTS prompt_version;
+ALTER TABLE ai_suggestion_sessions DROP COLUMN IF EXISTS prompt_name;
+DROP TABLE IF EXISTS prompt_templates;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    version bigint NOT NULL,
+    model varchar(100) NOT NULL,
+    temperature real NOT NULL,
+    max_tokens bigint NOT NULL,
+    system_prompt text NOT NULL,
+    user_prompt text NOT NULL,
+    active boolean NOT NULL DEFAULT false,
+    created_by bigint,
+    created_at timestamptz,
+    CONSTRAINT fk_prompt_templates_created_by FOREIGN KEY (created_by) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_templates_name_version ON prompt_templates (name, version);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_templates_active ON prompt_templates (name) WHERE active;
+
+ALTER TABLE ai_suggestion_sessions ADD COLUMN IF NOT EXISTS prompt_name varchar(100);
+ALTER TABLE ai_suggestion_sessions ADD COLUMN IF NOT EXISTS prompt_version bigint;
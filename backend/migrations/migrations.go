@@ -0,0 +1,4 @@
+package migrations
+import "embed"
+//go:embed *.sql
+var FS embed.FS
@@ -34,7 +34,9 @@ const validationSchema = Yup.object({
 
 export const CreateOrderDialog: React.FC<CreateOrderDialogProps> = ({ onClose }) => {
   const dispatch = useAppDispatch();
-  const { aiSuggestions, loading } = useAppSelector((state) => state.orders);
+  const { aiSuggestions, aiSuggestionSessionId, aiSuggestionWarnings, loading } = useAppSelector(
+    (state) => state.orders
+  );
   const { flags } = useAppSelector((state) => state.featureFlags);
   const [selectedProducts, setSelectedProducts] = useState<AISuggestedProduct[]>([]);
   const [step, setStep] = useState<"form" | "suggestions">("form");
@@ -59,6 +61,7 @@ export const CreateOrderDialog: React.FC<CreateOrderDialogProps> = ({ onClose })
         delivery_address: values.delivery_address || undefined,
         postal_code: values.postal_code || undefined,
         selected_products: selectedProducts.length > 0 ? selectedProducts : undefined,
+        suggestion_session_id: aiSuggestionSessionId,
       })
     );
     dispatch(clearAISuggestions());
@@ -188,6 +191,21 @@ export const CreateOrderDialog: React.FC<CreateOrderDialogProps> = ({ onClose })
                     <div className="space-y-2">
                       <h3 className="font-semibold">AI Suggested Products:</h3>
                       {loading && <p>Loading suggestions...</p>}
+                      {!loading && aiSuggestionWarnings.length > 0 && (
+                        <div className="space-y-1 rounded-lg border border-yellow-300 bg-yellow-50 p-3 text-sm">
+                          <p className="font-medium">Safety notes:</p>
+                          <ul className="list-disc pl-5">
+                            {aiSuggestionWarnings.map((warning, index) => (
+                              <li
+                                key={index}
+                                className={warning.severity === "critical" ? "text-red-700" : undefined}
+                              >
+                                {warning.message}
+                              </li>
+                            ))}
+                          </ul>
+                        </div>
+                      )}
                       {!loading && aiSuggestions.length === 0 && (
                         <p className="text-muted-foreground">
                           No pharmacy products found in your request.
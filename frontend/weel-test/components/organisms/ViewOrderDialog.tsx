@@ -4,7 +4,7 @@ import React, { useState } from "react";
 import { Order, AISuggestedProduct } from "@/types";
 import { Button } from "../atoms/Button";
 import { Badge } from "../atoms/Badge";
-import { useAppDispatch } from "@/lib/redux/hooks";
+import { useAppDispatch, useAppSelector } from "@/lib/redux/hooks";
 import { updateOrderRequest } from "@/lib/redux/slices/ordersSlice";
 import { X } from "lucide-react";
 
@@ -15,6 +15,8 @@ interface ViewOrderDialogProps {
 
 export const ViewOrderDialog: React.FC<ViewOrderDialogProps> = ({ order, onClose }) => {
   const dispatch = useAppDispatch();
+  const { user } = useAppSelector((state) => state.auth);
+  const isStaff = user?.role === "pharmacist" || user?.role === "admin";
   const [selectedStatus, setSelectedStatus] = useState(order.status);
   const [isEditing, setIsEditing] = useState(false);
 
@@ -103,8 +105,8 @@ export const ViewOrderDialog: React.FC<ViewOrderDialogProps> = ({ order, onClose
                     className="flex h-9 rounded-md border border-gray-300 bg-white px-3 py-1 text-sm text-gray-900"
                   >
                     <option value="pending">Pending</option>
-                    <option value="processing">Processing</option>
-                    <option value="completed">Completed</option>
+                    {(isStaff || order.status === "processing") && <option value="processing">Processing</option>}
+                    {(isStaff || order.status === "completed") && <option value="completed">Completed</option>}
                     <option value="cancelled">Cancelled</option>
                   </select>
                   <Button size="sm" onClick={handleUpdateStatus}>
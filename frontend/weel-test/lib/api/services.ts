@@ -8,8 +8,10 @@ import {
   GetAISuggestionsResponse,
   CreateOrderRequest,
   UpdateOrderRequest,
+  CancelOrderRequest,
   Order,
   FeatureFlagsResponse,
+  PaginatedResponse,
 } from "@/types";
 
 // Auth API
@@ -35,10 +37,14 @@ export const ordersAPI = {
   getOrders: (params?: {
     status?: string;
     delivery_preference?: string;
-    sort_by?: string;
-    sort_order?: string;
+    created_after?: string;
+    created_before?: string;
+    postal_code?: string;
+    q?: string;
+    sort?: string;
+    cursor?: string;
     limit?: number;
-    offset?: number;
+    include_total?: boolean;
   }) => {
     const queryParams = new URLSearchParams();
     if (params) {
@@ -49,7 +55,7 @@ export const ordersAPI = {
       });
     }
     const queryString = queryParams.toString();
-    return apiClient.get<{ orders: Order[]; count: number }>(
+    return apiClient.get<PaginatedResponse<Order>>(
       `/orders${queryString ? `?${queryString}` : ""}`
     );
   },
@@ -59,6 +65,9 @@ export const ordersAPI = {
 
   updateOrder: (id: number, data: UpdateOrderRequest) =>
     apiClient.put<Order>(`/orders/${id}`, data),
+
+  cancelOrder: (id: number, data: CancelOrderRequest) =>
+    apiClient.post<Order>(`/orders/${id}/cancel`, data),
 };
 
 // Feature Flags API
@@ -21,12 +21,13 @@ import {
   GetAISuggestionsRequest,
   GetAISuggestionsResponse,
   CreateOrderRequest,
+  PaginatedResponse,
 } from "@/types";
 
 function* handleFetchOrders() {
   try {
-    const response: { orders: Order[]; count: number } = yield call(ordersAPI.getOrders);
-    yield put(fetchOrdersSuccess(response.orders));
+    const response: PaginatedResponse<Order> = yield call(ordersAPI.getOrders, { limit: 100 });
+    yield put(fetchOrdersSuccess(response.data));
     yield put(calculateStats());
   } catch (error: any) {
     yield put(fetchOrdersFailure(error.response?.data?.error || "Failed to fetch orders"));
@@ -39,7 +40,13 @@ function* handleGetAISuggestions(action: PayloadAction<GetAISuggestionsRequest>)
       ordersAPI.getAISuggestions,
       action.payload
     );
-    yield put(getAISuggestionsSuccess(response.suggestions));
+    yield put(
+      getAISuggestionsSuccess({
+        suggestions: response.suggestions,
+        sessionId: response.session_id,
+        warnings: response.warnings,
+      })
+    );
   } catch (error: any) {
     yield put(getAISuggestionsFailure(error.response?.data?.error || "Failed to get AI suggestions"));
   }
@@ -57,9 +64,12 @@ function* handleCreateOrder(action: PayloadAction<CreateOrderRequest>) {
 
 function* handleUpdateOrder(action: PayloadAction<{ id: number; status: string }>) {
   try {
-    const order: Order = yield call(ordersAPI.updateOrder, action.payload.id, {
-      status: action.payload.status as any,
-    });
+    const order: Order =
+      action.payload.status === "cancelled"
+        ? yield call(ordersAPI.cancelOrder, action.payload.id, { reason_code: "other" })
+        : yield call(ordersAPI.updateOrder, action.payload.id, {
+            status: action.payload.status as any,
+          });
     yield put(updateOrderSuccess(order));
     yield put(calculateStats());
   } catch (error: any) {
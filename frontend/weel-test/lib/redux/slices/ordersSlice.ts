@@ -5,12 +5,15 @@ import {
   GetAISuggestionsRequest,
   CreateOrderRequest,
   OrderStats,
+  SuggestionWarning,
 } from "@/types";
 
 interface OrdersState {
   orders: Order[];
   selectedOrder: Order | null;
   aiSuggestions: AISuggestedProduct[];
+  aiSuggestionSessionId?: number;
+  aiSuggestionWarnings: SuggestionWarning[];
   stats: OrderStats | null;
   loading: boolean;
   error: string | null;
@@ -27,6 +30,7 @@ const initialState: OrdersState = {
   orders: [],
   selectedOrder: null,
   aiSuggestions: [],
+  aiSuggestionWarnings: [],
   stats: null,
   loading: false,
   error: null,
@@ -59,8 +63,17 @@ const ordersSlice = createSlice({
       state.loading = true;
       state.error = null;
     },
-    getAISuggestionsSuccess: (state, action: PayloadAction<AISuggestedProduct[]>) => {
-      state.aiSuggestions = action.payload;
+    getAISuggestionsSuccess: (
+      state,
+      action: PayloadAction<{
+        suggestions: AISuggestedProduct[];
+        sessionId?: number;
+        warnings?: SuggestionWarning[];
+      }>
+    ) => {
+      state.aiSuggestions = action.payload.suggestions;
+      state.aiSuggestionSessionId = action.payload.sessionId;
+      state.aiSuggestionWarnings = action.payload.warnings ?? [];
       state.loading = false;
       state.error = null;
     },
@@ -77,6 +90,7 @@ const ordersSlice = createSlice({
     createOrderSuccess: (state, action: PayloadAction<Order>) => {
       state.orders.unshift(action.payload);
       state.aiSuggestions = [];
+      state.aiSuggestionWarnings = [];
       state.loading = false;
       state.error = null;
     },
@@ -164,6 +178,8 @@ const ordersSlice = createSlice({
 
     clearAISuggestions: (state) => {
       state.aiSuggestions = [];
+      state.aiSuggestionSessionId = undefined;
+      state.aiSuggestionWarnings = [];
     },
   },
 });
@@ -1,16 +1,91 @@
 // User Types
+export type UserRole = "customer" | "pharmacist" | "admin";
+
 export interface User {
   id: number;
   email: string;
   first_name: string;
   last_name: string;
+  role: UserRole;
+  store_id?: number;
   created_at: string;
   updated_at: string;
 }
 
+// Address Types
+export interface PostalAddress {
+  line1: string;
+  line2?: string;
+  city: string;
+  region?: string;
+  postal_code: string;
+  country: string; // ISO 3166-1 alpha-2
+  latitude?: number;
+  longitude?: number;
+}
+
+export interface Address extends PostalAddress {
+  id: number;
+  user_id: number;
+  label?: string;
+  is_default: boolean;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface AddressSnapshot extends PostalAddress {
+  label?: string;
+}
+
+// Delivery Types
+export interface DeliveryQuote {
+  deliverable: boolean;
+  postal_code: string;
+  zone_id?: number;
+  zone_name?: string;
+  fee: number;
+  min_order_amount: number;
+  meets_minimum?: boolean;
+}
+
+export interface DailyHours {
+  weekday: number;
+  open: string;
+  close: string;
+}
+
+export interface Store {
+  id: number;
+  name: string;
+  phone?: string;
+  address?: AddressSnapshot;
+  timezone: string;
+  hours: DailyHours[];
+  delivery_preferences: DeliveryPreference[];
+  active: boolean;
+}
+
+export interface InventoryItem {
+  id: number;
+  store_id: number;
+  sku: string;
+  name: string;
+  quantity: number;
+  price: number;
+  updated_at: string;
+}
+
+export interface PickupSlot {
+  starts_at: string;
+  ends_at: string;
+  capacity: number;
+  remaining: number;
+}
+
 // Order Types
 export type OrderStatus = "pending" | "processing" | "completed" | "cancelled";
 export type DeliveryPreference = "IN_STORE" | "DELIVERY" | "CURBSIDE";
+export type PrescriptionStatus = "required" | "submitted" | "approved" | "rejected";
 
 export interface AISuggestedProduct {
   name: string;
@@ -26,8 +101,24 @@ export interface Order {
   delivery_preference: DeliveryPreference;
   delivery_address?: string;
   postal_code?: string;
+  address_id?: number;
+  shipping_address?: AddressSnapshot;
+  delivery_zone_id?: number;
+  delivery_fee: number;
+  store_id?: number;
+  pickup_slot_id?: number;
+  pickup_slot_start?: string;
+  pickup_slot_end?: string;
+  customer_arrived_at?: string;
+  arrival_note?: string;
   ai_suggested_products?: string; // JSON string
+  suggestion_session_id?: number;
+  total: number;
   status: OrderStatus;
+  prescription_status?: PrescriptionStatus;
+  cancellation_reason?: CancellationReason;
+  cancellation_note?: string;
+  cancelled_at?: string;
   created_at: string;
   updated_at: string;
 }
@@ -70,9 +161,46 @@ export interface GetAISuggestionsRequest {
   delivery_address?: string;
 }
 
+export interface AIQuotaStatus {
+  limit: number;
+  used: number;
+  remaining: number;
+  custom: boolean;
+  resets_at: string;
+}
+
+export type WarningSeverity = "info" | "warning" | "critical";
+
+export interface SuggestionWarning {
+  code: string;
+  severity: WarningSeverity;
+  action: "flagged" | "blocked" | "adjusted";
+  product?: string;
+  products?: string[];
+  message: string;
+}
+
 export interface GetAISuggestionsResponse {
+  session_id?: number;
   suggestions: AISuggestedProduct[];
   count: number;
+  quota?: AIQuotaStatus;
+  warnings: SuggestionWarning[];
+  requires_review: boolean;
+}
+
+export interface Pagination {
+  next_cursor: string | null;
+  prev_cursor: string | null;
+  has_more: boolean;
+  limit: number;
+  total?: number;
+}
+
+export interface PaginatedResponse<T> {
+  data: T[];
+  count: number;
+  pagination: Pagination;
 }
 
 export interface CreateOrderRequest {
@@ -80,13 +208,30 @@ export interface CreateOrderRequest {
   delivery_preference: DeliveryPreference;
   delivery_address?: string;
   postal_code?: string;
+  address_id?: number;
+  address?: PostalAddress;
   selected_products?: AISuggestedProduct[];
+  prescription_required?: boolean;
+  suggestion_session_id?: number;
 }
 
 export interface UpdateOrderRequest {
   status?: OrderStatus;
 }
 
+export type CancellationReason =
+  | "changed_mind"
+  | "ordered_by_mistake"
+  | "duplicate_order"
+  | "found_elsewhere"
+  | "too_slow"
+  | "other";
+
+export interface CancelOrderRequest {
+  reason_code: CancellationReason;
+  note?: string;
+}
+
 // Order Statistics
 export interface OrderStats {
   total: number;